@@ -73,6 +73,10 @@ type Interface interface {
 	// that response is returned.  An error is returned if this device has been closed or
 	// if there were any I/O issues sending the request.
 	//
+	// If the device answers with a multi-part response and Request.AcceptParts is set, this method
+	// returns as soon as the first part arrives and the remaining parts are delivered on the returned
+	// Response's Parts channel.  Otherwise, the remaining parts are discarded before this method returns.
+	//
 	// Internally, the requests passed to this method are serviced by the write pump in
 	// the enclosing Manager instance.  The read pump will handle sending the response.
 	Send(*Request) (*Response, error)
@@ -99,13 +103,17 @@ type device struct {
 	transactions *Transactions
 
 	conveyClosure conveymetric.Closure
+
+	// partnerIDs are the partners this device belongs to, as determined when it connected
+	partnerIDs []string
 }
 
 type deviceOptions struct {
-	ID          ID
-	QueueSize   int
-	ConnectedAt time.Time
-	Logger      log.Logger
+	ID              ID
+	QueueSize       int
+	MaxPendingParts int
+	ConnectedAt     time.Time
+	Logger          log.Logger
 }
 
 // newDevice is an internal factory function for devices
@@ -131,7 +139,7 @@ func newDevice(o deviceOptions) *device {
 		state:        stateOpen,
 		shutdown:     make(chan struct{}),
 		messages:     make(chan *envelope, o.QueueSize),
-		transactions: NewTransactions(),
+		transactions: newTransactions(o.MaxPendingParts),
 	}
 }
 
@@ -228,6 +236,45 @@ func (d *device) awaitResponse(request *Request, result <-chan *Response) (*Resp
 	}
 }
 
+// streamParts delivers the remaining parts of a multi-part response.  The transaction is cancelled once the
+// last part has been delivered or the request can no longer receive parts.
+func (d *device) streamParts(request *Request, transactionKey string, result <-chan *Response) <-chan *Response {
+	parts := make(chan *Response)
+	go func() {
+		defer close(parts)
+		defer d.transactions.Cancel(transactionKey)
+
+		for part := range result {
+			select {
+			case <-request.Context().Done():
+				return
+			case <-d.shutdown:
+				return
+			case parts <- part:
+			}
+		}
+	}()
+
+	return parts
+}
+
+// discardParts consumes the remaining parts of a multi-part response for a request that did not set AcceptParts,
+// so that those parts are not mistaken for unsolicited messages from the device.
+func (d *device) discardParts(request *Request, parts <-chan *Response) {
+	for {
+		select {
+		case <-request.Context().Done():
+			return
+		case <-d.shutdown:
+			return
+		case _, ok := <-parts:
+			if !ok {
+				return
+			}
+		}
+	}
+}
+
 func (d *device) Send(request *Request) (*Response, error) {
 	if d.Closed() {
 		return nil, ErrorDeviceClosed
@@ -245,12 +292,13 @@ func (d *device) Send(request *Request) (*Response, error) {
 			// this indicates some larger problem, most often a duplicate transaction key.
 			return nil, err
 		}
-
-		// ensure that the transaction is cleared
-		defer d.transactions.Cancel(transactionKey)
 	}
 
 	if err := d.sendRequest(request); err != nil {
+		if transactional {
+			d.transactions.Cancel(transactionKey)
+		}
+
 		return nil, err
 	}
 
@@ -259,7 +307,21 @@ func (d *device) Send(request *Request) (*Response, error) {
 		return nil, nil
 	}
 
-	return d.awaitResponse(request, result)
+	response, err := d.awaitResponse(request, result)
+	if err == nil && response.Parts != nil {
+		if request.AcceptParts {
+			// the transaction remains pending until the remaining parts have been delivered
+			response.Parts = d.streamParts(request, transactionKey, response.Parts)
+			return response, nil
+		}
+
+		d.discardParts(request, response.Parts)
+		response.Parts = nil
+	}
+
+	// ensure that the transaction is cleared
+	d.transactions.Cancel(transactionKey)
+	return response, err
 }
 
 func (d *device) Statistics() Statistics {
@@ -18,6 +18,8 @@ import (
 var (
 	ErrActive    error = errors.New("A drain operation is already running")
 	ErrNotActive error = errors.New("No drain operation is running")
+	ErrPaused    error = errors.New("The drain operation is already paused")
+	ErrNotPaused error = errors.New("The drain operation is not paused")
 )
 
 const (
@@ -26,6 +28,11 @@ const (
 
 	MetricNotDraining float64 = 0.0
 	MetricDraining    float64 = 1.0
+	MetricPaused      float64 = 2.0
+	MetricScheduled   float64 = 3.0
+
+	// DefaultHistorySize is the number of finished drain jobs retained when no history size is configured
+	DefaultHistorySize int = 10
 
 	// disconnectBatchSize is the arbitrary size of batches used when no rate is associated with the drain,
 	// i.e. disconnect as fast as possible
@@ -95,6 +102,16 @@ func WithDrainCounter(a xmetrics.Adder) Option {
 	}
 }
 
+func WithHistorySize(n int) Option {
+	return func(dr *drainer) {
+		if n > 0 {
+			dr.historySize = n
+		} else {
+			dr.historySize = DefaultHistorySize
+		}
+	}
+}
+
 type Job struct {
 	// Count is the total number of devices to disconnect.  If this field is nonpositive and percent is unset,
 	// the count of connected devices at the start of job execution is used.  If Percent is set, this field's
@@ -113,6 +130,22 @@ type Job struct {
 	// Tick is the time unit for the Rate field.  If Rate is set but this field is not set,
 	// a tick of 1 second is used as the default.
 	Tick time.Duration `json:"tick,omitempty" schema:"tick"`
+
+	// StartAt is the time at which the job should begin draining devices.  If this field is unset or
+	// is not in the future, the job starts immediately.  A scheduled job occupies the drainer just as
+	// a running job does, and can be paused or cancelled before it begins.
+	StartAt time.Time `json:"startAt,omitempty" schema:"startAt"`
+
+	// Target, when positive, switches the job into drain-to-target mode.  Rather than disconnecting a fixed
+	// number of devices, the job disconnects devices at the configured Rate until the number of connected
+	// devices is at or below this value.  Count and Percent are ignored in this mode, and Count is set to the
+	// number of excess devices at the time the job starts.  If Rate is unset, all excess devices are disconnected
+	// on each tick.
+	Target int `json:"target,omitempty" schema:"target"`
+
+	// Enforce applies only to drain-to-target mode.  If set, the job does not finish when the target is reached.
+	// Instead, it continues to disconnect devices above the target, acting as a soft cap, until cancelled.
+	Enforce bool `json:"enforce,omitempty" schema:"enforce"`
 }
 
 // ToMap returns a map representation of this Job appropriate for marshaling to formats like JSON.
@@ -134,11 +167,28 @@ func (j Job) ToMap() map[string]interface{} {
 		m["tick"] = j.Tick.String()
 	}
 
+	if !j.StartAt.IsZero() {
+		m["startAt"] = j.StartAt.Format(time.RFC3339)
+	}
+
+	if j.Target > 0 {
+		m["target"] = j.Target
+		if j.Enforce {
+			m["enforce"] = true
+		}
+	}
+
 	return m
 }
 
 // normalize applies some basic logic to interpret defaults and set values appropriately for a given device count
 func (j *Job) normalize(deviceCount int) {
+	if j.Target > 0 {
+		j.normalizeTarget(deviceCount)
+		return
+	}
+
+	j.Enforce = false
 	if j.Percent > 0 {
 		j.Count = int((float64(deviceCount) / 100.0) * float64(j.Percent))
 	} else if j.Count <= 0 {
@@ -155,6 +205,23 @@ func (j *Job) normalize(deviceCount int) {
 	}
 }
 
+// normalizeTarget handles normalization for drain-to-target jobs, which always run on a tick
+func (j *Job) normalizeTarget(deviceCount int) {
+	j.Percent = 0
+	j.Count = deviceCount - j.Target
+	if j.Count < 0 {
+		j.Count = 0
+	}
+
+	if j.Rate < 0 {
+		j.Rate = 0
+	}
+
+	if j.Tick <= 0 {
+		j.Tick = time.Second
+	}
+}
+
 // Interface describes the behavior of a component which can execute a Job to drain devices.
 // Only (1) drain Job is allowed to run at any time.
 type Interface interface {
@@ -173,6 +240,37 @@ type Interface interface {
 	// Cancel asynchronously halts any running drain job.  The returned channel can be used to wait for the job to actually exit.
 	// If no job is running, an error is returned along with a nil channel.
 	Cancel() (<-chan struct{}, error)
+
+	// Pause suspends the current drain job.  No devices will be disconnected until Resume is called.
+	// A scheduled job may be paused before it begins, in which case it will wait for Resume once its start time arrives.
+	Pause() error
+
+	// Resume continues a paused drain job.
+	Resume() error
+
+	// History returns records of the most recent drain jobs that have finished or were cancelled, oldest first.
+	History() []Record
+}
+
+// Record describes a drain job that is no longer active
+type Record struct {
+	// ID is the drainer-assigned identifier of the job
+	ID uint32 `json:"id"`
+
+	// Job is the actual Job that was executed
+	Job Job `json:"job"`
+
+	// Progress is the final progress of the job
+	Progress Progress `json:"progress"`
+}
+
+// ToMap returns a map representation of this Record appropriate for marshaling to formats like JSON.
+func (r Record) ToMap() map[string]interface{} {
+	return map[string]interface{}{
+		"id":       r.ID,
+		"job":      r.Job.ToMap(),
+		"progress": r.Progress,
+	}
 }
 
 func defaultNewTicker(d time.Duration) (<-chan time.Time, func()) {
@@ -180,12 +278,19 @@ func defaultNewTicker(d time.Duration) (<-chan time.Time, func()) {
 	return ticker.C, ticker.Stop
 }
 
+func defaultNewTimer(d time.Duration) (<-chan time.Time, func() bool) {
+	timer := time.NewTimer(d)
+	return timer.C, timer.Stop
+}
+
 // New constructs a drainer using the supplied options
 func New(options ...Option) Interface {
 	dr := &drainer{
-		logger:    logging.DefaultLogger(),
-		now:       time.Now,
-		newTicker: defaultNewTicker,
+		logger:      logging.DefaultLogger(),
+		now:         time.Now,
+		newTicker:   defaultNewTicker,
+		newTimer:    defaultNewTimer,
+		historySize: DefaultHistorySize,
 		m: metrics{
 			state:   discard.NewGauge(),
 			counter: discard.NewCounter(),
@@ -218,8 +323,12 @@ type jobContext struct {
 	id        uint32
 	logger    log.Logger
 	t         *tracker
+	g         *gate
+	requested Job
 	j         Job
 	batchSize int
+	timer     <-chan time.Time
+	stopTimer func() bool
 	ticker    <-chan time.Time
 	stop      func()
 	cancel    chan struct{}
@@ -233,12 +342,16 @@ type drainer struct {
 	registry  device.Registry
 	now       func() time.Time
 	newTicker func(time.Duration) (<-chan time.Time, func())
+	newTimer  func(time.Duration) (<-chan time.Time, func() bool)
 	m         metrics
 
 	controlLock sync.RWMutex
 	active      uint32
 	currentID   uint32
 	current     atomic.Value
+
+	historySize int
+	history     []Record
 }
 
 // nextBatch grabs a batch of devices, bounded by the size of the supplied batch channel, and attempts
@@ -301,6 +414,10 @@ func (dr *drainer) jobFinished(jc jobContext) {
 		jc.stop()
 	}
 
+	if jc.stopTimer != nil {
+		jc.stopTimer()
+	}
+
 	jc.t.done(dr.now().UTC())
 
 	// we need to contend on the control lock to avoid clobbering state from Start/Cancel code
@@ -309,6 +426,18 @@ func (dr *drainer) jobFinished(jc jobContext) {
 		dr.m.state.Set(MetricNotDraining)
 	}
 
+	r := Record{ID: jc.id, Job: jc.j, Progress: jc.t.Progress()}
+	select {
+	case <-jc.cancel:
+		r.Progress.Cancelled = true
+	default:
+	}
+
+	dr.history = append(dr.history, r)
+	if len(dr.history) > dr.historySize {
+		dr.history = append([]Record(nil), dr.history[len(dr.history)-dr.historySize:]...)
+	}
+
 	dr.controlLock.Unlock()
 
 	// only close the done channel when all cleanup is complete
@@ -337,8 +466,10 @@ func (dr *drainer) drain(jc jobContext) {
 
 		select {
 		case <-jc.ticker:
-			more, visited = dr.nextBatch(jc, batch)
-			remaining -= visited
+			if more = dr.waitIfPaused(jc); more {
+				more, visited = dr.nextBatch(jc, batch)
+				remaining -= visited
+			}
 		case <-jc.cancel:
 			jc.logger.Log(level.Key(), level.ErrorValue(), logging.MessageKey(), "job cancelled")
 			more = false
@@ -363,12 +494,127 @@ func (dr *drainer) disconnect(jc jobContext) {
 			batch = make(chan device.ID, remaining)
 		}
 
-		more, visited = dr.nextBatch(jc, batch)
-		remaining -= visited
+		if more = dr.waitIfPaused(jc); more {
+			more, visited = dr.nextBatch(jc, batch)
+			remaining -= visited
+		}
 	}
 }
 
+// drainToTarget is run as a goroutine to disconnect devices until the number of connected devices
+// is at or below the job's target.  If the job enforces its target, this method continues to
+// disconnect excess devices on each tick until cancelled.
+func (dr *drainer) drainToTarget(jc jobContext) {
+	defer dr.jobFinished(jc)
+	jc.logger.Log(level.Key(), level.InfoValue(), logging.MessageKey(), "drain to target starting", "target", jc.j.Target, "enforce", jc.j.Enforce, "rate", jc.j.Rate, "tick", jc.j.Tick)
+
+	for {
+		select {
+		case <-jc.ticker:
+			if !dr.waitIfPaused(jc) {
+				return
+			}
+
+			connected := dr.registry.Len()
+			jc.t.setConnected(connected)
+
+			excess := connected - jc.j.Target
+			if excess <= 0 {
+				if jc.t.reachTarget(dr.now().UTC()) {
+					jc.logger.Log(level.Key(), level.InfoValue(), logging.MessageKey(), "target reached", "connected", connected)
+				}
+
+				if !jc.j.Enforce {
+					return
+				}
+
+				continue
+			}
+
+			if jc.j.Rate > 0 && excess > jc.j.Rate {
+				excess = jc.j.Rate
+			}
+
+			if more, _ := dr.nextBatch(jc, make(chan device.ID, excess)); !more {
+				select {
+				case <-jc.cancel:
+					return
+				default:
+				}
+			}
+
+		case <-jc.cancel:
+			jc.logger.Log(level.Key(), level.ErrorValue(), logging.MessageKey(), "job cancelled")
+			return
+		}
+	}
+}
+
+// waitIfPaused blocks while the given job is paused.  If the job is cancelled while waiting,
+// this method returns false.
+func (dr *drainer) waitIfPaused(jc jobContext) bool {
+	if !jc.g.isPaused() {
+		return true
+	}
+
+	jc.logger.Log(level.Key(), level.InfoValue(), logging.MessageKey(), "job paused")
+	if !jc.g.wait(jc.cancel) {
+		jc.logger.Log(level.Key(), level.ErrorValue(), logging.MessageKey(), "job cancelled")
+		return false
+	}
+
+	jc.logger.Log(level.Key(), level.InfoValue(), logging.MessageKey(), "job resumed")
+	return true
+}
+
+// begin initializes the runtime state for a job that is about to start draining devices,
+// then dispatches to the appropriate drain strategy.  This method must be invoked as a goroutine.
+func (dr *drainer) begin(jc jobContext) {
+	if jc.j.Target > 0 {
+		jc.ticker, jc.stop = dr.newTicker(jc.j.Tick)
+		dr.drainToTarget(jc)
+	} else if jc.j.Rate > 0 {
+		jc.ticker, jc.stop = dr.newTicker(jc.j.Tick)
+		dr.drain(jc)
+	} else {
+		jc.batchSize = disconnectBatchSize
+		dr.disconnect(jc)
+	}
+}
+
+// schedule is run as a goroutine to wait for a scheduled job's start time.  Once that time arrives,
+// the job is normalized against the devices connected at that moment and draining begins.
+func (dr *drainer) schedule(jc jobContext) {
+	jc.logger.Log(level.Key(), level.InfoValue(), logging.MessageKey(), "drain scheduled", "startAt", jc.j.StartAt)
+
+	select {
+	case <-jc.timer:
+	case <-jc.cancel:
+		jc.logger.Log(level.Key(), level.ErrorValue(), logging.MessageKey(), "job cancelled")
+		dr.jobFinished(jc)
+		return
+	}
+
+	jc.j = jc.requested
+	jc.j.normalize(dr.registry.Len())
+	jc.t.start(dr.now().UTC())
+
+	dr.controlLock.Lock()
+	if jc.id == dr.currentID && atomic.LoadUint32(&dr.active) == StateActive {
+		dr.current.Store(jc)
+		if jc.g.isPaused() {
+			dr.m.state.Set(MetricPaused)
+		} else {
+			dr.m.state.Set(MetricDraining)
+		}
+	}
+
+	dr.controlLock.Unlock()
+	dr.begin(jc)
+}
+
 func (dr *drainer) Start(j Job) (<-chan struct{}, Job, error) {
+	requested := j
 	j.normalize(dr.registry.Len())
 
 	defer dr.controlLock.Unlock()
@@ -383,23 +629,30 @@ func (dr *drainer) Start(j Job) (<-chan struct{}, Job, error) {
 		id:     dr.currentID,
 		logger: log.With(dr.logger, "id", dr.currentID),
 		t: &tracker{
-			started: dr.now().UTC(),
 			counter: dr.m.counter,
 		},
-		j:      j,
-		cancel: make(chan struct{}),
-		done:   make(chan struct{}),
+		g:         new(gate),
+		requested: requested,
+		j:         j,
+		cancel:    make(chan struct{}),
+		done:      make(chan struct{}),
+	}
+
+	var delay time.Duration
+	if !j.StartAt.IsZero() {
+		delay = j.StartAt.Sub(dr.now())
 	}
 
-	if jc.j.Rate > 0 {
-		jc.ticker, jc.stop = dr.newTicker(j.Tick)
-		go dr.drain(jc)
+	if delay > 0 {
+		jc.timer, jc.stopTimer = dr.newTimer(delay)
+		go dr.schedule(jc)
+		dr.m.state.Set(MetricScheduled)
 	} else {
-		jc.batchSize = disconnectBatchSize
-		go dr.disconnect(jc)
+		jc.t.start(dr.now().UTC())
+		go dr.begin(jc)
+		dr.m.state.Set(MetricDraining)
 	}
 
-	dr.m.state.Set(MetricDraining)
 	dr.current.Store(jc)
 	return jc.done, jc.j, nil
 }
@@ -409,9 +662,17 @@ func (dr *drainer) Status() (bool, Job, Progress) {
 	dr.controlLock.RLock()
 
 	if jc, ok := dr.current.Load().(jobContext); ok {
+		p := jc.t.Progress()
+		p.Paused = jc.g.isPaused()
+		select {
+		case <-jc.cancel:
+			p.Cancelled = true
+		default:
+		}
+
 		return atomic.LoadUint32(&dr.active) == StateActive,
 			jc.j,
-			jc.t.Progress()
+			p
 	}
 
 	// if the job has never run, this result will be returned
@@ -431,3 +692,53 @@ func (dr *drainer) Cancel() (<-chan struct{}, error) {
 	close(jc.cancel)
 	return jc.done, nil
 }
+
+func (dr *drainer) Pause() error {
+	defer dr.controlLock.Unlock()
+	dr.controlLock.Lock()
+
+	if atomic.LoadUint32(&dr.active) != StateActive {
+		return ErrNotActive
+	}
+
+	jc := dr.current.Load().(jobContext)
+	if !jc.g.pause() {
+		return ErrPaused
+	}
+
+	jc.logger.Log(level.Key(), level.InfoValue(), logging.MessageKey(), "pausing job")
+	dr.m.state.Set(MetricPaused)
+	return nil
+}
+
+func (dr *drainer) Resume() error {
+	defer dr.controlLock.Unlock()
+	dr.controlLock.Lock()
+
+	if atomic.LoadUint32(&dr.active) != StateActive {
+		return ErrNotActive
+	}
+
+	jc := dr.current.Load().(jobContext)
+	if !jc.g.unpause() {
+		return ErrNotPaused
+	}
+
+	jc.logger.Log(level.Key(), level.InfoValue(), logging.MessageKey(), "resuming job")
+	if jc.t.Progress().Started.IsZero() {
+		dr.m.state.Set(MetricScheduled)
+	} else {
+		dr.m.state.Set(MetricDraining)
+	}
+
+	return nil
+}
+
+func (dr *drainer) History() []Record {
+	defer dr.controlLock.RUnlock()
+	dr.controlLock.RLock()
+
+	history := make([]Record, len(dr.history))
+	copy(history, dr.history)
+	return history
+}
@@ -25,6 +25,10 @@ func testJobNormalize(t *testing.T) {
 		{0, Job{Percent: 0}, Job{Count: 0}},
 		{123752, Job{Percent: 17}, Job{Count: 21037, Percent: 17}},
 		{73, Job{Percent: 100}, Job{Count: 73, Percent: 100}},
+		{1000, Job{Count: 500, Enforce: true}, Job{Count: 500}},
+		{1000, Job{Target: 800}, Job{Count: 200, Target: 800, Tick: time.Second}},
+		{1000, Job{Count: 12, Percent: 50, Target: 1500, Rate: 10, Enforce: true}, Job{Count: 0, Target: 1500, Rate: 10, Tick: time.Second, Enforce: true}},
+		{1000, Job{Target: 10, Rate: -1, Tick: time.Minute}, Job{Count: 990, Target: 10, Tick: time.Minute}},
 	}
 
 	for i, record := range testData {
@@ -40,8 +44,31 @@ func testJobNormalize(t *testing.T) {
 	}
 }
 
+func testJobToMap(t *testing.T) {
+	var (
+		startAt  = time.Date(2018, 4, 5, 12, 0, 0, 0, time.UTC)
+		testData = []struct {
+			job      Job
+			expected map[string]interface{}
+		}{
+			{Job{}, map[string]interface{}{"count": 0}},
+			{Job{Count: 10, Percent: 5, Rate: 2, Tick: time.Minute}, map[string]interface{}{"count": 10, "percent": 5, "rate": 2, "tick": "1m0s"}},
+			{Job{Count: 10, StartAt: startAt}, map[string]interface{}{"count": 10, "startAt": "2018-04-05T12:00:00Z"}},
+			{Job{Count: 10, Target: 50}, map[string]interface{}{"count": 10, "target": 50}},
+			{Job{Count: 10, Target: 50, Enforce: true}, map[string]interface{}{"count": 10, "target": 50, "enforce": true}},
+		}
+	)
+
+	for i, record := range testData {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			assert.Equal(t, record.expected, record.job.ToMap())
+		})
+	}
+}
+
 func TestJob(t *testing.T) {
 	t.Run("Normalize", testJobNormalize)
+	t.Run("ToMap", testJobToMap)
 }
 
 func testWithLoggerDefault(t *testing.T) {
@@ -191,6 +218,31 @@ func TestWithDrainCounter(t *testing.T) {
 	t.Run("Custom", testWithDrainCounterCustom)
 }
 
+func testWithHistorySizeDefault(t *testing.T) {
+	var (
+		assert = assert.New(t)
+		d      = new(drainer)
+	)
+
+	WithHistorySize(0)(d)
+	assert.Equal(DefaultHistorySize, d.historySize)
+}
+
+func testWithHistorySizeCustom(t *testing.T) {
+	var (
+		assert = assert.New(t)
+		d      = new(drainer)
+	)
+
+	WithHistorySize(37)(d)
+	assert.Equal(37, d.historySize)
+}
+
+func TestWithHistorySize(t *testing.T) {
+	t.Run("Default", testWithHistorySizeDefault)
+	t.Run("Custom", testWithHistorySizeCustom)
+}
+
 func testNewNoRegistry(t *testing.T) {
 	var (
 		assert  = assert.New(t)
@@ -582,6 +634,433 @@ func testDrainerDrainCancel(t *testing.T) {
 	assert.True(stopCalled)
 }
 
+func testDrainerPauseResume(t *testing.T) {
+	var (
+		assert   = assert.New(t)
+		require  = require.New(t)
+		provider = xmetricstest.NewProvider(nil)
+		logger   = logging.NewTestLogger(nil, t)
+
+		manager = generateManager(assert, 100)
+		ticker  = make(chan time.Time)
+
+		d = New(
+			WithLogger(logger),
+			WithManager(manager),
+			WithStateGauge(provider.NewGauge("state")),
+			WithDrainCounter(provider.NewCounter("counter")),
+		)
+	)
+
+	require.NotNil(d)
+	defer d.Cancel()
+
+	d.(*drainer).newTicker = func(time.Duration) (<-chan time.Time, func()) {
+		return ticker, func() {}
+	}
+
+	assert.Equal(ErrNotActive, d.Pause())
+	assert.Equal(ErrNotActive, d.Resume())
+
+	done, _, err := d.Start(Job{Rate: 50})
+	require.NoError(err)
+	require.NotNil(done)
+
+	assert.Equal(ErrNotPaused, d.Resume())
+	require.NoError(d.Pause())
+	assert.Equal(ErrPaused, d.Pause())
+	provider.Assert(t, "state")(xmetricstest.Value(MetricPaused))
+
+	active, _, progress := d.Status()
+	assert.True(active)
+	assert.True(progress.Paused)
+
+	close(manager.pauseVisit)
+	close(manager.pauseDisconnect)
+
+	// the job is paused, so this tick must not disconnect anything
+	ticker <- time.Time{}
+	provider.Assert(t, "counter")(xmetricstest.Value(0.0))
+
+	require.NoError(d.Resume())
+	provider.Assert(t, "state")(xmetricstest.Value(MetricDraining))
+	ticker <- time.Time{}
+
+	select {
+	case <-done:
+		// passing
+	case <-time.After(5 * time.Second):
+		assert.Fail("Drain failed to complete after being resumed")
+		return
+	}
+
+	provider.Assert(t, "state")(xmetricstest.Value(MetricNotDraining))
+	provider.Assert(t, "counter")(xmetricstest.Value(100.0))
+	assert.Empty(manager.devices)
+}
+
+func testDrainerPauseCancel(t *testing.T) {
+	var (
+		assert   = assert.New(t)
+		require  = require.New(t)
+		provider = xmetricstest.NewProvider(nil)
+		logger   = logging.NewTestLogger(nil, t)
+
+		manager = generateManager(assert, 100)
+		ticker  = make(chan time.Time)
+
+		d = New(
+			WithLogger(logger),
+			WithManager(manager),
+			WithStateGauge(provider.NewGauge("state")),
+			WithDrainCounter(provider.NewCounter("counter")),
+		)
+	)
+
+	require.NotNil(d)
+	defer d.Cancel()
+
+	d.(*drainer).newTicker = func(time.Duration) (<-chan time.Time, func()) {
+		return ticker, func() {}
+	}
+
+	done, _, err := d.Start(Job{Rate: 10})
+	require.NoError(err)
+	require.NotNil(done)
+	require.NoError(d.Pause())
+	ticker <- time.Time{}
+
+	_, err = d.Cancel()
+	require.NoError(err)
+
+	select {
+	case <-done:
+		// passing
+	case <-time.After(5 * time.Second):
+		assert.Fail("The paused job did not complete after being cancelled")
+		return
+	}
+
+	provider.Assert(t, "state")(xmetricstest.Value(MetricNotDraining))
+	provider.Assert(t, "counter")(xmetricstest.Value(0.0))
+
+	history := d.History()
+	require.Len(history, 1)
+	assert.True(history[0].Progress.Cancelled)
+	assert.Zero(history[0].Progress.Visited)
+}
+
+func testDrainerSchedule(t *testing.T) {
+	var (
+		assert   = assert.New(t)
+		require  = require.New(t)
+		provider = xmetricstest.NewProvider(nil)
+		logger   = logging.NewTestLogger(nil, t)
+
+		manager = generateManager(assert, 100)
+		now     = time.Now()
+		startAt = now.Add(time.Hour)
+		timer   = make(chan time.Time, 1)
+
+		d = New(
+			WithLogger(logger),
+			WithManager(manager),
+			WithStateGauge(provider.NewGauge("state")),
+			WithDrainCounter(provider.NewCounter("counter")),
+		)
+	)
+
+	require.NotNil(d)
+	defer d.Cancel()
+
+	d.(*drainer).now = func() time.Time { return now }
+	d.(*drainer).newTimer = func(delay time.Duration) (<-chan time.Time, func() bool) {
+		assert.Equal(time.Hour, delay)
+		return timer, func() bool { return true }
+	}
+
+	done, job, err := d.Start(Job{Percent: 50, StartAt: startAt})
+	require.NoError(err)
+	require.NotNil(done)
+	assert.Equal(Job{Count: 50, Percent: 50, StartAt: startAt}, job)
+	provider.Assert(t, "state")(xmetricstest.Value(MetricScheduled))
+
+	active, job, progress := d.Status()
+	assert.True(active)
+	assert.Equal(startAt, job.StartAt)
+	assert.True(progress.Started.IsZero())
+
+	// devices that disconnect before the job starts affect the computed count
+	for id := range manager.devices {
+		delete(manager.devices, id)
+		if len(manager.devices) == 80 {
+			break
+		}
+	}
+
+	timer <- now
+	close(manager.pauseVisit)
+	close(manager.pauseDisconnect)
+
+	select {
+	case <-done:
+		// passing
+	case <-time.After(5 * time.Second):
+		assert.Fail("The scheduled job did not complete")
+		return
+	}
+
+	provider.Assert(t, "state")(xmetricstest.Value(MetricNotDraining))
+	provider.Assert(t, "counter")(xmetricstest.Value(40.0))
+
+	active, job, progress = d.Status()
+	assert.False(active)
+	assert.Equal(Job{Count: 40, Percent: 50, StartAt: startAt}, job)
+	assert.Equal(now.UTC(), progress.Started)
+
+	history := d.History()
+	require.Len(history, 1)
+	assert.Equal(uint32(1), history[0].ID)
+	assert.Equal(job, history[0].Job)
+	assert.Equal(40, history[0].Progress.Drained)
+	assert.False(history[0].Progress.Cancelled)
+}
+
+func testDrainerScheduleCancel(t *testing.T) {
+	var (
+		assert   = assert.New(t)
+		require  = require.New(t)
+		provider = xmetricstest.NewProvider(nil)
+		logger   = logging.NewTestLogger(nil, t)
+
+		manager = generateManager(assert, 100)
+		now     = time.Now()
+
+		stopCalled = make(chan struct{})
+
+		d = New(
+			WithLogger(logger),
+			WithManager(manager),
+			WithStateGauge(provider.NewGauge("state")),
+			WithDrainCounter(provider.NewCounter("counter")),
+		)
+	)
+
+	require.NotNil(d)
+	defer d.Cancel()
+
+	d.(*drainer).now = func() time.Time { return now }
+	d.(*drainer).newTimer = func(time.Duration) (<-chan time.Time, func() bool) {
+		return make(chan time.Time), func() bool {
+			close(stopCalled)
+			return true
+		}
+	}
+
+	done, _, err := d.Start(Job{StartAt: now.Add(time.Minute)})
+	require.NoError(err)
+	require.NotNil(done)
+
+	_, err = d.Cancel()
+	require.NoError(err)
+
+	select {
+	case <-done:
+		// passing
+	case <-time.After(5 * time.Second):
+		assert.Fail("The scheduled job did not complete after being cancelled")
+		return
+	}
+
+	select {
+	case <-stopCalled:
+		// passing
+	default:
+		assert.Fail("The timer was not stopped")
+	}
+
+	provider.Assert(t, "state")(xmetricstest.Value(MetricNotDraining))
+	provider.Assert(t, "counter")(xmetricstest.Value(0.0))
+	assert.Len(manager.devices, 100)
+
+	history := d.History()
+	require.Len(history, 1)
+	assert.True(history[0].Progress.Cancelled)
+	assert.True(history[0].Progress.Started.IsZero())
+}
+
+func testDrainerHistory(t *testing.T) {
+	var (
+		assert  = assert.New(t)
+		require = require.New(t)
+		logger  = logging.NewTestLogger(nil, t)
+
+		manager = generateManager(assert, 0)
+
+		d = New(
+			WithLogger(logger),
+			WithManager(manager),
+			WithHistorySize(2),
+		)
+	)
+
+	require.NotNil(d)
+	assert.Empty(d.History())
+	close(manager.pauseVisit)
+
+	for i := 0; i < 3; i++ {
+		done, _, err := d.Start(Job{})
+		require.NoError(err)
+
+		select {
+		case <-done:
+		case <-time.After(5 * time.Second):
+			assert.Fail("Drain failed to complete")
+			return
+		}
+	}
+
+	history := d.History()
+	require.Len(history, 2)
+	assert.Equal(uint32(2), history[0].ID)
+	assert.Equal(uint32(3), history[1].ID)
+}
+
+func testDrainerDrainToTarget(t *testing.T) {
+	var (
+		assert   = assert.New(t)
+		require  = require.New(t)
+		provider = xmetricstest.NewProvider(nil)
+		logger   = logging.NewTestLogger(nil, t)
+
+		manager = generateManager(assert, 100)
+		now     = time.Now()
+		ticker  = make(chan time.Time, 2)
+
+		d = New(
+			WithLogger(logger),
+			WithManager(manager),
+			WithStateGauge(provider.NewGauge("state")),
+			WithDrainCounter(provider.NewCounter("counter")),
+		)
+	)
+
+	require.NotNil(d)
+	defer d.Cancel()
+
+	d.(*drainer).now = func() time.Time { return now }
+	d.(*drainer).newTicker = func(d time.Duration) (<-chan time.Time, func()) {
+		assert.Equal(time.Second, d)
+		return ticker, func() {}
+	}
+
+	done, job, err := d.Start(Job{Target: 95})
+	require.NoError(err)
+	require.NotNil(done)
+	assert.Equal(Job{Count: 5, Target: 95, Tick: time.Second}, job)
+
+	close(manager.pauseVisit)
+	close(manager.pauseDisconnect)
+	ticker <- time.Time{}
+	ticker <- time.Time{}
+
+	select {
+	case <-done:
+		// passing
+	case <-time.After(5 * time.Second):
+		assert.Fail("Drain to target failed to complete")
+		return
+	}
+
+	provider.Assert(t, "state")(xmetricstest.Value(MetricNotDraining))
+	provider.Assert(t, "counter")(xmetricstest.Value(5.0))
+	assert.Len(manager.devices, 95)
+
+	active, _, progress := d.Status()
+	assert.False(active)
+	assert.Equal(5, progress.Visited)
+	assert.Equal(5, progress.Drained)
+	assert.Equal(95, progress.Connected)
+	require.NotNil(progress.TargetReached)
+	assert.Equal(now.UTC(), *progress.TargetReached)
+	assert.False(progress.Cancelled)
+}
+
+func testDrainerDrainToTargetEnforce(t *testing.T) {
+	var (
+		assert   = assert.New(t)
+		require  = require.New(t)
+		provider = xmetricstest.NewProvider(nil)
+		logger   = logging.NewTestLogger(nil, t)
+
+		manager = generateManager(assert, 100)
+		ticker  = make(chan time.Time)
+
+		d = New(
+			WithLogger(logger),
+			WithManager(manager),
+			WithStateGauge(provider.NewGauge("state")),
+			WithDrainCounter(provider.NewCounter("counter")),
+		)
+	)
+
+	require.NotNil(d)
+	defer d.Cancel()
+
+	d.(*drainer).newTicker = func(time.Duration) (<-chan time.Time, func()) {
+		return ticker, func() {}
+	}
+
+	done, job, err := d.Start(Job{Target: 90, Rate: 5, Enforce: true})
+	require.NoError(err)
+	require.NotNil(done)
+	assert.Equal(Job{Count: 10, Target: 90, Rate: 5, Tick: time.Second, Enforce: true}, job)
+
+	close(manager.pauseVisit)
+	close(manager.pauseDisconnect)
+
+	// the ticker is unbuffered, so each send guarantees the previous tick has been processed
+	for i := 0; i < 4; i++ {
+		ticker <- time.Time{}
+	}
+
+	active, _, progress := d.Status()
+	assert.True(active)
+	assert.Equal(10, progress.Drained)
+	assert.NotNil(progress.TargetReached)
+
+	// devices that connect after the target is reached are disconnected
+	manager.addDevices(1000, 3)
+	ticker <- time.Time{}
+	ticker <- time.Time{}
+
+	_, err = d.Cancel()
+	require.NoError(err)
+
+	select {
+	case <-done:
+		// passing
+	case <-time.After(5 * time.Second):
+		assert.Fail("Drain to target failed to complete after being cancelled")
+		return
+	}
+
+	provider.Assert(t, "state")(xmetricstest.Value(MetricNotDraining))
+	provider.Assert(t, "counter")(xmetricstest.Value(13.0))
+	assert.Len(manager.devices, 90)
+
+	active, _, progress = d.Status()
+	assert.False(active)
+	assert.Equal(13, progress.Visited)
+	assert.Equal(13, progress.Drained)
+	assert.Equal(90, progress.Connected)
+	assert.True(progress.Cancelled)
+
+	history := d.History()
+	require.Len(history, 1)
+	assert.True(history[0].Progress.Cancelled)
+}
+
 func TestDrainer(t *testing.T) {
 	deviceCounts := []int{0, 1, 2, disconnectBatchSize - 1, disconnectBatchSize, disconnectBatchSize + 1, 1709}
 
@@ -604,4 +1083,11 @@ func TestDrainer(t *testing.T) {
 	t.Run("VisitCancel", testDrainerVisitCancel)
 	t.Run("DisconnectCancel", testDrainerDisconnectCancel)
 	t.Run("DrainCancel", testDrainerDrainCancel)
+	t.Run("PauseResume", testDrainerPauseResume)
+	t.Run("PauseCancel", testDrainerPauseCancel)
+	t.Run("Schedule", testDrainerSchedule)
+	t.Run("ScheduleCancel", testDrainerScheduleCancel)
+	t.Run("History", testDrainerHistory)
+	t.Run("DrainToTarget", testDrainerDrainToTarget)
+	t.Run("DrainToTargetEnforce", testDrainerDrainToTargetEnforce)
 }
@@ -0,0 +1,65 @@
+package drain
+
+import "sync"
+
+// gate controls whether a drain job is allowed to make progress.  A closed gate
+// causes the job to block until the gate is reopened or the job is cancelled.
+type gate struct {
+	lock   sync.Mutex
+	paused bool
+	resume chan struct{}
+}
+
+// pause closes this gate.  If the gate was already closed, this method returns false.
+func (g *gate) pause() bool {
+	defer g.lock.Unlock()
+	g.lock.Lock()
+
+	if g.paused {
+		return false
+	}
+
+	g.paused = true
+	g.resume = make(chan struct{})
+	return true
+}
+
+// unpause reopens this gate, releasing any goroutine blocked in wait.  If the gate
+// was not closed, this method returns false.
+func (g *gate) unpause() bool {
+	defer g.lock.Unlock()
+	g.lock.Lock()
+
+	if !g.paused {
+		return false
+	}
+
+	g.paused = false
+	close(g.resume)
+	return true
+}
+
+func (g *gate) isPaused() bool {
+	defer g.lock.Unlock()
+	g.lock.Lock()
+	return g.paused
+}
+
+// wait blocks while this gate is closed.  If the cancel channel is closed first,
+// this method returns false.
+func (g *gate) wait(cancel <-chan struct{}) bool {
+	g.lock.Lock()
+	paused, resume := g.paused, g.resume
+	g.lock.Unlock()
+
+	if !paused {
+		return true
+	}
+
+	select {
+	case <-resume:
+		return true
+	case <-cancel:
+		return false
+	}
+}
@@ -0,0 +1,39 @@
+package drain
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/Comcast/webpa-common/xhttp"
+)
+
+// History returns a JSON message describing the drain jobs that have finished or were cancelled
+type History struct {
+	Drainer Interface
+}
+
+func (h *History) ServeHTTP(response http.ResponseWriter, request *http.Request) {
+	var (
+		records = h.Drainer.History()
+		output  = make([]map[string]interface{}, len(records))
+	)
+
+	for i, r := range records {
+		output[i] = r.ToMap()
+	}
+
+	message, err := json.Marshal(
+		map[string]interface{}{
+			"history": output,
+		},
+	)
+
+	if err != nil {
+		xhttp.WriteError(response, http.StatusInternalServerError, err)
+		return
+	}
+
+	response.Header().Set("Content-Type", "application/json")
+	response.WriteHeader(http.StatusOK)
+	response.Write(message)
+}
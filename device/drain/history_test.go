@@ -0,0 +1,70 @@
+package drain
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func testHistoryEmpty(t *testing.T) {
+	var (
+		assert = assert.New(t)
+
+		d       = new(mockDrainer)
+		history = History{d}
+
+		response = httptest.NewRecorder()
+		request  = httptest.NewRequest("GET", "/", nil)
+	)
+
+	d.On("History").Return([]Record{}).Once()
+	history.ServeHTTP(response, request)
+	assert.Equal(http.StatusOK, response.Code)
+	assert.Equal("application/json", response.HeaderMap.Get("Content-Type"))
+	assert.JSONEq(`{"history": []}`, response.Body.String())
+
+	d.AssertExpectations(t)
+}
+
+func testHistoryRecords(t *testing.T) {
+	var (
+		assert = assert.New(t)
+
+		d       = new(mockDrainer)
+		history = History{d}
+		now     = time.Now()
+
+		response = httptest.NewRecorder()
+		request  = httptest.NewRequest("GET", "/", nil)
+	)
+
+	d.On("History").Return([]Record{
+		{ID: 1, Job: Job{Count: 100}, Progress: Progress{Visited: 100, Drained: 98, Started: now, Finished: &now}},
+		{ID: 2, Job: Job{Count: 50, Rate: 10, Tick: time.Minute}, Progress: Progress{Visited: 20, Drained: 20, Started: now, Finished: &now, Cancelled: true}},
+	}).Once()
+
+	history.ServeHTTP(response, request)
+	assert.Equal(http.StatusOK, response.Code)
+	assert.Equal("application/json", response.HeaderMap.Get("Content-Type"))
+	assert.JSONEq(
+		fmt.Sprintf(
+			`{"history": [
+				{"id": 1, "job": {"count": 100}, "progress": {"visited": 100, "drained": 98, "started": "%[1]s", "finished": "%[1]s"}},
+				{"id": 2, "job": {"count": 50, "rate": 10, "tick": "1m0s"}, "progress": {"visited": 20, "drained": 20, "started": "%[1]s", "finished": "%[1]s", "cancelled": true}}
+			]}`,
+			now.Format(time.RFC3339Nano),
+		),
+		response.Body.String(),
+	)
+
+	d.AssertExpectations(t)
+}
+
+func TestHistory(t *testing.T) {
+	t.Run("Empty", testHistoryEmpty)
+	t.Run("Records", testHistoryRecords)
+}
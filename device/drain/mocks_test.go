@@ -28,6 +28,18 @@ func (m *mockDrainer) Cancel() (<-chan struct{}, error) {
 	return arguments.Get(0).(<-chan struct{}), arguments.Error(1)
 }
 
+func (m *mockDrainer) Pause() error {
+	return m.Called().Error(0)
+}
+
+func (m *mockDrainer) Resume() error {
+	return m.Called().Error(0)
+}
+
+func (m *mockDrainer) History() []Record {
+	return m.Called().Get(0).([]Record)
+}
+
 type stubManager struct {
 	lock    sync.RWMutex
 	assert  *assert.Assertions
@@ -77,6 +89,8 @@ func (sm *stubManager) DisconnectAll() int {
 }
 
 func (sm *stubManager) Len() int {
+	defer sm.lock.RUnlock()
+	sm.lock.RLock()
 	return len(sm.devices)
 }
 
@@ -120,7 +134,16 @@ func generateManager(assert *assert.Assertions, count uint64) *stubManager {
 		pauseVisit:      make(chan struct{}),
 	}
 
-	for mac := uint64(0); mac < count; mac++ {
+	sm.addDevices(0, count)
+	return sm
+}
+
+// addDevices connects count mock devices, with MAC addresses starting at the given value
+func (sm *stubManager) addDevices(start, count uint64) {
+	defer sm.lock.Unlock()
+	sm.lock.Lock()
+
+	for mac := start; mac < start+count; mac++ {
 		var (
 			id = device.IntToMAC(mac)
 			d  = new(device.MockDevice)
@@ -130,6 +153,4 @@ func generateManager(assert *assert.Assertions, count uint64) *stubManager {
 		d.On("String").Return("mockDevice(" + string(id) + ")")
 		sm.devices[id] = d
 	}
-
-	return sm
 }
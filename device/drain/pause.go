@@ -0,0 +1,14 @@
+package drain
+
+import "net/http"
+
+// Pause is an HTTP handler that suspends the current drain job
+type Pause struct {
+	Drainer Interface
+}
+
+func (p *Pause) ServeHTTP(response http.ResponseWriter, request *http.Request) {
+	if err := p.Drainer.Pause(); err != nil {
+		response.WriteHeader(http.StatusConflict)
+	}
+}
@@ -0,0 +1,51 @@
+package drain
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func testPauseError(t *testing.T, expectedError error) {
+	var (
+		assert = assert.New(t)
+
+		d     = new(mockDrainer)
+		pause = Pause{d}
+
+		response = httptest.NewRecorder()
+		request  = httptest.NewRequest("POST", "/", nil)
+	)
+
+	d.On("Pause").Return(expectedError).Once()
+	pause.ServeHTTP(response, request)
+	assert.Equal(http.StatusConflict, response.Code)
+
+	d.AssertExpectations(t)
+}
+
+func testPauseSuccess(t *testing.T) {
+	var (
+		assert = assert.New(t)
+
+		d     = new(mockDrainer)
+		pause = Pause{d}
+
+		response = httptest.NewRecorder()
+		request  = httptest.NewRequest("POST", "/", nil)
+	)
+
+	d.On("Pause").Return(error(nil)).Once()
+	pause.ServeHTTP(response, request)
+	assert.Equal(http.StatusOK, response.Code)
+
+	d.AssertExpectations(t)
+}
+
+func TestPause(t *testing.T) {
+	t.Run("NotActive", func(t *testing.T) { testPauseError(t, ErrNotActive) })
+	t.Run("Paused", func(t *testing.T) { testPauseError(t, ErrPaused) })
+	t.Run("Success", testPauseSuccess)
+}
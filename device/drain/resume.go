@@ -0,0 +1,14 @@
+package drain
+
+import "net/http"
+
+// Resume is an HTTP handler that continues a paused drain job
+type Resume struct {
+	Drainer Interface
+}
+
+func (r *Resume) ServeHTTP(response http.ResponseWriter, request *http.Request) {
+	if err := r.Drainer.Resume(); err != nil {
+		response.WriteHeader(http.StatusConflict)
+	}
+}
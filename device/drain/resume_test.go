@@ -0,0 +1,51 @@
+package drain
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func testResumeError(t *testing.T, expectedError error) {
+	var (
+		assert = assert.New(t)
+
+		d      = new(mockDrainer)
+		resume = Resume{d}
+
+		response = httptest.NewRecorder()
+		request  = httptest.NewRequest("POST", "/", nil)
+	)
+
+	d.On("Resume").Return(expectedError).Once()
+	resume.ServeHTTP(response, request)
+	assert.Equal(http.StatusConflict, response.Code)
+
+	d.AssertExpectations(t)
+}
+
+func testResumeSuccess(t *testing.T) {
+	var (
+		assert = assert.New(t)
+
+		d      = new(mockDrainer)
+		resume = Resume{d}
+
+		response = httptest.NewRecorder()
+		request  = httptest.NewRequest("POST", "/", nil)
+	)
+
+	d.On("Resume").Return(error(nil)).Once()
+	resume.ServeHTTP(response, request)
+	assert.Equal(http.StatusOK, response.Code)
+
+	d.AssertExpectations(t)
+}
+
+func TestResume(t *testing.T) {
+	t.Run("NotActive", func(t *testing.T) { testResumeError(t, ErrNotActive) })
+	t.Run("NotPaused", func(t *testing.T) { testResumeError(t, ErrNotPaused) })
+	t.Run("Success", testResumeSuccess)
+}
@@ -30,6 +30,7 @@ func (s *Start) ServeHTTP(response http.ResponseWriter, request *http.Request) {
 	)
 
 	decoder.RegisterConverter(time.Duration(0), converter.Duration)
+	decoder.RegisterConverter(time.Time{}, converter.Time)
 	if err := decoder.Decode(&input, request.Form); err != nil {
 		logger.Log(level.Key(), level.ErrorValue(), logging.MessageKey(), "unable to decode request", logging.ErrorKey(), err)
 		xhttp.WriteError(response, http.StatusBadRequest, err)
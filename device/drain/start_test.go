@@ -61,6 +61,10 @@ func testStartServeHTTPValid(t *testing.T) {
 			"/foo?count=22&rate=10&tick=20s",
 			Job{Count: 22, Rate: 10, Tick: 20 * time.Second},
 		},
+		{
+			"/foo?rate=5&startAt=2018-04-05T12:00:00Z",
+			Job{Rate: 5, StartAt: time.Date(2018, 4, 5, 12, 0, 0, 0, time.UTC)},
+		},
 	}
 
 	for _, record := range testData {
@@ -18,33 +18,58 @@ type Progress struct {
 	// so this value can be lower than Visited, even in a job that has finished.
 	Drained int `json:"drained"`
 
-	// Started is the UTC system time at which the drain job was started.
+	// Started is the UTC system time at which the drain job was started.  For a scheduled job that
+	// has not begun yet, this field will be the zero time.
 	Started time.Time `json:"started"`
 
 	// Finished is the UTC system time at which the drain job finished or was canceled.
 	// If the job is running, this field will be nil.
 	Finished *time.Time `json:"finished,omitempty"`
+
+	// Paused indicates whether the drain job is currently paused.
+	Paused bool `json:"paused,omitempty"`
+
+	// Cancelled indicates whether the drain job was cancelled before it could finish.
+	Cancelled bool `json:"cancelled,omitempty"`
+
+	// Connected is the number of connected devices observed on the most recent tick of a drain-to-target job.
+	// This field is unset for other kinds of jobs.
+	Connected int `json:"connected,omitempty"`
+
+	// TargetReached is the UTC system time at which a drain-to-target job first observed the number
+	// of connected devices at or below its target.  For other jobs, or until the target is reached, this field is nil.
+	TargetReached *time.Time `json:"targetReached,omitempty"`
 }
 
 type tracker struct {
-	visited  int32
-	drained  int32
-	started  time.Time
-	finished atomic.Value
-	counter  xmetrics.Adder
+	visited       int32
+	drained       int32
+	connected     int32
+	started       atomic.Value
+	finished      atomic.Value
+	targetReached atomic.Value
+	counter       xmetrics.Adder
 }
 
 func (t *tracker) Progress() Progress {
 	p := Progress{
-		Visited: int(atomic.LoadInt32(&t.visited)),
-		Drained: int(atomic.LoadInt32(&t.drained)),
-		Started: t.started,
+		Visited:   int(atomic.LoadInt32(&t.visited)),
+		Drained:   int(atomic.LoadInt32(&t.drained)),
+		Connected: int(atomic.LoadInt32(&t.connected)),
+	}
+
+	if started, ok := t.started.Load().(time.Time); ok {
+		p.Started = started
 	}
 
 	if finished, ok := t.finished.Load().(time.Time); ok && !finished.IsZero() {
 		p.Finished = &finished
 	}
 
+	if targetReached, ok := t.targetReached.Load().(time.Time); ok {
+		p.TargetReached = &targetReached
+	}
+
 	return p
 }
 
@@ -57,6 +82,25 @@ func (t *tracker) addDrained(delta int) {
 	t.counter.Add(float64(delta))
 }
 
+func (t *tracker) setConnected(connected int) {
+	atomic.StoreInt32(&t.connected, int32(connected))
+}
+
+// reachTarget records the first time a target was reached.  This method returns true
+// if this was the first time, false if the target had already been reached.
+func (t *tracker) reachTarget(timestamp time.Time) bool {
+	if _, ok := t.targetReached.Load().(time.Time); ok {
+		return false
+	}
+
+	t.targetReached.Store(timestamp)
+	return true
+}
+
+func (t *tracker) start(timestamp time.Time) {
+	t.started.Store(timestamp)
+}
+
 func (t *tracker) done(timestamp time.Time) {
 	t.finished.Store(timestamp)
 }
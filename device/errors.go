@@ -23,4 +23,7 @@ var (
 	ErrorDeviceClosed                 = errors.New("That device has been closed")
 	ErrorTransactionsClosed           = errors.New("Transactions are closed for that device")
 	ErrorTransactionsAlreadyClosed    = errors.New("That Transactions is already closed")
+	ErrorInvalidPart                  = errors.New("Invalid multi-part response markers")
+	ErrorPartOutOfSequence            = errors.New("That response part is out of sequence")
+	ErrorTooManyPendingParts          = errors.New("Too many response parts are pending for that transaction")
 )
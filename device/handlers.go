@@ -110,13 +110,19 @@ func useID(f IDFromRequest) func(http.Handler) http.Handler {
 }
 
 // MessageHandler is a configurable http.Handler which handles inbound WRP traffic
-// to be sent to devices.
+// to be sent to devices.  When a device answers with a multi-part response and the client accepts
+// a WRP stream content type, the parts are streamed to the client as they arrive, using EncodeResponseParts.
+// Other clients receive only the first part.
 type MessageHandler struct {
 	// Logger is the sink for logging output.  If not set, logging will be sent to a NOP logger
 	Logger log.Logger
 
 	// Router is the device message Router to use.  This field is required.
 	Router Router
+
+	// Validator is the optional strategy used to check inbound WRP messages.  Messages that fail
+	// validation are rejected with http.StatusBadRequest.  If not set, messages are not validated.
+	Validator wrp.Validator
 }
 
 func (mh *MessageHandler) logger() log.Logger {
@@ -127,15 +133,26 @@ func (mh *MessageHandler) logger() log.Logger {
 	return logging.DefaultLogger()
 }
 
-// decodeRequest transforms an HTTP request into a device request.
+func (mh *MessageHandler) validators() []wrp.Validator {
+	if mh.Validator != nil {
+		return []wrp.Validator{mh.Validator}
+	}
+
+	return nil
+}
+
+// decodeRequest transforms an HTTP request into a device request.  Multi-part responses are only accepted
+// when the client accepts a WRP stream content type.
 func (mh *MessageHandler) decodeRequest(httpRequest *http.Request) (deviceRequest *Request, err error) {
 	format, err := wrp.FormatFromContentType(httpRequest.Header.Get("Content-Type"), wrp.Msgpack)
 	if err != nil {
 		return nil, err
 	}
 
-	deviceRequest, err = DecodeRequest(httpRequest.Body, format)
+	deviceRequest, err = DecodeRequest(httpRequest.Body, format, mh.validators()...)
 	if err == nil {
+		deviceRequest.PartnerIDs = callerPartnerIDs(httpRequest)
+		deviceRequest.AcceptParts = wrp.IsStreamContentType(httpRequest.Header.Get("Accept"))
 		deviceRequest = deviceRequest.WithContext(httpRequest.Context())
 	}
 
@@ -195,6 +212,10 @@ func (mh *MessageHandler) ServeHTTP(httpResponse http.ResponseWriter, httpReques
 			code = StatusDeviceDisconnected
 		case ErrorTransactionsAlreadyClosed:
 			code = StatusDeviceDisconnected
+		default:
+			if _, ok := err.(*PartnerMismatchError); ok {
+				code = http.StatusForbidden
+			}
 		}
 
 		mh.logger().Log(level.Key(), level.ErrorValue(), logging.MessageKey(), "Could not process device request", logging.ErrorKey(), err, "code", code)
@@ -204,6 +225,11 @@ func (mh *MessageHandler) ServeHTTP(httpResponse http.ResponseWriter, httpReques
 			"Could not process device request: %s",
 			err,
 		)
+	} else if deviceResponse != nil && deviceResponse.Parts != nil {
+		// multi-part responses are streamed to the client as each part arrives
+		if err := EncodeResponseParts(httpResponse, deviceResponse, responseFormat); err != nil {
+			mh.logger().Log(level.Key(), level.ErrorValue(), logging.MessageKey(), "Error while streaming transaction response", logging.ErrorKey(), err)
+		}
 	} else if deviceResponse != nil {
 		if err := EncodeResponse(httpResponse, deviceResponse, responseFormat); err != nil {
 			mh.logger().Log(level.Key(), level.ErrorValue(), logging.MessageKey(), "Error while writing transaction response", logging.ErrorKey(), err)
@@ -12,6 +12,7 @@ import (
 	"time"
 
 	"github.com/Comcast/webpa-common/logging"
+	securehandler "github.com/Comcast/webpa-common/secure/handler"
 	"github.com/Comcast/webpa-common/wrp"
 	"github.com/gorilla/mux"
 	"github.com/justinas/alice"
@@ -258,6 +259,36 @@ func testMessageHandlerServeHTTPDecodeError(t *testing.T) {
 	router.AssertExpectations(t)
 }
 
+func testMessageHandlerServeHTTPValidationError(t *testing.T) {
+	var (
+		assert  = assert.New(t)
+		require = require.New(t)
+
+		invalidMessage = wrp.SimpleEvent{
+			Source: "dns:app.comcast.com",
+		}
+
+		response           = httptest.NewRecorder()
+		request            = httptest.NewRequest("POST", "/foo", bytes.NewReader(wrp.MustEncode(&invalidMessage, wrp.Msgpack)))
+		actualResponseBody map[string]interface{}
+
+		router  = new(mockRouter)
+		handler = MessageHandler{
+			Router:    router,
+			Validator: wrp.DefaultValidator(),
+		}
+	)
+
+	handler.ServeHTTP(response, request)
+	assert.Equal(http.StatusBadRequest, response.Code)
+	assert.Equal("application/json", response.HeaderMap.Get("Content-Type"))
+	responseContents, err := ioutil.ReadAll(response.Body)
+	require.NoError(err)
+	assert.NoError(json.Unmarshal(responseContents, &actualResponseBody))
+
+	router.AssertExpectations(t)
+}
+
 func testMessageHandlerServeHTTPRouteError(t *testing.T, routeError error, expectedCode int) {
 	var (
 		assert  = assert.New(t)
@@ -431,6 +462,157 @@ func testMessageHandlerServeHTTPRequestResponse(t *testing.T, responseFormat, re
 	device.AssertExpectations(t)
 }
 
+func testMessageHandlerServeHTTPMultiPart(t *testing.T, responseFormat wrp.Format) {
+	var (
+		assert  = assert.New(t)
+		require = require.New(t)
+
+		requestMessage = &wrp.Message{
+			Type:            wrp.SimpleRequestResponseMessageType,
+			Source:          "test.com",
+			Destination:     "mac:123412341234",
+			TransactionUUID: "transaction-key",
+		}
+
+		response = httptest.NewRecorder()
+		request  = httptest.NewRequest("POST", "/foo", bytes.NewReader(wrp.MustEncode(requestMessage, wrp.Msgpack)))
+
+		router  = new(mockRouter)
+		handler = MessageHandler{
+			Logger: logging.NewTestLogger(nil, t),
+			Router: router,
+		}
+
+		parts = make(chan *Response, 1)
+	)
+
+	parts <- &Response{
+		Message: &wrp.Message{Type: wrp.SimpleRequestResponseMessageType, Payload: []byte("second")},
+		Part:    &Part{Sequence: 1, Last: true},
+	}
+
+	close(parts)
+	request.Header.Set("Content-Type", wrp.Msgpack.ContentType())
+	request.Header.Set("Accept", responseFormat.StreamContentType())
+
+	router.On("Route", mock.MatchedBy(func(r *Request) bool { return r.AcceptParts })).Once().Return(
+		&Response{
+			Message: &wrp.Message{Type: wrp.SimpleRequestResponseMessageType, Payload: []byte("first")},
+			Part:    &Part{Sequence: 0},
+			Parts:   parts,
+		},
+		nil,
+	)
+
+	handler.ServeHTTP(response, request)
+	assert.Equal(http.StatusOK, response.Code)
+	assert.Equal(responseFormat.StreamContentType(), response.HeaderMap.Get("Content-Type"))
+
+	reader := wrp.NewStreamReader(response.Body, responseFormat)
+	for _, expected := range []string{"first", "second"} {
+		var message wrp.Message
+		require.NoError(reader.Read(&message))
+		assert.Equal([]byte(expected), message.Payload)
+	}
+
+	router.AssertExpectations(t)
+}
+
+func testMessageHandlerServeHTTPMultiPartNotStreamed(t *testing.T, responseFormat wrp.Format) {
+	var (
+		assert  = assert.New(t)
+		require = require.New(t)
+
+		requestMessage = &wrp.Message{
+			Type:            wrp.SimpleRequestResponseMessageType,
+			Source:          "test.com",
+			Destination:     "mac:123412341234",
+			TransactionUUID: "transaction-key",
+		}
+
+		response = httptest.NewRecorder()
+		request  = httptest.NewRequest("POST", "/foo", bytes.NewReader(wrp.MustEncode(requestMessage, wrp.Msgpack)))
+
+		router  = new(mockRouter)
+		handler = MessageHandler{
+			Logger: logging.NewTestLogger(nil, t),
+			Router: router,
+		}
+	)
+
+	request.Header.Set("Content-Type", wrp.Msgpack.ContentType())
+	request.Header.Set("Accept", responseFormat.ContentType())
+
+	// a request that does not accept parts gets just the first part, as the remaining parts are discarded by Send
+	first := &wrp.Message{Type: wrp.SimpleRequestResponseMessageType, Payload: []byte("first")}
+	router.On("Route", mock.MatchedBy(func(r *Request) bool { return !r.AcceptParts })).Once().Return(
+		&Response{
+			Message:  first,
+			Format:   wrp.Msgpack,
+			Contents: wrp.MustEncode(first, wrp.Msgpack),
+			Part:     &Part{Sequence: 0},
+		},
+		nil,
+	)
+
+	handler.ServeHTTP(response, request)
+	assert.Equal(http.StatusOK, response.Code)
+	assert.Equal(responseFormat.ContentType(), response.HeaderMap.Get("Content-Type"))
+
+	var message wrp.Message
+	require.NoError(wrp.NewDecoderBytes(response.Body.Bytes(), responseFormat).Decode(&message))
+	assert.Equal([]byte("first"), message.Payload)
+
+	router.AssertExpectations(t)
+}
+
+func testMessageHandlerServeHTTPPartnerIDs(t *testing.T, callerPartnerIDs []string, expectedCode int) {
+	var (
+		assert  = assert.New(t)
+		require = require.New(t)
+
+		manager = NewManager(&Options{Logger: logging.NewTestLogger(nil, t), EnforcePartnerIDs: true}).(*manager)
+		d       = newDevice(deviceOptions{ID: "mac:112233445566", Logger: logging.NewTestLogger(nil, t)})
+		handler = MessageHandler{
+			Logger: logging.NewTestLogger(nil, t),
+			Router: manager,
+		}
+
+		// the body always claims the device's partner
+		requestMessage = &wrp.Message{
+			Type:        wrp.SimpleEventMessageType,
+			Source:      "test.com",
+			Destination: "mac:112233445566",
+			PartnerIDs:  []string{"comcast"},
+		}
+
+		response = httptest.NewRecorder()
+		request  = httptest.NewRequest("POST", "/foo", bytes.NewReader(wrp.MustEncode(requestMessage, wrp.Msgpack)))
+	)
+
+	d.partnerIDs = []string{"comcast"}
+	require.NoError(manager.devices.add(d))
+
+	// simulate the write pump, which completes each enqueued request
+	go func() {
+		for e := range d.messages {
+			e.complete <- nil
+		}
+	}()
+
+	defer close(d.messages)
+
+	request.Header.Set("Content-Type", wrp.Msgpack.ContentType())
+	if callerPartnerIDs != nil {
+		request = request.WithContext(
+			securehandler.NewContextWithValue(request.Context(), &securehandler.ContextValues{PartnerIDs: callerPartnerIDs}),
+		)
+	}
+
+	handler.ServeHTTP(response, request)
+	assert.Equal(expectedCode, response.Code)
+}
+
 func testMessageHandlerServeHTTPEncodeError(t *testing.T) {
 	const transactionKey = "transaction-key"
 
@@ -504,6 +686,7 @@ func TestMessageHandler(t *testing.T) {
 
 	t.Run("ServeHTTP", func(t *testing.T) {
 		t.Run("DecodeError", testMessageHandlerServeHTTPDecodeError)
+		t.Run("ValidationError", testMessageHandlerServeHTTPValidationError)
 		t.Run("EncodeError", testMessageHandlerServeHTTPEncodeError)
 
 		t.Run("RouteError", func(t *testing.T) {
@@ -512,6 +695,7 @@ func TestMessageHandler(t *testing.T) {
 			testMessageHandlerServeHTTPRouteError(t, ErrorNonUniqueID, http.StatusBadRequest)
 			testMessageHandlerServeHTTPRouteError(t, ErrorInvalidTransactionKey, http.StatusBadRequest)
 			testMessageHandlerServeHTTPRouteError(t, ErrorTransactionAlreadyRegistered, http.StatusBadRequest)
+			testMessageHandlerServeHTTPRouteError(t, &PartnerMismatchError{ID: "mac:112233445566"}, http.StatusForbidden)
 			testMessageHandlerServeHTTPRouteError(t, errors.New("random error"), http.StatusInternalServerError)
 		})
 
@@ -521,6 +705,19 @@ func TestMessageHandler(t *testing.T) {
 			}
 		})
 
+		t.Run("PartnerIDs", func(t *testing.T) {
+			t.Run("Match", func(t *testing.T) { testMessageHandlerServeHTTPPartnerIDs(t, []string{"comcast"}, http.StatusOK) })
+			t.Run("Mismatch", func(t *testing.T) { testMessageHandlerServeHTTPPartnerIDs(t, []string{"cox"}, http.StatusForbidden) })
+			t.Run("NoJWT", func(t *testing.T) { testMessageHandlerServeHTTPPartnerIDs(t, nil, http.StatusForbidden) })
+		})
+
+		t.Run("MultiPart", func(t *testing.T) {
+			for _, responseFormat := range wrp.AllFormats() {
+				testMessageHandlerServeHTTPMultiPart(t, responseFormat)
+				testMessageHandlerServeHTTPMultiPartNotStreamed(t, responseFormat)
+			}
+		})
+
 		t.Run("RequestResponse", func(t *testing.T) {
 			for _, responseFormat := range []wrp.Format{wrp.Msgpack, wrp.JSON} {
 				for _, requestFormat := range []wrp.Format{wrp.Msgpack, wrp.JSON} {
@@ -4,8 +4,6 @@ import (
 	"fmt"
 	"net/http"
 	"regexp"
-	"strings"
-	"unicode"
 )
 
 // ID represents a normalized identifer for a device.
@@ -17,19 +15,17 @@ func (id ID) Bytes() []byte {
 }
 
 const (
-	hexDigits     = "0123456789abcdefABCDEF"
-	macDelimiters = ":-.,"
-	macPrefix     = "mac"
-	macLength     = 12
+	macPrefix = "mac"
 )
 
 var (
 	invalidID = ID("")
 
 	// idPattern is the precompiled regular expression that all device identifiers must match.
-	// Matching is partial, as everything after the service is ignored.
+	// Matching is partial, as everything after the service is ignored.  The prefix must name
+	// a registered scheme.
 	idPattern = regexp.MustCompile(
-		`^(?P<prefix>(?i)mac|uuid|dns|serial):(?P<id>[^/]+)(?P<service>/[^/]+)?`,
+		`^(?P<prefix>[^:/]+):(?P<id>[^/]+)(?P<service>/[^/]+)?`,
 	)
 )
 
@@ -40,55 +36,27 @@ func IntToMAC(value uint64) ID {
 	return ID(fmt.Sprintf("mac:%012x", value&0x0000FFFFFFFFFFFF))
 }
 
-// ParseID parses a raw device name into a canonicalized identifier.
+// ParseID parses a raw device name into a canonicalized identifier using DefaultSchemes().
 func ParseID(deviceName string) (ID, error) {
-	match := idPattern.FindStringSubmatch(deviceName)
-	if match == nil {
-		return invalidID, ErrorInvalidDeviceName
-	}
-
-	var (
-		prefix = strings.ToLower(match[1])
-		idPart = match[2]
-	)
-
-	if prefix == macPrefix {
-		var invalidCharacter rune = -1
-		idPart = strings.Map(
-			func(r rune) rune {
-				switch {
-				case strings.ContainsRune(hexDigits, r):
-					return unicode.ToLower(r)
-				case strings.ContainsRune(macDelimiters, r):
-					return -1
-				default:
-					invalidCharacter = r
-					return -1
-				}
-			},
-			idPart,
-		)
-
-		if invalidCharacter != -1 || len(idPart) != macLength {
-			return invalidID, ErrorInvalidDeviceName
-		}
-	}
-
-	return ID(fmt.Sprintf("%s:%s", prefix, idPart)), nil
+	return DefaultSchemes().ParseID(deviceName)
 }
 
 // IDHashParser is a parsing function that examines an HTTP request to produce
 // a []byte key for consistent hashing.  The returned function examines the
 // given request header and invokes ParseID on the value.
-//
-// If deviceNameHeader is the empty string, DefaultDeviceNameHeader is used.
 func IDHashParser(request *http.Request) ([]byte, error) {
+	return DefaultSchemes().IDHashParser(request)
+}
+
+// IDHashParser is a parsing function that examines an HTTP request to produce a []byte key
+// for consistent hashing, using the schemes in this registry to parse the device name header.
+func (s *Schemes) IDHashParser(request *http.Request) ([]byte, error) {
 	deviceName := request.Header.Get(DeviceNameHeader)
 	if len(deviceName) == 0 {
 		return nil, ErrorMissingDeviceNameHeader
 	}
 
-	id, err := ParseID(deviceName)
+	id, err := s.ParseID(deviceName)
 	if err != nil {
 		return nil, err
 	}
@@ -4,7 +4,9 @@ import (
 	"net/http/httptest"
 	"testing"
 
+	"github.com/Comcast/webpa-common/wrp"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestIntToMAC(t *testing.T) {
@@ -61,6 +63,24 @@ func TestParseID(t *testing.T) {
 	}
 }
 
+func TestParseIDMatchesLocator(t *testing.T) {
+	for _, deviceName := range []string{"MAC:11:22:33:44:55:66", "mac:11aaBB445566/service/path", "mac:11,aa,BB,44,55,66", "uuid:1234/service", "DNS:foo.com"} {
+		t.Run(deviceName, func(t *testing.T) {
+			var (
+				assert  = assert.New(t)
+				require = require.New(t)
+			)
+
+			id, err := ParseID(deviceName)
+			require.NoError(err)
+
+			locator, err := wrp.ParseLocator(deviceName)
+			require.NoError(err)
+			assert.Equal(string(id), locator.ID())
+		})
+	}
+}
+
 func TestIDHashParser(t *testing.T) {
 	var (
 		assert            = assert.New(t)
@@ -36,6 +36,10 @@ const (
 	// was no waiting transaction
 	TransactionBroken
 
+	// TransactionPart indicates that one part of a multi-part response to a transaction has been received.
+	// The transaction remains pending until its last part is received, which produces a TransactionComplete event.
+	TransactionPart
+
 	InvalidEventString string = "!!INVALID DEVICE EVENT TYPE!!"
 )
 
@@ -60,6 +64,8 @@ func (et EventType) String() string {
 		return "TransactionComplete"
 	case TransactionBroken:
 		return "TransactionBroken"
+	case TransactionPart:
+		return "TransactionPart"
 	default:
 		return InvalidEventString
 	}
@@ -18,6 +18,7 @@ func testEventString(t *testing.T) {
 			MessageFailed,
 			TransactionComplete,
 			TransactionBroken,
+			TransactionPart,
 		}
 	)
 
@@ -83,10 +83,16 @@ type Manager interface {
 // created from the options if one is not supplied.
 func NewManager(o *Options) Manager {
 	var (
-		logger   = o.logger()
-		measures = NewMeasures(o.metricsProvider())
+		logger       = o.logger()
+		measures     = NewMeasures(o.metricsProvider())
+		authStatus   = o.authStatus()
+		authContents []byte
 	)
 
+	if authStatus != 0 {
+		authContents = wrp.MustEncode(&wrp.Authorization{Status: authStatus}, wrp.Msgpack)
+	}
+
 	return &manager{
 		logger:   logger,
 		errorLog: logging.Error(logger),
@@ -104,10 +110,21 @@ func NewManager(o *Options) Manager {
 		conveyHWMetric: conveymetric.NewConveyMetric(measures.Models, "hw-model", "model"),
 
 		deviceMessageQueueSize: o.deviceMessageQueueSize(),
+		maxPendingParts:        o.maxPendingParts(),
 		pingPeriod:             o.pingPeriod(),
 
 		listeners: o.listeners(),
 		measures:  measures,
+		validator: o.validator(),
+
+		decompressPayloads:  o.decompressPayloads(),
+		maxDecompressedSize: o.maxDecompressedSize(),
+
+		authStatus:   authStatus,
+		authContents: authContents,
+
+		enforcePartnerIDs: o.enforcePartnerIDs(),
+		conveyPartnerIDs:  o.conveyPartnerIDs(),
 	}
 }
 
@@ -126,10 +143,21 @@ type manager struct {
 	conveyHWMetric conveymetric.Interface
 
 	deviceMessageQueueSize int
+	maxPendingParts        int
 	pingPeriod             time.Duration
 
 	listeners []Listener
 	measures  Measures
+	validator wrp.Validator
+
+	decompressPayloads  bool
+	maxDecompressedSize int
+
+	authStatus   int64
+	authContents []byte
+
+	enforcePartnerIDs bool
+	conveyPartnerIDs  bool
 }
 
 func (m *manager) Connect(response http.ResponseWriter, request *http.Request, responseHeader http.Header) (Interface, error) {
@@ -145,7 +173,7 @@ func (m *manager) Connect(response http.ResponseWriter, request *http.Request, r
 		return nil, ErrorMissingDeviceNameContext
 	}
 
-	d := newDevice(deviceOptions{ID: id, QueueSize: m.deviceMessageQueueSize, Logger: m.logger})
+	d := newDevice(deviceOptions{ID: id, QueueSize: m.deviceMessageQueueSize, MaxPendingParts: m.maxPendingParts, Logger: m.logger})
 	convey, conveyErr := m.conveyTranslator.FromHeader(request.Header)
 	if conveyErr == nil {
 		d.infoLog.Log("convey", convey)
@@ -153,6 +181,8 @@ func (m *manager) Connect(response http.ResponseWriter, request *http.Request, r
 		d.errorLog.Log(logging.MessageKey(), "badly formatted convey data", logging.ErrorKey(), conveyErr)
 	}
 
+	d.partnerIDs = connectPartnerIDs(request, convey, m.conveyPartnerIDs)
+
 	c, err := m.upgrader.Upgrade(response, request, responseHeader)
 	if err != nil {
 		d.errorLog.Log(logging.MessageKey(), "failed websocket upgrade", logging.ErrorKey(), err)
@@ -168,6 +198,10 @@ func (m *manager) Connect(response http.ResponseWriter, request *http.Request, r
 		return nil, err
 	}
 
+	if m.authStatus != 0 {
+		m.sendAuthStatus(d)
+	}
+
 	if err := m.devices.add(d); err != nil {
 		d.errorLog.Log(logging.MessageKey(), "unable to register device", logging.ErrorKey(), err)
 		c.Close()
@@ -205,6 +239,23 @@ func (m *manager) Connect(response http.ResponseWriter, request *http.Request, r
 	return d, nil
 }
 
+// sendAuthStatus enqueues the configured Authorization message for a newly connected device.  This method
+// must be called before the device is visible to other goroutines, so that the Authorization message is the
+// first message written to the device.  The enqueued message is not awaited.
+func (m *manager) sendAuthStatus(d *device) {
+	request := &Request{
+		Message:  &wrp.Authorization{Type: wrp.AuthMessageType, Status: m.authStatus},
+		Format:   wrp.Msgpack,
+		Contents: m.authContents,
+	}
+
+	select {
+	case d.messages <- &envelope{request: request, complete: make(chan error, 1)}:
+	default:
+		d.errorLog.Log(logging.MessageKey(), "unable to enqueue authorization status")
+	}
+}
+
 func (m *manager) dispatch(e *Event) {
 	for _, listener := range m.listeners {
 		listener(e)
@@ -246,6 +297,7 @@ func (m *manager) readPump(d *device, r ReadCloser, closeOnce *sync.Once) {
 	var (
 		readError error
 		decoder   = wrp.NewDecoder(nil, wrp.Msgpack)
+		encoder   = wrp.NewEncoderBytes(new([]byte), wrp.Msgpack)
 	)
 
 	// all the read pump has to do is ensure the device and the connection are closed
@@ -283,26 +335,56 @@ func (m *manager) readPump(d *device, r ReadCloser, closeOnce *sync.Once) {
 			continue
 		}
 
+		if m.validator != nil {
+			if err := m.validator.Validate(message); err != nil {
+				d.errorLog.Log(logging.MessageKey(), "skipping invalid WRP message", logging.ErrorKey(), err)
+				continue
+			}
+		}
+
+		if m.decompressPayloads && len(message.ContentEncoding) > 0 {
+			if err := wrp.DecompressPayloadLimit(message, m.maxDecompressedSize); err != nil {
+				d.errorLog.Log(logging.MessageKey(), "skipping WRP message with undecodable payload", logging.ErrorKey(), err)
+				continue
+			}
+
+			// the original frame no longer matches the message, so reencode it
+			data = nil
+			encoder.ResetBytes(&data)
+			if err := encoder.Encode(message); err != nil {
+				d.errorLog.Log(logging.MessageKey(), "unable to reencode decompressed WRP message", logging.ErrorKey(), err)
+				continue
+			}
+
+			event.Contents = data
+		}
+
 		if message.Type == wrp.SimpleRequestResponseMessageType {
 			m.measures.RequestResponse.Add(1.0)
 		}
 
 		// update any waiting transaction
 		if message.IsTransactionPart() {
-			err := d.transactions.Complete(
-				message.TransactionKey(),
-				&Response{
-					Device:   d,
-					Message:  message,
-					Format:   wrp.Msgpack,
-					Contents: data,
-				},
-			)
+			part, err := GetPart(message)
+			if err == nil {
+				err = d.transactions.Complete(
+					message.TransactionKey(),
+					&Response{
+						Device:   d,
+						Message:  message,
+						Format:   wrp.Msgpack,
+						Contents: data,
+						Part:     part,
+					},
+				)
+			}
 
 			if err != nil {
 				d.errorLog.Log(logging.MessageKey(), "Error while completing transaction", "transactionKey", message.TransactionKey(), logging.ErrorKey(), err)
 				event.Type = TransactionBroken
 				event.Error = err
+			} else if part != nil && !part.Last {
+				event.Type = TransactionPart
 			} else {
 				event.Type = TransactionComplete
 			}
@@ -452,6 +534,12 @@ func (m *manager) Route(request *Request) (*Response, error) {
 	if destination, err := request.ID(); err != nil {
 		return nil, err
 	} else if d, ok := m.devices.get(destination); ok {
+		if m.enforcePartnerIDs {
+			if err := checkPartnerIDs(d, request); err != nil {
+				return nil, err
+			}
+		}
+
 		return d.Send(request)
 	} else {
 		return nil, ErrorDeviceNotFound
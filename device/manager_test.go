@@ -1,6 +1,8 @@
 package device
 
 import (
+	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"github.com/Comcast/webpa-common/convey"
@@ -14,8 +16,10 @@ import (
 
 	"github.com/Comcast/webpa-common/logging"
 	"github.com/Comcast/webpa-common/wrp"
+	"github.com/gorilla/websocket"
 	"github.com/justinas/alice"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 var (
@@ -291,6 +295,163 @@ func testManagerRouteDeviceNotFound(t *testing.T) {
 	assert.Equal(ErrorDeviceNotFound, err)
 }
 
+func testManagerRoutePartnerIDs(t *testing.T, enforce bool) {
+	var (
+		assert  = assert.New(t)
+		require = require.New(t)
+
+		manager = NewManager(&Options{Logger: logging.NewTestLogger(nil, t), EnforcePartnerIDs: enforce}).(*manager)
+		d       = newDevice(deviceOptions{ID: testDeviceIDs[0], Logger: logging.NewTestLogger(nil, t)})
+	)
+
+	d.partnerIDs = []string{"comcast", "cox"}
+	require.NoError(manager.devices.add(d))
+
+	// simulate the write pump, which completes each enqueued request
+	go func() {
+		for e := range d.messages {
+			e.complete <- nil
+		}
+	}()
+
+	defer close(d.messages)
+
+	for _, partnerIDs := range [][]string{{"cox"}, {"shaw", "comcast"}} {
+		response, err := manager.Route(&Request{
+			Message:    &wrp.SimpleEvent{Destination: string(d.id)},
+			PartnerIDs: partnerIDs,
+		})
+
+		assert.Nil(response)
+		assert.NoError(err)
+	}
+
+	// the partner ids in the message are supplied by the caller, and are never trusted
+	for _, partnerIDs := range [][]string{nil, {"shaw"}} {
+		response, err := manager.Route(&Request{
+			Message:    &wrp.SimpleEvent{Destination: string(d.id), PartnerIDs: []string{"comcast"}},
+			PartnerIDs: partnerIDs,
+		})
+
+		assert.Nil(response)
+		if enforce {
+			assert.Equal(&PartnerMismatchError{ID: d.id, Expected: d.partnerIDs, Actual: partnerIDs}, err)
+		} else {
+			assert.NoError(err)
+		}
+	}
+}
+
+// newTestPart creates one part of a multi-part response to the given request
+func newTestPart(request *wrp.Message, sequence int, last bool) []byte {
+	return wrp.MustEncode(
+		&wrp.Message{
+			Type:            wrp.SimpleRequestResponseMessageType,
+			Source:          request.Destination,
+			Destination:     request.Source,
+			TransactionUUID: request.TransactionUUID,
+			Payload:         []byte(fmt.Sprintf("part %d", sequence)),
+			Metadata: map[string]string{
+				PartSequenceKey: fmt.Sprint(sequence),
+				PartLastKey:     fmt.Sprint(last),
+			},
+		},
+		wrp.Msgpack,
+	)
+}
+
+func testManagerRouteMultiPart(t *testing.T, acceptParts bool) {
+	var (
+		assert    = assert.New(t)
+		require   = require.New(t)
+		connected = make(chan struct{})
+		events    = make(chan EventType, 10)
+
+		// the pumps outlive this test, so they must not log to it
+		options = &Options{
+			Logger: logging.DefaultLogger(),
+			Listeners: []Listener{
+				func(event *Event) {
+					switch event.Type {
+					case Connect:
+						close(connected)
+					case TransactionPart, TransactionComplete, TransactionBroken:
+						events <- event.Type
+					}
+				},
+			},
+		}
+
+		manager, server, connectURL = startWebsocketServer(options)
+
+		requestMessage = &wrp.Message{
+			Type:            wrp.SimpleRequestResponseMessageType,
+			Source:          "dns:talaria.example.com",
+			Destination:     string(testDeviceIDs[0]),
+			TransactionUUID: "multi-part",
+		}
+	)
+
+	defer server.Close()
+
+	deviceConnection, _, err := DefaultDialer().DialDevice(string(testDeviceIDs[0]), connectURL, nil)
+	require.NoError(err)
+	defer deviceConnection.Close()
+
+	select {
+	case <-connected:
+	case <-time.After(5 * time.Second):
+		require.Fail("The device did not connect")
+	}
+
+	go func() {
+		deviceConnection.SetReadDeadline(time.Now().Add(5 * time.Second))
+		if _, _, err := deviceConnection.ReadMessage(); !assert.NoError(err) {
+			return
+		}
+
+		for sequence := 0; sequence < 3; sequence++ {
+			assert.NoError(deviceConnection.WriteMessage(websocket.BinaryMessage, newTestPart(requestMessage, sequence, sequence == 2)))
+		}
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	response, err := manager.Route((&Request{Message: requestMessage, AcceptParts: acceptParts}).WithContext(ctx))
+	require.NoError(err)
+	require.NotNil(response)
+	require.NotNil(response.Part)
+	assert.Equal(Part{Sequence: 0}, *response.Part)
+	assert.Equal([]byte("part 0"), response.Message.Payload)
+
+	if acceptParts {
+		require.NotNil(response.Parts)
+		for sequence := 1; sequence < 3; sequence++ {
+			part, ok := <-response.Parts
+			require.True(ok)
+			require.NotNil(part.Part)
+			assert.Equal(Part{Sequence: sequence, Last: sequence == 2}, *part.Part)
+			assert.Equal([]byte(fmt.Sprintf("part %d", sequence)), part.Message.Payload)
+		}
+
+		_, ok := <-response.Parts
+		assert.False(ok)
+	} else {
+		// the remaining parts have already been discarded
+		assert.Nil(response.Parts)
+	}
+
+	for _, expected := range []EventType{TransactionPart, TransactionPart, TransactionComplete} {
+		select {
+		case actual := <-events:
+			assert.Equal(expected, actual)
+		case <-time.After(5 * time.Second):
+			assert.Fail("No transaction event was dispatched")
+		}
+	}
+}
+
 func testManagerConnectIncludesConvey(t *testing.T) {
 	var (
 		assert      = assert.New(t)
@@ -354,19 +515,184 @@ func testManagerConnectIncludesConvey(t *testing.T) {
 	assert.Equal("WebPA-1.6", convey["webpa-protocol"])
 }
 
+func testManagerConnectAuthStatus(t *testing.T) {
+	var (
+		assert  = assert.New(t)
+		require = require.New(t)
+		sent    = make(chan *Event, 1)
+
+		options = &Options{
+			Logger:     logging.NewTestLogger(nil, t),
+			AuthStatus: http.StatusOK,
+			Listeners: []Listener{
+				func(event *Event) {
+					if event.Type == MessageSent {
+						sent <- event
+					}
+				},
+			},
+		}
+
+		_, server, connectURL = startWebsocketServer(options)
+	)
+
+	defer server.Close()
+
+	deviceConnection, _, err := DefaultDialer().DialDevice(string(testDeviceIDs[0]), connectURL, nil)
+	require.NoError(err)
+	defer deviceConnection.Close()
+
+	deviceConnection.SetReadDeadline(time.Now().Add(5 * time.Second))
+	messageType, frame, err := deviceConnection.ReadMessage()
+	require.NoError(err)
+	assert.Equal(websocket.BinaryMessage, messageType)
+
+	var auth wrp.Authorization
+	require.NoError(wrp.NewDecoderBytes(frame, wrp.Msgpack).Decode(&auth))
+	assert.Equal(wrp.AuthMessageType, auth.Type)
+	assert.Equal(int64(http.StatusOK), auth.Status)
+
+	select {
+	case event := <-sent:
+		assert.Equal(&auth, event.Message)
+		assert.Equal(frame, event.Contents)
+	case <-time.After(5 * time.Second):
+		assert.Fail("No MessageSent event was dispatched for the authorization status")
+	}
+}
+
+func testManagerReadPumpValidation(t *testing.T) {
+	var (
+		assert   = assert.New(t)
+		received = make(chan *wrp.Message, 2)
+
+		options = &Options{
+			Logger:    logging.NewTestLogger(nil, t),
+			Validator: wrp.DefaultValidator(),
+			Listeners: []Listener{
+				func(event *Event) {
+					if event.Type == MessageReceived {
+						received <- event.Message.(*wrp.Message)
+					}
+				},
+			},
+		}
+
+		_, server, connectURL = startWebsocketServer(options)
+	)
+
+	defer server.Close()
+
+	deviceConnection, _, err := DefaultDialer().DialDevice(string(testDeviceIDs[0]), connectURL, nil)
+	if !assert.NoError(err) {
+		return
+	}
+
+	defer deviceConnection.Close()
+
+	invalid := wrp.MustEncode(&wrp.SimpleEvent{Source: string(testDeviceIDs[0])}, wrp.Msgpack)
+	valid := wrp.MustEncode(&wrp.SimpleEvent{Source: string(testDeviceIDs[0]), Destination: "event:device-status"}, wrp.Msgpack)
+	assert.NoError(deviceConnection.WriteMessage(websocket.BinaryMessage, invalid))
+	assert.NoError(deviceConnection.WriteMessage(websocket.BinaryMessage, valid))
+
+	select {
+	case message := <-received:
+		assert.Equal("event:device-status", message.Destination)
+	case <-time.After(5 * time.Second):
+		assert.Fail("The valid message was not received")
+	}
+
+	select {
+	case message := <-received:
+		assert.Fail("Only one message should have been received", "%v", message)
+	default:
+	}
+}
+
+func testManagerReadPumpDecompression(t *testing.T) {
+	var (
+		assert   = assert.New(t)
+		require  = require.New(t)
+		received = make(chan *Event, 3)
+
+		options = &Options{
+			Logger:             logging.NewTestLogger(nil, t),
+			DecompressPayloads: true,
+			Listeners: []Listener{
+				func(event *Event) {
+					if event.Type == MessageReceived {
+						received <- event
+					}
+				},
+			},
+		}
+
+		_, server, connectURL = startWebsocketServer(options)
+
+		expectedPayload = bytes.Repeat([]byte("a large device response "), 100)
+		compressed      = &wrp.Message{
+			Type:        wrp.SimpleEventMessageType,
+			Source:      string(testDeviceIDs[0]),
+			Destination: "event:device-status",
+			Payload:     expectedPayload,
+		}
+	)
+
+	defer server.Close()
+	require.NoError(wrp.CompressPayload(compressed, wrp.GzipEncoding))
+
+	deviceConnection, _, err := DefaultDialer().DialDevice(string(testDeviceIDs[0]), connectURL, nil)
+	require.NoError(err)
+	defer deviceConnection.Close()
+
+	corrupt := *compressed
+	corrupt.Payload = []byte("this is not gzip")
+
+	assert.NoError(deviceConnection.WriteMessage(websocket.BinaryMessage, wrp.MustEncode(&corrupt, wrp.Msgpack)))
+	assert.NoError(deviceConnection.WriteMessage(websocket.BinaryMessage, wrp.MustEncode(compressed, wrp.Msgpack)))
+
+	select {
+	case event := <-received:
+		message := event.Message.(*wrp.Message)
+		assert.Equal(expectedPayload, message.Payload)
+		assert.Empty(message.ContentEncoding)
+
+		var decoded wrp.Message
+		require.NoError(wrp.NewDecoderBytes(event.Contents, wrp.Msgpack).Decode(&decoded))
+		assert.Equal(*message, decoded)
+	case <-time.After(5 * time.Second):
+		assert.Fail("The compressed message was not received")
+	}
+
+	select {
+	case event := <-received:
+		assert.Fail("Only one message should have been received", "%v", event.Message)
+	default:
+	}
+}
+
 func TestManager(t *testing.T) {
 	t.Run("Connect", func(t *testing.T) {
 		t.Run("MissingDeviceContext", testManagerConnectMissingDeviceContext)
 		t.Run("UpgradeError", testManagerConnectUpgradeError)
 		t.Run("Visit", testManagerConnectVisit)
 		t.Run("IncludesConvey", testManagerConnectIncludesConvey)
+		t.Run("AuthStatus", testManagerConnectAuthStatus)
 	})
 
 	t.Run("Route", func(t *testing.T) {
 		t.Run("BadDestination", testManagerRouteBadDestination)
 		t.Run("DeviceNotFound", testManagerRouteDeviceNotFound)
+		t.Run("PartnerIDsEnforced", func(t *testing.T) { testManagerRoutePartnerIDs(t, true) })
+		t.Run("PartnerIDsNotEnforced", func(t *testing.T) { testManagerRoutePartnerIDs(t, false) })
+		t.Run("MultiPart", func(t *testing.T) {
+			t.Run("AcceptParts", func(t *testing.T) { testManagerRouteMultiPart(t, true) })
+			t.Run("DiscardParts", func(t *testing.T) { testManagerRouteMultiPart(t, false) })
+		})
 	})
 
+	t.Run("ReadPumpValidation", testManagerReadPumpValidation)
+	t.Run("ReadPumpDecompression", testManagerReadPumpDecompression)
 	t.Run("Disconnect", testManagerDisconnect)
 	t.Run("DisconnectIf", testManagerDisconnectIf)
 }
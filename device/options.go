@@ -4,6 +4,7 @@ import (
 	"time"
 
 	"github.com/Comcast/webpa-common/logging"
+	"github.com/Comcast/webpa-common/wrp"
 	"github.com/go-kit/kit/log"
 	"github.com/go-kit/kit/metrics/provider"
 	"github.com/gorilla/websocket"
@@ -25,6 +26,7 @@ const (
 	DefaultReadBufferSize         = 0
 	DefaultWriteBufferSize        = 0
 	DefaultDeviceMessageQueueSize = 100
+	DefaultMaxPendingParts        = 100
 )
 
 // Options represent the available configuration options for components
@@ -55,6 +57,11 @@ type Options struct {
 	// DefaultWriteTimeout is used.
 	WriteTimeout time.Duration
 
+	// MaxPendingParts is the number of parts of a multi-part response that may be received from a device but
+	// not yet consumed by the goroutine waiting on the transaction.  A transaction that exceeds this limit is
+	// cancelled.  If not supplied, DefaultMaxPendingParts is used.
+	MaxPendingParts int
+
 	// Listeners contains the event sinks for managers created using these options
 	Listeners []Listener
 
@@ -67,6 +74,37 @@ type Options struct {
 
 	// Now is the closure used to determine the current time.  If not set, time.Now is used.
 	Now func() time.Time
+
+	// Validator is the optional strategy used to check WRP messages read from devices.  Messages that
+	// fail validation are logged and dropped, as with malformed messages.  If unset, messages are not validated.
+	Validator wrp.Validator
+
+	// DecompressPayloads enables transparent decompression of payloads read from devices.  When set, messages
+	// whose content_encoding is set are decompressed after validation, so that listeners and transactions
+	// see the original payload while validators, such as signature verifiers, see the message as it was sent.
+	// Messages that cannot be decompressed are logged and dropped.
+	DecompressPayloads bool
+
+	// MaxDecompressedSize is the largest payload, in bytes, that a device message may decompress to when
+	// DecompressPayloads is set.  Larger messages are logged and dropped.  If nonpositive,
+	// wrp.DefaultMaxDecompressedSize is used.
+	MaxDecompressedSize int
+
+	// AuthStatus is the status sent to each device in a WRP Authorization message immediately after it connects.
+	// The Authorization message is always the first message written to a device.  If unset, no Authorization
+	// message is sent.
+	AuthStatus int64
+
+	// EnforcePartnerIDs causes a Manager to reject routed requests whose caller does not belong to at least one of
+	// the target device's partners.  The caller's partner ids are Request.PartnerIDs, which MessageHandler takes from
+	// the validated JWT claims of the HTTP request.  A device's partner ids come from the JWT claims of its connect
+	// request.  Devices with no partner ids are not restricted.  Rejected requests produce a *PartnerMismatchError.
+	EnforcePartnerIDs bool
+
+	// ConveyPartnerIDs allows a device's partner ids to be taken from the ConveyPartnerIDKey of its convey data
+	// when its connect request has no JWT partner ids.  Convey data is asserted by the device itself and is not
+	// authenticated, so this should only be set when devices are trusted.
+	ConveyPartnerIDs bool
 }
 
 func (o *Options) upgrader() *websocket.Upgrader {
@@ -86,6 +124,14 @@ func (o *Options) deviceMessageQueueSize() int {
 	return DefaultDeviceMessageQueueSize
 }
 
+func (o *Options) maxPendingParts() int {
+	if o != nil && o.MaxPendingParts > 0 {
+		return o.MaxPendingParts
+	}
+
+	return DefaultMaxPendingParts
+}
+
 func (o *Options) maxDevices() int {
 	if o != nil && o.MaxDevices > 0 {
 		return o.MaxDevices
@@ -157,3 +203,51 @@ func (o *Options) now() func() time.Time {
 
 	return time.Now
 }
+
+func (o *Options) validator() wrp.Validator {
+	if o != nil {
+		return o.Validator
+	}
+
+	return nil
+}
+
+func (o *Options) decompressPayloads() bool {
+	if o != nil {
+		return o.DecompressPayloads
+	}
+
+	return false
+}
+
+func (o *Options) maxDecompressedSize() int {
+	if o != nil && o.MaxDecompressedSize > 0 {
+		return o.MaxDecompressedSize
+	}
+
+	return wrp.DefaultMaxDecompressedSize
+}
+
+func (o *Options) authStatus() int64 {
+	if o != nil {
+		return o.AuthStatus
+	}
+
+	return 0
+}
+
+func (o *Options) enforcePartnerIDs() bool {
+	if o != nil {
+		return o.EnforcePartnerIDs
+	}
+
+	return false
+}
+
+func (o *Options) conveyPartnerIDs() bool {
+	if o != nil {
+		return o.ConveyPartnerIDs
+	}
+
+	return false
+}
@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"github.com/Comcast/webpa-common/logging"
+	"github.com/Comcast/webpa-common/wrp"
 	"github.com/go-kit/kit/metrics/provider"
 	"github.com/gorilla/websocket"
 	"github.com/stretchr/testify/assert"
@@ -17,6 +18,7 @@ func TestOptionsDefault(t *testing.T) {
 		t.Log(o)
 
 		assert.Equal(DefaultDeviceMessageQueueSize, o.deviceMessageQueueSize())
+		assert.Equal(DefaultMaxPendingParts, o.maxPendingParts())
 		assert.NotNil(o.upgrader())
 		assert.Equal(0, o.maxDevices())
 		assert.Equal(DefaultIdlePeriod, o.idlePeriod())
@@ -25,6 +27,9 @@ func TestOptionsDefault(t *testing.T) {
 		assert.NotNil(o.logger())
 		assert.Empty(o.listeners())
 		assert.Equal(provider.NewDiscardProvider(), o.metricsProvider())
+		assert.False(o.enforcePartnerIDs())
+		assert.False(o.conveyPartnerIDs())
+		assert.Equal(wrp.DefaultMaxDecompressedSize, o.maxDecompressedSize())
 	}
 }
 
@@ -43,16 +48,21 @@ func TestOptions(t *testing.T) {
 			},
 			MaxDevices:             20000,
 			DeviceMessageQueueSize: DefaultDeviceMessageQueueSize + 287342,
+			MaxPendingParts:        DefaultMaxPendingParts + 12,
 			IdlePeriod:             DefaultIdlePeriod + 3472*time.Minute,
 			PingPeriod:             DefaultPingPeriod + 384*time.Millisecond,
 			WriteTimeout:           DefaultWriteTimeout + 327193*time.Second,
 			Logger:                 expectedLogger,
 			Listeners:              []Listener{func(*Event) {}},
 			MetricsProvider:        expectedMetricsProvider,
+			EnforcePartnerIDs:      true,
+			ConveyPartnerIDs:       true,
+			MaxDecompressedSize:    1024,
 		}
 	)
 
 	assert.Equal(o.DeviceMessageQueueSize, o.deviceMessageQueueSize())
+	assert.Equal(o.MaxPendingParts, o.maxPendingParts())
 	assert.Equal(
 		websocket.Upgrader{
 			HandshakeTimeout: 12377123 * time.Second,
@@ -70,4 +80,7 @@ func TestOptions(t *testing.T) {
 	assert.Equal(expectedLogger, o.logger())
 	assert.Equal(o.Listeners, o.listeners())
 	assert.Equal(expectedMetricsProvider, o.metricsProvider())
+	assert.True(o.enforcePartnerIDs())
+	assert.True(o.conveyPartnerIDs())
+	assert.Equal(1024, o.maxDecompressedSize())
 }
@@ -0,0 +1,100 @@
+package device
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/Comcast/webpa-common/convey"
+	"github.com/Comcast/webpa-common/secure/handler"
+)
+
+// ConveyPartnerIDKey is the convey key that may carry the partner id of a device when it connects.  The value
+// may be either a single string or an array of strings.
+const ConveyPartnerIDKey = "partner-id"
+
+// PartnerMismatchError is returned by Manager.Route when partner ids are enforced and none of the partner ids
+// of a request's caller belong to the target device.
+type PartnerMismatchError struct {
+	// ID is the identifier of the target device
+	ID ID
+
+	// Expected is the set of partner ids associated with the target device
+	Expected []string
+
+	// Actual is the set of authenticated partner ids of the request's caller
+	Actual []string
+}
+
+func (pme *PartnerMismatchError) Error() string {
+	return fmt.Sprintf("Partner ids %q do not match device %s partner ids %q", pme.Actual, pme.ID, pme.Expected)
+}
+
+// StatusCode allows a PartnerMismatchError to be used as a go-kit StatusCoder
+func (pme *PartnerMismatchError) StatusCode() int {
+	return http.StatusForbidden
+}
+
+// connectPartnerIDs determines the partner ids of a connecting device.  The partner ids from the JWT claims
+// of the connect request, if any, are used.  Otherwise, if useConvey is set, the partner ids are taken from the
+// device's convey data.  Convey data is asserted by the device itself and is not authenticated.
+func connectPartnerIDs(request *http.Request, c convey.C, useConvey bool) []string {
+	if partnerIDs := callerPartnerIDs(request); len(partnerIDs) > 0 {
+		return partnerIDs
+	} else if !useConvey {
+		return nil
+	}
+
+	switch v := c[ConveyPartnerIDKey].(type) {
+	case string:
+		if len(v) > 0 {
+			return []string{v}
+		}
+
+	case []interface{}:
+		partnerIDs := make([]string, 0, len(v))
+		for _, e := range v {
+			if s, ok := e.(string); ok && len(s) > 0 {
+				partnerIDs = append(partnerIDs, s)
+			}
+		}
+
+		if len(partnerIDs) > 0 {
+			return partnerIDs
+		}
+	}
+
+	return nil
+}
+
+// callerPartnerIDs returns the partner ids from the validated JWT claims of an HTTP request, if any
+func callerPartnerIDs(request *http.Request) []string {
+	if values, ok := handler.FromContext(request.Context()); ok && values != nil {
+		return values.PartnerIDs
+	}
+
+	return nil
+}
+
+// checkPartnerIDs verifies that a request's caller belongs to at least one of the given device's partners.
+// The partner ids carried in the request's WRP message are supplied by the caller and are never consulted.
+// A device with no known partner ids accepts any request.
+func checkPartnerIDs(d *device, request *Request) error {
+	if len(d.partnerIDs) == 0 {
+		return nil
+	}
+
+	actual := request.PartnerIDs
+	for _, a := range actual {
+		for _, e := range d.partnerIDs {
+			if a == e {
+				return nil
+			}
+		}
+	}
+
+	return &PartnerMismatchError{
+		ID:       d.id,
+		Expected: d.partnerIDs,
+		Actual:   actual,
+	}
+}
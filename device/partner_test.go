@@ -0,0 +1,65 @@
+package device
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Comcast/webpa-common/convey"
+	"github.com/Comcast/webpa-common/secure/handler"
+	"github.com/stretchr/testify/assert"
+)
+
+func testConnectPartnerIDsFromClaims(t *testing.T) {
+	var (
+		assert  = assert.New(t)
+		request = httptest.NewRequest("GET", "/", nil)
+	)
+
+	request = request.WithContext(
+		handler.NewContextWithValue(request.Context(), &handler.ContextValues{PartnerIDs: []string{"comcast"}}),
+	)
+
+	assert.Equal([]string{"comcast"}, connectPartnerIDs(request, convey.C{ConveyPartnerIDKey: "cox"}, true))
+	assert.Equal([]string{"comcast"}, connectPartnerIDs(request, convey.C{ConveyPartnerIDKey: "cox"}, false))
+}
+
+func testConnectPartnerIDsFromConvey(t *testing.T) {
+	var (
+		assert  = assert.New(t)
+		request = httptest.NewRequest("GET", "/", nil)
+	)
+
+	// convey data is only consulted when allowed
+	assert.Nil(connectPartnerIDs(request, convey.C{ConveyPartnerIDKey: "cox"}, false))
+
+	assert.Nil(connectPartnerIDs(request, nil, true))
+	assert.Nil(connectPartnerIDs(request, convey.C{}, true))
+	assert.Nil(connectPartnerIDs(request, convey.C{ConveyPartnerIDKey: ""}, true))
+	assert.Nil(connectPartnerIDs(request, convey.C{ConveyPartnerIDKey: 123}, true))
+	assert.Equal([]string{"cox"}, connectPartnerIDs(request, convey.C{ConveyPartnerIDKey: "cox"}, true))
+	assert.Equal(
+		[]string{"cox", "shaw"},
+		connectPartnerIDs(request, convey.C{ConveyPartnerIDKey: []interface{}{"cox", 123, "", "shaw"}}, true),
+	)
+
+	// empty claims fall back to the convey data
+	request = request.WithContext(handler.NewContextWithValue(request.Context(), new(handler.ContextValues)))
+	assert.Equal([]string{"cox"}, connectPartnerIDs(request, convey.C{ConveyPartnerIDKey: "cox"}, true))
+}
+
+func TestConnectPartnerIDs(t *testing.T) {
+	t.Run("FromClaims", testConnectPartnerIDsFromClaims)
+	t.Run("FromConvey", testConnectPartnerIDsFromConvey)
+}
+
+func TestPartnerMismatchError(t *testing.T) {
+	var (
+		assert = assert.New(t)
+		err    = &PartnerMismatchError{ID: "mac:112233445566", Expected: []string{"comcast"}, Actual: []string{"cox"}}
+	)
+
+	assert.Equal(403, err.StatusCode())
+	assert.Contains(err.Error(), "mac:112233445566")
+	assert.Contains(err.Error(), `"comcast"`)
+	assert.Contains(err.Error(), `"cox"`)
+}
@@ -0,0 +1,51 @@
+package device
+
+import (
+	"strconv"
+
+	"github.com/Comcast/webpa-common/wrp"
+)
+
+const (
+	// PartSequenceKey is the WRP metadata key whose value is the zero-based sequence number of a message that
+	// is one part of a multi-part transaction response.  Messages without this key are complete responses.
+	PartSequenceKey = "part-sequence"
+
+	// PartLastKey is the WRP metadata key that marks the final part of a multi-part transaction response.
+	// A part is the last part when this key has the value "true".
+	PartLastKey = "part-last"
+)
+
+// Part describes the position of a message within a multi-part transaction response.  Devices that return large
+// results may send several messages with the same transaction UUID, each carrying the PartSequenceKey metadata.
+// The transaction completes with the part that carries PartLastKey.
+type Part struct {
+	// Sequence is the zero-based position of this part within the response
+	Sequence int
+
+	// Last indicates that this is the final part of the response
+	Last bool
+}
+
+// GetPart returns the multi-part markers of a message.  If the message is a complete response by itself,
+// this function returns nil.  If the markers are present but malformed, ErrorInvalidPart is returned.
+func GetPart(m *wrp.Message) (*Part, error) {
+	value, ok := m.Metadata[PartSequenceKey]
+	if !ok {
+		return nil, nil
+	}
+
+	sequence, err := strconv.Atoi(value)
+	if err != nil || sequence < 0 {
+		return nil, ErrorInvalidPart
+	}
+
+	part := &Part{Sequence: sequence}
+	if last, ok := m.Metadata[PartLastKey]; ok {
+		if part.Last, err = strconv.ParseBool(last); err != nil {
+			return nil, ErrorInvalidPart
+		}
+	}
+
+	return part, nil
+}
@@ -0,0 +1,50 @@
+package device
+
+import (
+	"testing"
+
+	"github.com/Comcast/webpa-common/wrp"
+	"github.com/stretchr/testify/assert"
+)
+
+func testGetPartValid(t *testing.T) {
+	assert := assert.New(t)
+
+	testData := []struct {
+		metadata map[string]string
+		expected *Part
+	}{
+		{nil, nil},
+		{map[string]string{"foo": "bar"}, nil},
+		{map[string]string{PartLastKey: "true"}, nil},
+		{map[string]string{PartSequenceKey: "0"}, &Part{Sequence: 0}},
+		{map[string]string{PartSequenceKey: "12", PartLastKey: "false"}, &Part{Sequence: 12}},
+		{map[string]string{PartSequenceKey: "3", PartLastKey: "true"}, &Part{Sequence: 3, Last: true}},
+	}
+
+	for _, record := range testData {
+		actual, err := GetPart(&wrp.Message{Metadata: record.metadata})
+		assert.Equal(record.expected, actual)
+		assert.NoError(err)
+	}
+}
+
+func testGetPartInvalid(t *testing.T) {
+	assert := assert.New(t)
+
+	for _, metadata := range []map[string]string{
+		{PartSequenceKey: ""},
+		{PartSequenceKey: "first"},
+		{PartSequenceKey: "-1"},
+		{PartSequenceKey: "1", PartLastKey: "maybe"},
+	} {
+		actual, err := GetPart(&wrp.Message{Metadata: metadata})
+		assert.Nil(actual)
+		assert.Equal(ErrorInvalidPart, err)
+	}
+}
+
+func TestGetPart(t *testing.T) {
+	t.Run("Valid", testGetPartValid)
+	t.Run("Invalid", testGetPartInvalid)
+}
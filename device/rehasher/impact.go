@@ -0,0 +1,174 @@
+package rehasher
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"sync/atomic"
+
+	"github.com/go-kit/kit/log/level"
+
+	"github.com/Comcast/webpa-common/device"
+	"github.com/Comcast/webpa-common/logging"
+	"github.com/Comcast/webpa-common/service"
+	"github.com/Comcast/webpa-common/service/monitor"
+	"github.com/Comcast/webpa-common/xhttp"
+)
+
+// DefaultSampleSize is the number of disconnected device identifiers included in a Report when no sample size is configured
+const DefaultSampleSize = 10
+
+var errNoInstances = errors.New("No instances are known.  Supply one or more instance parameters.")
+
+// Report describes the impact a rehash has, or would have, on the devices connected to this instance.
+type Report struct {
+	// Instances is the set of service instances the devices were hashed against
+	Instances []string `json:"instances"`
+
+	// Total is the number of devices examined
+	Total int `json:"total"`
+
+	// Keep is the number of devices that hash to this instance
+	Keep int `json:"keep"`
+
+	// Disconnect is the number of devices that would be disconnected, including devices which could not be hashed
+	Disconnect int `json:"disconnect"`
+
+	// Errors is the number of devices that could not be hashed.  These devices are included in Disconnect.
+	Errors int `json:"errors"`
+
+	// Moves is the count of disconnected devices by the instance each device hashed to
+	Moves map[string]int `json:"moves"`
+
+	// Samples are the identifiers of some of the devices that would be disconnected
+	Samples []device.ID `json:"samples"`
+}
+
+// reportBuilder accumulates a Report as devices are hashed
+type reportBuilder struct {
+	report     Report
+	sampleSize int
+}
+
+func newReportBuilder(instances []string, sampleSize int) *reportBuilder {
+	return &reportBuilder{
+		report: Report{
+			Instances: instances,
+			Moves:     make(map[string]int),
+			Samples:   make([]device.ID, 0, sampleSize),
+		},
+		sampleSize: sampleSize,
+	}
+}
+
+// add records the rehash outcome for a single device
+func (rb *reportBuilder) add(id device.ID, instance string, keep bool, err error) {
+	rb.report.Total++
+	if keep {
+		rb.report.Keep++
+		return
+	}
+
+	rb.report.Disconnect++
+	if err != nil {
+		rb.report.Errors++
+	} else {
+		rb.report.Moves[instance]++
+	}
+
+	if len(rb.report.Samples) < rb.sampleSize {
+		rb.report.Samples = append(rb.report.Samples, id)
+	}
+}
+
+// classify determines where a device hashes and whether it should remain connected to this instance
+func classify(accessor service.Accessor, isRegistered func(string) bool, id device.ID) (instance string, keep bool, err error) {
+	instance, err = accessor.Get(id.Bytes())
+	keep = err == nil && isRegistered(instance)
+	return
+}
+
+// Impact is an HTTP handler that reports which connected devices a rehash would disconnect, without disconnecting
+// any of them.  Impact is also a monitor.Listener, so that it can track the current set of instances.  Register it
+// with the same monitor as the rehasher.
+//
+// If the request has one or more instance parameters, e.g. ?instance=http://host1:8080&instance=http://host2:8080,
+// the report is computed against that hypothetical set of instances.  Otherwise, the current instances are used.
+type Impact struct {
+	// Registry is the set of connected devices to examine.  This field is required.
+	Registry device.Registry
+
+	// AccessorFactory creates the service.Accessor used to hash devices.  If unset, service.DefaultAccessorFactory is used.
+	AccessorFactory service.AccessorFactory
+
+	// IsRegistered determines if an instance refers to this process.  This field is required.
+	IsRegistered func(string) bool
+
+	// SampleSize is the maximum number of disconnected device identifiers in a report.  If unset, DefaultSampleSize is used.
+	SampleSize int
+
+	current atomic.Value
+}
+
+// MonitorEvent records the current set of instances
+func (i *Impact) MonitorEvent(e monitor.Event) {
+	if e.Err == nil && !e.Stopped && len(e.Instances) > 0 {
+		i.current.Store(e.Instances)
+	}
+}
+
+// Report computes the impact of rehashing the connected devices against the given instances
+func (i *Impact) Report(instances []string) Report {
+	af := i.AccessorFactory
+	if af == nil {
+		af = service.DefaultAccessorFactory
+	}
+
+	sampleSize := i.SampleSize
+	if sampleSize < 1 {
+		sampleSize = DefaultSampleSize
+	}
+
+	var (
+		accessor = af(instances)
+		rb       = newReportBuilder(instances, sampleSize)
+	)
+
+	i.Registry.VisitAll(func(d device.Interface) bool {
+		id := d.ID()
+		instance, keep, err := classify(accessor, i.IsRegistered, id)
+		rb.add(id, instance, keep, err)
+		return true
+	})
+
+	return rb.report
+}
+
+func (i *Impact) ServeHTTP(response http.ResponseWriter, request *http.Request) {
+	logger := logging.GetLogger(request.Context())
+	if err := request.ParseForm(); err != nil {
+		logger.Log(level.Key(), level.ErrorValue(), logging.MessageKey(), "unable to parse form", logging.ErrorKey(), err)
+		xhttp.WriteError(response, http.StatusBadRequest, err)
+		return
+	}
+
+	instances := request.Form["instance"]
+	if len(instances) == 0 {
+		instances, _ = i.current.Load().([]string)
+	}
+
+	if len(instances) == 0 {
+		xhttp.WriteError(response, http.StatusBadRequest, errNoInstances)
+		return
+	}
+
+	message, err := json.Marshal(i.Report(instances))
+	if err != nil {
+		xhttp.WriteError(response, http.StatusInternalServerError, err)
+		return
+	}
+
+	response.Header().Set("Content-Type", "application/json")
+	response.WriteHeader(http.StatusOK)
+	response.Write(message)
+}
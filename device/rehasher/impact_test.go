@@ -0,0 +1,144 @@
+package rehasher
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Comcast/webpa-common/device"
+	"github.com/Comcast/webpa-common/service"
+	"github.com/Comcast/webpa-common/service/monitor"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	impactKeepID  = device.IntToMAC(1)
+	impactMoveID  = device.IntToMAC(2)
+	impactErrorID = device.IntToMAC(3)
+)
+
+// impactAccessorFactory produces accessors that hash the impact test devices based on the instances
+func impactAccessorFactory(instances []string) service.Accessor {
+	a := new(service.MockAccessor)
+	a.On("Get", impactKeepID.Bytes()).Return("keep", error(nil))
+	a.On("Get", impactMoveID.Bytes()).Return(instances[len(instances)-1], error(nil))
+	a.On("Get", impactErrorID.Bytes()).Return("", errors.New("expected"))
+	return a
+}
+
+func newTestImpact(sampleSize int) *Impact {
+	return &Impact{
+		Registry:        newStubManager(impactKeepID, impactMoveID, impactErrorID),
+		AccessorFactory: impactAccessorFactory,
+		IsRegistered:    func(instance string) bool { return instance == "keep" },
+		SampleSize:      sampleSize,
+	}
+}
+
+func testImpactReport(t *testing.T) {
+	var (
+		assert  = assert.New(t)
+		require = require.New(t)
+		impact  = newTestImpact(0)
+	)
+
+	report := impact.Report([]string{"keep", "other"})
+	assert.Equal([]string{"keep", "other"}, report.Instances)
+	assert.Equal(3, report.Total)
+	assert.Equal(1, report.Keep)
+	assert.Equal(2, report.Disconnect)
+	assert.Equal(1, report.Errors)
+	assert.Equal(map[string]int{"other": 1}, report.Moves)
+	require.Len(report.Samples, 2)
+	assert.ElementsMatch([]device.ID{impactMoveID, impactErrorID}, report.Samples)
+
+	report = impact.Report([]string{"keep"})
+	assert.Equal(3, report.Total)
+	assert.Equal(2, report.Keep)
+	assert.Equal(1, report.Disconnect)
+	assert.Equal(1, report.Errors)
+	assert.Empty(report.Moves)
+}
+
+func testImpactReportSampleSize(t *testing.T) {
+	var (
+		assert = assert.New(t)
+		impact = newTestImpact(1)
+	)
+
+	report := impact.Report([]string{"other"})
+	assert.Equal(2, report.Disconnect)
+	assert.Len(report.Samples, 1)
+}
+
+func testImpactServeHTTPNoInstances(t *testing.T) {
+	var (
+		assert   = assert.New(t)
+		impact   = newTestImpact(0)
+		response = httptest.NewRecorder()
+		request  = httptest.NewRequest("GET", "/", nil)
+	)
+
+	impact.MonitorEvent(monitor.Event{Err: errors.New("expected")})
+	impact.ServeHTTP(response, request)
+	assert.Equal(http.StatusBadRequest, response.Code)
+}
+
+func testImpactServeHTTPParseFormError(t *testing.T) {
+	var (
+		assert   = assert.New(t)
+		impact   = newTestImpact(0)
+		response = httptest.NewRecorder()
+		request  = httptest.NewRequest("GET", "/foo?%TT*&&", nil)
+	)
+
+	impact.ServeHTTP(response, request)
+	assert.Equal(http.StatusBadRequest, response.Code)
+}
+
+func testImpactServeHTTPCurrent(t *testing.T) {
+	var (
+		assert   = assert.New(t)
+		impact   = newTestImpact(0)
+		response = httptest.NewRecorder()
+		request  = httptest.NewRequest("GET", "/", nil)
+	)
+
+	impact.MonitorEvent(monitor.Event{EventCount: 1, Instances: []string{"keep"}})
+	impact.MonitorEvent(monitor.Event{EventCount: 2, Stopped: true})
+	impact.ServeHTTP(response, request)
+	assert.Equal(http.StatusOK, response.Code)
+	assert.Equal("application/json", response.HeaderMap.Get("Content-Type"))
+	assert.JSONEq(
+		`{"instances": ["keep"], "total": 3, "keep": 2, "disconnect": 1, "errors": 1, "moves": {}, "samples": ["`+string(impactErrorID)+`"]}`,
+		response.Body.String(),
+	)
+}
+
+func testImpactServeHTTPHypothetical(t *testing.T) {
+	var (
+		assert   = assert.New(t)
+		impact   = newTestImpact(0)
+		response = httptest.NewRecorder()
+		request  = httptest.NewRequest("GET", "/?instance=keep&instance=other", nil)
+	)
+
+	impact.MonitorEvent(monitor.Event{EventCount: 1, Instances: []string{"keep"}})
+	impact.ServeHTTP(response, request)
+	assert.Equal(http.StatusOK, response.Code)
+	assert.Contains(response.Body.String(), `"instances":["keep","other"]`)
+	assert.Contains(response.Body.String(), `"moves":{"other":1}`)
+}
+
+func TestImpact(t *testing.T) {
+	t.Run("Report", testImpactReport)
+	t.Run("ReportSampleSize", testImpactReportSampleSize)
+	t.Run("ServeHTTP", func(t *testing.T) {
+		t.Run("NoInstances", testImpactServeHTTPNoInstances)
+		t.Run("ParseFormError", testImpactServeHTTPParseFormError)
+		t.Run("Current", testImpactServeHTTPCurrent)
+		t.Run("Hypothetical", testImpactServeHTTPHypothetical)
+	})
+}
@@ -1,6 +1,7 @@
 package rehasher
 
 import (
+	"sync"
 	"time"
 
 	"github.com/go-kit/kit/log"
@@ -9,6 +10,7 @@ import (
 	"github.com/go-kit/kit/metrics/provider"
 
 	"github.com/Comcast/webpa-common/device"
+	"github.com/Comcast/webpa-common/device/drain"
 	"github.com/Comcast/webpa-common/logging"
 	"github.com/Comcast/webpa-common/service"
 	"github.com/Comcast/webpa-common/service/monitor"
@@ -69,6 +71,55 @@ func WithMetricsProvider(p provider.Provider) Option {
 		r.disconnectAllCounter = p.NewCounter(RehashDisconnectAllCounter)
 		r.timestamp = p.NewGauge(RehashTimestamp)
 		r.duration = p.NewGauge(RehashDurationMilliseconds)
+		r.pendingMoves = p.NewGauge(RehashPendingMoves)
+	}
+}
+
+// WithThrottle configures a rehasher to move devices at a fixed rate rather than disconnecting all of them
+// in a single pass.  Devices are disconnected using a drain job against the given registry, at rate devices per tick.
+// If a tick is not supplied, it defaults to 1 second.  A nonpositive rate or a nil registry disables throttling.
+//
+// When throttling is enabled, a new rehash cancels any throttled rehash still in progress.  Devices are checked against
+// the most recent set of instances as they are visited, so devices never move based on a stale topology.
+func WithThrottle(registry device.Registry, rate int, tick time.Duration) Option {
+	return func(r *rehasher) {
+		if registry == nil || rate < 1 {
+			r.registry = nil
+			r.rate = 0
+			r.tick = 0
+			return
+		}
+
+		if tick <= 0 {
+			tick = time.Second
+		}
+
+		r.registry = registry
+		r.rate = rate
+		r.tick = tick
+	}
+}
+
+// WithDryRun configures a rehasher to compute and log the impact of each rehash without disconnecting any devices.
+// The devices in the given registry are examined read-only, as with Impact.  In dry run mode, service discovery
+// errors are logged but do not result in any disconnections.  A nil registry disables dry run mode.
+func WithDryRun(registry device.Registry) Option {
+	return func(r *rehasher) {
+		r.dryRunRegistry = registry
+		r.dryRun = registry != nil
+	}
+}
+
+// WithErrorGracePeriod configures how long a rehasher waits before acting on a service discovery error
+// or an empty set of instances.  If an update with instances arrives during this period, no devices are
+// disconnected due to the error.  A nonpositive value means to act on errors immediately, which is the default.
+func WithErrorGracePeriod(d time.Duration) Option {
+	return func(r *rehasher) {
+		if d > 0 {
+			r.gracePeriod = d
+		} else {
+			r.gracePeriod = 0
+		}
 	}
 }
 
@@ -78,6 +129,9 @@ func WithMetricsProvider(p provider.Provider) Option {
 // If the returned listener encounters any service discovery error, all devices are disconnected.  Otherwise,
 // the IsRegistered strategy is used to determine which devices should still be connected to the Connector.  Devices
 // that hash to instances not registered in this environment are disconnected.
+//
+// WithThrottle and WithErrorGracePeriod can be used to soften these disconnections, so that a transient service
+// discovery problem does not cause a reconnect storm.
 func New(connector device.Connector, options ...Option) monitor.Listener {
 	if connector == nil {
 		panic("A device Connector is required")
@@ -91,12 +145,14 @@ func New(connector device.Connector, options ...Option) monitor.Listener {
 			accessorFactory: service.DefaultAccessorFactory,
 			connector:       connector,
 			now:             time.Now,
+			afterFunc:       defaultAfterFunc,
 
 			keep:                 defaultProvider.NewGauge(RehashKeepDevice),
 			disconnect:           defaultProvider.NewGauge(RehashDisconnectDevice),
 			disconnectAllCounter: defaultProvider.NewCounter(RehashDisconnectAllCounter),
 			timestamp:            defaultProvider.NewGauge(RehashTimestamp),
 			duration:             defaultProvider.NewGauge(RehashDurationMilliseconds),
+			pendingMoves:         defaultProvider.NewGauge(RehashPendingMoves),
 		}
 	)
 
@@ -111,6 +167,10 @@ func New(connector device.Connector, options ...Option) monitor.Listener {
 	return r
 }
 
+func defaultAfterFunc(d time.Duration, f func()) func() bool {
+	return time.AfterFunc(d, f).Stop
+}
+
 // rehasher implements monitor.Listener and (1) disconnects all devices when any service discovery error occurs,
 // and (2) rehashes devices in response to updated instances.
 type rehasher struct {
@@ -119,12 +179,62 @@ type rehasher struct {
 	isRegistered    func(string) bool
 	connector       device.Connector
 	now             func() time.Time
+	afterFunc       func(time.Duration, func()) func() bool
+
+	registry    device.Registry
+	rate        int
+	tick        time.Duration
+	gracePeriod time.Duration
+
+	dryRun         bool
+	dryRunRegistry device.Registry
 
 	keep                 metrics.Gauge
 	disconnect           metrics.Gauge
 	disconnectAllCounter metrics.Counter
 	timestamp            metrics.Gauge
 	duration             metrics.Gauge
+	pendingMoves         metrics.Gauge
+
+	lock      sync.Mutex
+	drainer   drain.Interface
+	graceID   uint64
+	stopGrace func() bool
+}
+
+// shouldDisconnect is the rehash predicate that determines if a device no longer belongs to this instance.
+// The outcome is logged, so this predicate should only be used when the device is disconnected as a result.
+func (r *rehasher) shouldDisconnect(logger log.Logger, accessor service.Accessor, candidate device.ID) bool {
+	instance, keep, err := classify(accessor, r.isRegistered, candidate)
+	return logClassification(logger, candidate, instance, keep, err)
+}
+
+// logClassification logs the outcome of classifying a device during a rehash, and returns true if the device
+// is to be disconnected
+func logClassification(logger log.Logger, candidate device.ID, instance string, keep bool, err error) bool {
+	switch {
+	case err != nil:
+		logger.Log(level.Key(), level.ErrorValue(),
+			logging.MessageKey(), "disconnecting device: error during rehash",
+			logging.ErrorKey(), err,
+			"id", candidate,
+		)
+
+		return true
+
+	case !keep:
+		logger.Log(level.Key(), level.InfoValue(),
+			logging.MessageKey(), "disconnecting device: rehashed to another instance",
+			"instance", instance,
+			"id", candidate,
+		)
+
+		return true
+
+	default:
+		logger.Log(level.Key(), level.DebugValue(), logging.MessageKey(), "device hashed to this instance", "id", candidate)
+		return false
+	}
 }
 
 func (r *rehasher) rehash(key string, logger log.Logger, accessor service.Accessor) {
@@ -137,31 +247,12 @@ func (r *rehasher) rehash(key string, logger log.Logger, accessor service.Access
 		keepCount = 0
 
 		disconnectCount = r.connector.DisconnectIf(func(candidate device.ID) bool {
-			instance, err := accessor.Get(candidate.Bytes())
-			switch {
-			case err != nil:
-				logger.Log(level.Key(), level.ErrorValue(),
-					logging.MessageKey(), "disconnecting device: error during rehash",
-					logging.ErrorKey(), err,
-					"id", candidate,
-				)
-
+			if r.shouldDisconnect(logger, accessor, candidate) {
 				return true
-
-			case !r.isRegistered(instance):
-				logger.Log(level.Key(), level.InfoValue(),
-					logging.MessageKey(), "disconnecting device: rehashed to another instance",
-					"instance", instance,
-					"id", candidate,
-				)
-
-				return true
-
-			default:
-				logger.Log(level.Key(), level.DebugValue(), logging.MessageKey(), "device hashed to this instance", "id", candidate)
-				keepCount++
-				return false
 			}
+
+			keepCount++
+			return false
 		})
 
 		duration = r.now().Sub(start)
@@ -173,6 +264,192 @@ func (r *rehasher) rehash(key string, logger log.Logger, accessor service.Access
 	logger.Log(level.Key(), level.InfoValue(), logging.MessageKey(), "rehash complete", "disconnectCount", disconnectCount, "duration", duration)
 }
 
+// dryRunRehash computes the impact of a rehash, but does not disconnect any devices
+func (r *rehasher) dryRunRehash(key string, logger log.Logger, instances []string, accessor service.Accessor) {
+	logger.Log(level.Key(), level.InfoValue(), logging.MessageKey(), "dry run rehash starting")
+
+	start := r.now()
+	r.timestamp.With(service.ServiceLabel, key).Set(float64(start.UTC().Unix()))
+
+	rb := newReportBuilder(instances, DefaultSampleSize)
+	r.dryRunRegistry.VisitAll(func(d device.Interface) bool {
+		id := d.ID()
+		instance, keep, err := classify(accessor, r.isRegistered, id)
+		rb.add(id, instance, keep, err)
+		return true
+	})
+
+	duration := r.now().Sub(start)
+	r.keep.With(service.ServiceLabel, key).Set(float64(rb.report.Keep))
+	r.disconnect.With(service.ServiceLabel, key).Set(float64(rb.report.Disconnect))
+	r.duration.With(service.ServiceLabel, key).Set(float64(duration / time.Millisecond))
+	logger.Log(level.Key(), level.InfoValue(), logging.MessageKey(), "dry run rehash complete",
+		"total", rb.report.Total,
+		"keepCount", rb.report.Keep,
+		"disconnectCount", rb.report.Disconnect,
+		"errorCount", rb.report.Errors,
+		"moves", rb.report.Moves,
+		"samples", rb.report.Samples,
+		"duration", duration,
+	)
+}
+
+// throttledRehash starts a drain job that moves devices which no longer hash to this instance
+func (r *rehasher) throttledRehash(key string, logger log.Logger, accessor service.Accessor) {
+	logger.Log(level.Key(), level.InfoValue(), logging.MessageKey(), "throttled rehash starting", "rate", r.rate, "tick", r.tick)
+	r.timestamp.With(service.ServiceLabel, key).Set(float64(r.now().UTC().Unix()))
+
+	// the drain job consults the filter each time it visits the registry, so only actual disconnections are logged
+	job, err := r.startDrain(
+		key,
+		logger,
+		filteredRegistry{
+			Registry: r.registry,
+			filter: func(candidate device.ID) bool {
+				_, keep, _ := classify(accessor, r.isRegistered, candidate)
+				return !keep
+			},
+		},
+		loggingConnector{
+			Connector: r.connector,
+			logDisconnect: func(candidate device.ID) {
+				r.shouldDisconnect(logger, accessor, candidate)
+			},
+		},
+	)
+
+	if err != nil {
+		logger.Log(level.Key(), level.ErrorValue(), logging.MessageKey(), "unable to start throttled rehash", logging.ErrorKey(), err)
+		return
+	}
+
+	r.keep.With(service.ServiceLabel, key).Set(float64(r.registry.Len() - job.Count))
+	r.disconnect.With(service.ServiceLabel, key).Set(float64(job.Count))
+	logger.Log(level.Key(), level.InfoValue(), logging.MessageKey(), "throttled rehash started", "pendingMoves", job.Count)
+}
+
+// startDrain cancels any throttled drain job in progress, waiting for it to exit, then starts a new drain job
+// against the given registry and connector.  The pending moves gauge tracks the progress of the job.
+func (r *rehasher) startDrain(key string, logger log.Logger, registry device.Registry, connector device.Connector) (drain.Job, error) {
+	defer r.lock.Unlock()
+	r.lock.Lock()
+
+	r.cancelDrain()
+
+	var (
+		pending = r.pendingMoves.With(service.ServiceLabel, key)
+		d       = drain.New(
+			drain.WithLogger(logger),
+			drain.WithRegistry(registry),
+			drain.WithConnector(connector),
+			drain.WithDrainCounter(pendingAdder{pending}),
+		)
+	)
+
+	done, job, err := d.Start(drain.Job{Rate: r.rate, Tick: r.tick})
+	if err != nil {
+		return job, err
+	}
+
+	r.drainer = d
+	pending.Set(float64(job.Count))
+
+	go func() {
+		<-done
+
+		defer r.lock.Unlock()
+		r.lock.Lock()
+
+		// only clean up if this job was not superseded by another rehash
+		if r.drainer == d {
+			r.drainer = nil
+			pending.Set(0.0)
+		}
+	}()
+
+	return job, nil
+}
+
+// cancelDrain halts any throttled drain job and waits for it to exit.  This method must be invoked under the lock.
+func (r *rehasher) cancelDrain() {
+	if r.drainer != nil {
+		if done, err := r.drainer.Cancel(); err == nil {
+			<-done
+		}
+
+		r.drainer = nil
+	}
+}
+
+// disconnectAll disconnects all devices, at the throttled rate if configured.
+func (r *rehasher) disconnectAll(key string, logger log.Logger, reason string) {
+	if r.dryRun {
+		logger.Log(level.Key(), level.InfoValue(), logging.MessageKey(), "dry run: no devices disconnected", "reason", reason)
+		return
+	}
+
+	if r.registry != nil {
+		if _, err := r.startDrain(key, logger, r.registry, r.connector); err != nil {
+			logger.Log(level.Key(), level.ErrorValue(), logging.MessageKey(), "unable to start throttled disconnect", logging.ErrorKey(), err)
+		}
+	} else {
+		r.connector.DisconnectAll()
+	}
+
+	r.disconnectAllCounter.With(service.ServiceLabel, key, ReasonLabel, reason).Add(1.0)
+}
+
+// disconnectAllAfterGrace disconnects all devices once the grace period elapses, unless cancelGrace is called
+// before then.  If no grace period is configured, devices are disconnected immediately.  If a grace period is
+// already running, it is not extended.
+func (r *rehasher) disconnectAllAfterGrace(key string, logger log.Logger, reason string) {
+	if r.gracePeriod <= 0 {
+		r.disconnectAll(key, logger, reason)
+		return
+	}
+
+	defer r.lock.Unlock()
+	r.lock.Lock()
+
+	if r.stopGrace != nil {
+		logger.Log(level.Key(), level.InfoValue(), logging.MessageKey(), "grace period already running", "reason", reason)
+		return
+	}
+
+	logger.Log(level.Key(), level.InfoValue(), logging.MessageKey(), "grace period starting", "reason", reason, "gracePeriod", r.gracePeriod)
+	r.graceID++
+	id := r.graceID
+	r.stopGrace = r.afterFunc(r.gracePeriod, func() {
+		r.lock.Lock()
+		expired := id == r.graceID
+		if expired {
+			r.stopGrace = nil
+		}
+
+		r.lock.Unlock()
+
+		if expired {
+			logger.Log(level.Key(), level.ErrorValue(), logging.MessageKey(), "disconnecting all devices: grace period expired", "reason", reason)
+			r.disconnectAll(key, logger, reason)
+		}
+	})
+}
+
+// cancelGrace stops any running grace period.  This method returns true if a grace period was cancelled.
+func (r *rehasher) cancelGrace() bool {
+	defer r.lock.Unlock()
+	r.lock.Lock()
+
+	if r.stopGrace == nil {
+		return false
+	}
+
+	r.stopGrace()
+	r.stopGrace = nil
+	r.graceID++
+	return true
+}
+
 func (r *rehasher) MonitorEvent(e monitor.Event) {
 	logger := logging.Enrich(
 		log.With(
@@ -185,11 +462,19 @@ func (r *rehasher) MonitorEvent(e monitor.Event) {
 	switch {
 	case e.Err != nil:
 		logger.Log(level.Key(), level.ErrorValue(), logging.MessageKey(), "disconnecting all devices: service discovery error", logging.ErrorKey(), e.Err)
-		r.connector.DisconnectAll()
-		r.disconnectAllCounter.With(service.ServiceLabel, e.Key, ReasonLabel, DisconnectAllServiceDiscoveryError).Add(1.0)
+		r.disconnectAllAfterGrace(e.Key, logger, DisconnectAllServiceDiscoveryError)
 
 	case e.Stopped:
 		logger.Log(level.Key(), level.ErrorValue(), logging.MessageKey(), "disconnecting all devices: service discovery monitor being stopped")
+		r.cancelGrace()
+		r.lock.Lock()
+		r.cancelDrain()
+		r.lock.Unlock()
+		if r.dryRun {
+			logger.Log(level.Key(), level.InfoValue(), logging.MessageKey(), "dry run: no devices disconnected", "reason", DisconnectAllServiceDiscoveryStopped)
+			return
+		}
+
 		r.connector.DisconnectAll()
 		r.disconnectAllCounter.With(service.ServiceLabel, e.Key, ReasonLabel, DisconnectAllServiceDiscoveryStopped).Add(1.0)
 
@@ -197,11 +482,20 @@ func (r *rehasher) MonitorEvent(e monitor.Event) {
 		logger.Log(level.Key(), level.InfoValue(), logging.MessageKey(), "ignoring initial instances")
 
 	case len(e.Instances) > 0:
-		r.rehash(e.Key, logger, r.accessorFactory(e.Instances))
+		if r.cancelGrace() {
+			logger.Log(level.Key(), level.InfoValue(), logging.MessageKey(), "service discovery recovered during grace period")
+		}
+
+		if r.dryRun {
+			r.dryRunRehash(e.Key, logger, e.Instances, r.accessorFactory(e.Instances))
+		} else if r.registry != nil {
+			r.throttledRehash(e.Key, logger, r.accessorFactory(e.Instances))
+		} else {
+			r.rehash(e.Key, logger, r.accessorFactory(e.Instances))
+		}
 
 	default:
 		logger.Log(level.Key(), level.ErrorValue(), logging.MessageKey(), "disconnecting all devices: service discovery updated with no instances")
-		r.connector.DisconnectAll()
-		r.disconnectAllCounter.With(service.ServiceLabel, e.Key, ReasonLabel, DisconnectAllServiceDiscoveryNoInstances).Add(1.0)
+		r.disconnectAllAfterGrace(e.Key, logger, DisconnectAllServiceDiscoveryNoInstances)
 	}
 }
@@ -2,6 +2,8 @@ package rehasher
 
 import (
 	"errors"
+	"net/http"
+	"sync"
 	"testing"
 	"time"
 
@@ -212,3 +214,365 @@ func TestNew(t *testing.T) {
 	t.Run("WithIsRegistered", testNewWithIsRegistered)
 	t.Run("WithEnvironment", testNewWithEnvironment)
 }
+
+// stubManager is a minimal in-memory device.Registry and device.Connector
+type stubManager struct {
+	lock    sync.RWMutex
+	devices map[device.ID]device.Interface
+}
+
+func newStubManager(ids ...device.ID) *stubManager {
+	sm := &stubManager{devices: make(map[device.ID]device.Interface, len(ids))}
+	for _, id := range ids {
+		d := new(device.MockDevice)
+		d.On("ID").Return(id)
+		sm.devices[id] = d
+	}
+
+	return sm
+}
+
+func (sm *stubManager) Connect(http.ResponseWriter, *http.Request, http.Header) (device.Interface, error) {
+	panic("Connect is not supported")
+}
+
+func (sm *stubManager) Disconnect(id device.ID) bool {
+	defer sm.lock.Unlock()
+	sm.lock.Lock()
+
+	_, ok := sm.devices[id]
+	delete(sm.devices, id)
+	return ok
+}
+
+func (sm *stubManager) DisconnectIf(func(device.ID) bool) int {
+	panic("DisconnectIf is not supported")
+}
+
+func (sm *stubManager) DisconnectAll() int {
+	panic("DisconnectAll is not supported")
+}
+
+func (sm *stubManager) Len() int {
+	defer sm.lock.RUnlock()
+	sm.lock.RLock()
+	return len(sm.devices)
+}
+
+func (sm *stubManager) Get(id device.ID) (device.Interface, bool) {
+	defer sm.lock.RUnlock()
+	sm.lock.RLock()
+	d, ok := sm.devices[id]
+	return d, ok
+}
+
+func (sm *stubManager) VisitAll(p func(device.Interface) bool) int {
+	defer sm.lock.RUnlock()
+	sm.lock.RLock()
+
+	count := 0
+	for _, d := range sm.devices {
+		count++
+		if !p(d) {
+			break
+		}
+	}
+
+	return count
+}
+
+func (sm *stubManager) has(id device.ID) bool {
+	_, ok := sm.Get(id)
+	return ok
+}
+
+// eventually polls a condition until it is true or a timeout elapses
+func eventually(condition func() bool) bool {
+	for deadline := time.Now().Add(5 * time.Second); time.Now().Before(deadline); time.Sleep(10 * time.Millisecond) {
+		if condition() {
+			return true
+		}
+	}
+
+	return false
+}
+
+// messageCounter is a go-kit logger that counts log messages by their message value
+type messageCounter struct {
+	lock   sync.Mutex
+	counts map[interface{}]int
+}
+
+func (mc *messageCounter) Log(keyvals ...interface{}) error {
+	defer mc.lock.Unlock()
+	mc.lock.Lock()
+	for i := 0; i+1 < len(keyvals); i += 2 {
+		if keyvals[i] == logging.MessageKey() {
+			mc.counts[keyvals[i+1]]++
+		}
+	}
+
+	return nil
+}
+
+func (mc *messageCounter) count(message string) int {
+	defer mc.lock.Unlock()
+	mc.lock.Lock()
+	return mc.counts[message]
+}
+
+func testWithThrottle(t *testing.T) {
+	var (
+		assert   = assert.New(t)
+		registry = new(device.MockRegistry)
+		r        = new(rehasher)
+	)
+
+	WithThrottle(registry, 10, 0)(r)
+	assert.Equal(registry, r.registry)
+	assert.Equal(10, r.rate)
+	assert.Equal(time.Second, r.tick)
+
+	WithThrottle(registry, 5, time.Minute)(r)
+	assert.Equal(registry, r.registry)
+	assert.Equal(5, r.rate)
+	assert.Equal(time.Minute, r.tick)
+
+	WithThrottle(registry, 0, time.Minute)(r)
+	assert.Nil(r.registry)
+	assert.Zero(r.rate)
+	assert.Zero(r.tick)
+
+	WithThrottle(nil, 5, time.Minute)(r)
+	assert.Nil(r.registry)
+	assert.Zero(r.rate)
+	assert.Zero(r.tick)
+}
+
+func testWithErrorGracePeriod(t *testing.T) {
+	var (
+		assert = assert.New(t)
+		r      = new(rehasher)
+	)
+
+	WithErrorGracePeriod(time.Minute)(r)
+	assert.Equal(time.Minute, r.gracePeriod)
+
+	WithErrorGracePeriod(-1)(r)
+	assert.Zero(r.gracePeriod)
+}
+
+func testThrottledRehash(t *testing.T) {
+	const key = "testThrottledRehash"
+
+	var (
+		assert   = assert.New(t)
+		require  = require.New(t)
+		provider = xmetricstest.NewProvider(nil, Metrics)
+
+		keepID  = device.IntToMAC(1)
+		moveIDs = []device.ID{device.IntToMAC(2), device.IntToMAC(3), device.IntToMAC(4)}
+		manager = newStubManager(append([]device.ID{keepID}, moveIDs...)...)
+		counter = &messageCounter{counts: make(map[interface{}]int)}
+
+		accessor = service.MapAccessor{
+			string(keepID):     "keep",
+			string(moveIDs[0]): "move",
+			string(moveIDs[1]): "move",
+			string(moveIDs[2]): "move",
+		}
+	)
+
+	l := New(
+		manager,
+		WithLogger(counter),
+		WithAccessorFactory(func([]string) service.Accessor { return accessor }),
+		WithIsRegistered(func(instance string) bool { return instance == "keep" }),
+		WithMetricsProvider(provider),
+		WithThrottle(manager, 1, time.Millisecond),
+	)
+
+	require.NotNil(l)
+	l.MonitorEvent(monitor.Event{Key: key, EventCount: 2, Instances: []string{"keep", "move"}})
+	provider.Assert(t, RehashKeepDevice, service.ServiceLabel, key)(xmetricstest.Value(1.0))
+	provider.Assert(t, RehashDisconnectDevice, service.ServiceLabel, key)(xmetricstest.Value(3.0))
+
+	require.True(
+		eventually(func() bool {
+			r := l.(*rehasher)
+			defer r.lock.Unlock()
+			r.lock.Lock()
+			return r.drainer == nil
+		}),
+		"The throttled rehash did not complete",
+	)
+
+	assert.Equal(1, manager.Len())
+	assert.True(manager.has(keepID))
+
+	// the drain job filters the registry many times, but each moved device is logged only when it is disconnected
+	assert.Equal(len(moveIDs), counter.count("disconnecting device: rehashed to another instance"))
+	assert.Zero(counter.count("device hashed to this instance"))
+	provider.Assert(t, RehashPendingMoves, service.ServiceLabel, key)(xmetricstest.Value(0.0))
+	provider.Assert(t, RehashDisconnectAllCounter, service.ServiceLabel, key, ReasonLabel, DisconnectAllServiceDiscoveryError)(xmetricstest.Value(0.0))
+}
+
+func testGracePeriodRecovered(t *testing.T) {
+	const key = "testGracePeriodRecovered"
+
+	var (
+		assert   = assert.New(t)
+		require  = require.New(t)
+		provider = xmetricstest.NewProvider(nil, Metrics)
+
+		c = new(device.MockConnector)
+		a = new(service.MockAccessor)
+
+		expired    func()
+		stopCalled = false
+	)
+
+	c.On("DisconnectIf", mock.AnythingOfType("func(device.ID) bool")).Return(0).Once()
+
+	l := New(
+		c,
+		WithLogger(logging.NewTestLogger(nil, t)),
+		WithAccessorFactory(func([]string) service.Accessor { return a }),
+		WithIsRegistered(func(string) bool { return true }),
+		WithMetricsProvider(provider),
+		WithErrorGracePeriod(time.Minute),
+	)
+
+	require.NotNil(l)
+	l.(*rehasher).afterFunc = func(d time.Duration, f func()) func() bool {
+		assert.Equal(time.Minute, d)
+		expired = f
+		return func() bool {
+			stopCalled = true
+			return true
+		}
+	}
+
+	l.MonitorEvent(monitor.Event{Key: key, EventCount: 2, Err: errors.New("service discovery error")})
+	require.NotNil(expired)
+
+	// a second error does not restart the grace period
+	expired = nil
+	l.MonitorEvent(monitor.Event{Key: key, EventCount: 3})
+	assert.Nil(expired)
+
+	l.MonitorEvent(monitor.Event{Key: key, EventCount: 4, Instances: []string{"instance"}})
+	assert.True(stopCalled)
+
+	provider.Assert(t, RehashDisconnectAllCounter, service.ServiceLabel, key, ReasonLabel, DisconnectAllServiceDiscoveryError)(xmetricstest.Value(0.0))
+	provider.Assert(t, RehashDisconnectAllCounter, service.ServiceLabel, key, ReasonLabel, DisconnectAllServiceDiscoveryNoInstances)(xmetricstest.Value(0.0))
+
+	a.AssertExpectations(t)
+	c.AssertExpectations(t)
+}
+
+func testGracePeriodExpired(t *testing.T) {
+	const key = "testGracePeriodExpired"
+
+	var (
+		assert   = assert.New(t)
+		require  = require.New(t)
+		provider = xmetricstest.NewProvider(nil, Metrics)
+
+		c = new(device.MockConnector)
+
+		expired func()
+	)
+
+	c.On("DisconnectAll").Return(0).Once()
+
+	l := New(
+		c,
+		WithLogger(logging.NewTestLogger(nil, t)),
+		WithIsRegistered(func(string) bool { return true }),
+		WithMetricsProvider(provider),
+		WithErrorGracePeriod(time.Minute),
+	)
+
+	require.NotNil(l)
+	l.(*rehasher).afterFunc = func(d time.Duration, f func()) func() bool {
+		expired = f
+		return func() bool { return true }
+	}
+
+	l.MonitorEvent(monitor.Event{Key: key, EventCount: 2, Err: errors.New("service discovery error")})
+	require.NotNil(expired)
+	provider.Assert(t, RehashDisconnectAllCounter, service.ServiceLabel, key, ReasonLabel, DisconnectAllServiceDiscoveryError)(xmetricstest.Value(0.0))
+
+	expired()
+	provider.Assert(t, RehashDisconnectAllCounter, service.ServiceLabel, key, ReasonLabel, DisconnectAllServiceDiscoveryError)(xmetricstest.Value(1.0))
+
+	// once expired, the grace period can be started again
+	expired = nil
+	l.MonitorEvent(monitor.Event{Key: key, EventCount: 3, Err: errors.New("service discovery error")})
+	assert.NotNil(expired)
+
+	c.AssertExpectations(t)
+}
+
+func testDryRun(t *testing.T) {
+	const key = "testDryRun"
+
+	var (
+		assert   = assert.New(t)
+		require  = require.New(t)
+		provider = xmetricstest.NewProvider(nil, Metrics)
+
+		c = new(device.MockConnector)
+		a = new(service.MockAccessor)
+
+		keepID       = device.ID("keep")
+		disconnectID = device.ID("disconnect")
+
+		// the connector has no expectations, as a dry run must never disconnect devices
+		registry = newStubManager(keepID, disconnectID)
+	)
+
+	a.On("Get", keepID.Bytes()).Return("keep", error(nil)).Once()
+	a.On("Get", disconnectID.Bytes()).Return("disconnect", error(nil)).Once()
+
+	l := New(
+		c,
+		WithLogger(logging.NewTestLogger(nil, t)),
+		WithAccessorFactory(func([]string) service.Accessor { return a }),
+		WithIsRegistered(func(instance string) bool { return instance == "keep" }),
+		WithMetricsProvider(provider),
+		WithDryRun(registry),
+	)
+
+	require.NotNil(l)
+	l.MonitorEvent(monitor.Event{Key: key, EventCount: 2, Err: errors.New("service discovery error")})
+	l.MonitorEvent(monitor.Event{Key: key, EventCount: 3})
+	l.MonitorEvent(monitor.Event{Key: key, EventCount: 4, Instances: []string{"keep", "disconnect"}})
+	l.MonitorEvent(monitor.Event{Key: key, EventCount: 5, Stopped: true})
+
+	provider.Assert(t, RehashKeepDevice, service.ServiceLabel, key)(xmetricstest.Value(1.0))
+	provider.Assert(t, RehashDisconnectDevice, service.ServiceLabel, key)(xmetricstest.Value(1.0))
+	provider.Assert(t, RehashDisconnectAllCounter, service.ServiceLabel, key, ReasonLabel, DisconnectAllServiceDiscoveryError)(xmetricstest.Value(0.0))
+	provider.Assert(t, RehashDisconnectAllCounter, service.ServiceLabel, key, ReasonLabel, DisconnectAllServiceDiscoveryNoInstances)(xmetricstest.Value(0.0))
+	provider.Assert(t, RehashDisconnectAllCounter, service.ServiceLabel, key, ReasonLabel, DisconnectAllServiceDiscoveryStopped)(xmetricstest.Value(0.0))
+
+	a.AssertExpectations(t)
+	c.AssertExpectations(t)
+	assert.Equal(2, registry.Len())
+}
+
+func TestDryRun(t *testing.T) {
+	t.Run("Rehash", testDryRun)
+}
+
+func TestThrottle(t *testing.T) {
+	t.Run("WithThrottle", testWithThrottle)
+	t.Run("Rehash", testThrottledRehash)
+}
+
+func TestGracePeriod(t *testing.T) {
+	t.Run("WithErrorGracePeriod", testWithErrorGracePeriod)
+	t.Run("Recovered", testGracePeriodRecovered)
+	t.Run("Expired", testGracePeriodExpired)
+}
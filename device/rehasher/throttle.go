@@ -0,0 +1,78 @@
+package rehasher
+
+import (
+	"github.com/go-kit/kit/metrics"
+
+	"github.com/Comcast/webpa-common/device"
+)
+
+// filteredRegistry is a device.Registry that only exposes the devices matching a predicate.  This allows
+// a drain job to disconnect a subset of devices, such as those that no longer hash to this instance.
+//
+// The predicate is evaluated lazily, as devices are visited, so devices which connect or disconnect
+// while a drain job is running are handled correctly.
+type filteredRegistry struct {
+	device.Registry
+	filter func(device.ID) bool
+}
+
+func (fr filteredRegistry) Len() int {
+	count := 0
+	fr.Registry.VisitAll(func(d device.Interface) bool {
+		if fr.filter(d.ID()) {
+			count++
+		}
+
+		return true
+	})
+
+	return count
+}
+
+func (fr filteredRegistry) Get(id device.ID) (device.Interface, bool) {
+	if d, ok := fr.Registry.Get(id); ok && fr.filter(id) {
+		return d, true
+	}
+
+	return nil, false
+}
+
+func (fr filteredRegistry) VisitAll(p func(device.Interface) bool) int {
+	count := 0
+	fr.Registry.VisitAll(func(d device.Interface) bool {
+		if !fr.filter(d.ID()) {
+			return true
+		}
+
+		count++
+		return p(d)
+	})
+
+	return count
+}
+
+// loggingConnector is a device.Connector that reports each device it actually disconnects by id.  This allows
+// a throttled rehash to log its disconnections without logging each time a drain job filters its registry.
+type loggingConnector struct {
+	device.Connector
+	logDisconnect func(device.ID)
+}
+
+func (lc loggingConnector) Disconnect(id device.ID) bool {
+	if lc.Connector.Disconnect(id) {
+		lc.logDisconnect(id)
+		return true
+	}
+
+	return false
+}
+
+// pendingAdder adapts a pending moves gauge for use as a drain counter.  Each device drained
+// is one less device pending a move.
+type pendingAdder struct {
+	metrics.Gauge
+}
+
+func (pa pendingAdder) Add(delta float64) {
+	pa.Gauge.Add(-delta)
+}
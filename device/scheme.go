@@ -0,0 +1,205 @@
+package device
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/Comcast/webpa-common/wrp"
+)
+
+// Normalizer validates and canonicalizes the value portion of a device name, i.e. the text between
+// the scheme prefix and the optional service.  A Normalizer returns ErrorInvalidDeviceName if the value
+// is not valid for its scheme.
+type Normalizer func(string) (string, error)
+
+// PassThrough is a Normalizer that accepts any value unchanged
+func PassThrough(value string) (string, error) {
+	return value, nil
+}
+
+// Lowercase is a Normalizer that lowercases values.  This is useful for schemes, such as UUIDs,
+// whose values are case-insensitive.
+func Lowercase(value string) (string, error) {
+	return strings.ToLower(value), nil
+}
+
+// MAC is the Normalizer for the mac scheme.  It strips delimiters and lowercases hexadecimal digits.
+// The result must be exactly 12 hexadecimal digits.  This is the same canonicalization that wrp.ParseLocator
+// applies to mac locators.
+func MAC(value string) (string, error) {
+	value, ok := wrp.CanonicalMAC(value)
+	if !ok {
+		return "", ErrorInvalidDeviceName
+	}
+
+	return value, nil
+}
+
+// TrimLeft produces a Normalizer that strips leading padding, such as zeroes in front of a serial number.
+// A value that consists entirely of padding is invalid.
+func TrimLeft(cutset string) Normalizer {
+	return func(value string) (string, error) {
+		value = strings.TrimLeft(value, cutset)
+		if len(value) == 0 {
+			return "", ErrorInvalidDeviceName
+		}
+
+		return value, nil
+	}
+}
+
+// Pattern produces a Normalizer that requires values to match the given regular expression.
+// Values are not modified.
+func Pattern(p *regexp.Regexp) Normalizer {
+	return func(value string) (string, error) {
+		if !p.MatchString(value) {
+			return "", ErrorInvalidDeviceName
+		}
+
+		return value, nil
+	}
+}
+
+// Chain produces a Normalizer that applies each of the given Normalizers in order, stopping
+// at the first error.
+func Chain(n ...Normalizer) Normalizer {
+	return func(value string) (string, error) {
+		var err error
+		for _, f := range n {
+			if value, err = f(value); err != nil {
+				return "", err
+			}
+		}
+
+		return value, nil
+	}
+}
+
+// Schemes is a registry of device identifier schemes.  Each scheme is identified by a case-insensitive
+// prefix, e.g. "mac" or "uuid", and has a Normalizer that validates and canonicalizes values.
+//
+// A Schemes instance is safe for concurrent use.
+type Schemes struct {
+	lock        sync.RWMutex
+	normalizers map[string]Normalizer
+}
+
+// NewSchemes produces a Schemes registry containing the built-in schemes:  mac, uuid, dns, and serial.
+// Only the mac scheme canonicalizes its values.  The others accept any value unchanged, but can be
+// replaced via Register.
+func NewSchemes() *Schemes {
+	return &Schemes{
+		normalizers: map[string]Normalizer{
+			macPrefix: MAC,
+			"uuid":    PassThrough,
+			"dns":     PassThrough,
+			"serial":  PassThrough,
+		},
+	}
+}
+
+// Register adds a scheme to this registry, replacing any existing scheme with the same prefix.
+// If n is nil, PassThrough is used.
+func (s *Schemes) Register(prefix string, n Normalizer) {
+	if n == nil {
+		n = PassThrough
+	}
+
+	defer s.lock.Unlock()
+	s.lock.Lock()
+	s.normalizers[strings.ToLower(prefix)] = n
+}
+
+// Prefixes returns the sorted, lowercased prefixes of the schemes in this registry
+func (s *Schemes) Prefixes() []string {
+	defer s.lock.RUnlock()
+	s.lock.RLock()
+
+	prefixes := make([]string, 0, len(s.normalizers))
+	for prefix := range s.normalizers {
+		prefixes = append(prefixes, prefix)
+	}
+
+	sort.Strings(prefixes)
+	return prefixes
+}
+
+func (s *Schemes) normalizer(prefix string) (Normalizer, bool) {
+	defer s.lock.RUnlock()
+	s.lock.RLock()
+	n, ok := s.normalizers[prefix]
+	return n, ok
+}
+
+// ParseID parses a raw device name into a canonicalized identifier using the schemes in this registry.
+// The prefix is always lowercased, and the value is canonicalized by the scheme's Normalizer.
+func (s *Schemes) ParseID(deviceName string) (ID, error) {
+	match := idPattern.FindStringSubmatch(deviceName)
+	if match == nil {
+		return invalidID, ErrorInvalidDeviceName
+	}
+
+	prefix := strings.ToLower(match[1])
+	n, ok := s.normalizer(prefix)
+	if !ok {
+		return invalidID, ErrorInvalidDeviceName
+	}
+
+	value, err := n(match[2])
+	if err != nil {
+		return invalidID, err
+	}
+
+	return ID(fmt.Sprintf("%s:%s", prefix, value)), nil
+}
+
+// ParseLocator parses a WRP source or destination, canonicalizing its authority with the Normalizer of its
+// scheme.  Unlike ParseID, locators whose schemes are not in this registry, e.g. event locators, are accepted
+// and their authorities are left unchanged.  An authority that its scheme's Normalizer rejects results in
+// wrp.ErrInvalidLocator.
+func (s *Schemes) ParseLocator(value string) (wrp.Locator, error) {
+	return wrp.ParseLocatorWith(value, s.normalizeAuthority)
+}
+
+// normalizeAuthority is the wrp.AuthorityNormalizer backed by this registry
+func (s *Schemes) normalizeAuthority(scheme, authority string) (string, bool) {
+	n, ok := s.normalizer(scheme)
+	if !ok {
+		return authority, true
+	}
+
+	value, err := n(authority)
+	return value, err == nil
+}
+
+// defaultSchemes holds the *Schemes used by the package-level ParseID and IDHashParser functions
+var defaultSchemes atomic.Value
+
+func init() {
+	defaultSchemes.Store(NewSchemes())
+}
+
+// DefaultSchemes returns the registry used by ParseID and IDHashParser, and thus by WRP routing.
+// Schemes registered with the returned instance take effect immediately.
+func DefaultSchemes() *Schemes {
+	return defaultSchemes.Load().(*Schemes)
+}
+
+// ParseLocator parses a WRP source or destination using DefaultSchemes()
+func ParseLocator(value string) (wrp.Locator, error) {
+	return DefaultSchemes().ParseLocator(value)
+}
+
+// SetDefaultSchemes replaces the registry used by ParseID and IDHashParser.  If s is nil, the
+// default registry is reset to the built-in schemes.
+func SetDefaultSchemes(s *Schemes) {
+	if s == nil {
+		s = NewSchemes()
+	}
+
+	defaultSchemes.Store(s)
+}
@@ -0,0 +1,174 @@
+package device
+
+import (
+	"net/http/httptest"
+	"regexp"
+	"testing"
+
+	"github.com/Comcast/webpa-common/wrp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalizers(t *testing.T) {
+	testData := []struct {
+		name         string
+		normalizer   Normalizer
+		value        string
+		expected     string
+		expectsError bool
+	}{
+		{"PassThrough", PassThrough, "Anything Goes", "Anything Goes", false},
+		{"Lowercase", Lowercase, "ABCDEF-1234", "abcdef-1234", false},
+		{"MAC", MAC, "11:AA:bb:44:55:66", "11aabb445566", false},
+		{"MACTooShort", MAC, "11:AA:bb", "", true},
+		{"MACInvalidCharacter", MAC, "11:AA:bb:44:55:6x", "", true},
+		{"TrimLeft", TrimLeft("0 "), " 000123400", "123400", false},
+		{"TrimLeftAllPadding", TrimLeft("0"), "0000", "", true},
+		{"Pattern", Pattern(regexp.MustCompile(`^[0-9]{15}$`)), "490154203237518", "490154203237518", false},
+		{"PatternNoMatch", Pattern(regexp.MustCompile(`^[0-9]{15}$`)), "49015420323751", "", true},
+		{"ChainEmpty", Chain(), "Unchanged", "Unchanged", false},
+		{"Chain", Chain(TrimLeft("0"), Lowercase), "00ABC", "abc", false},
+		{"ChainError", Chain(TrimLeft("0"), Lowercase), "000", "", true},
+	}
+
+	for _, record := range testData {
+		t.Run(record.name, func(t *testing.T) {
+			assert := assert.New(t)
+			actual, err := record.normalizer(record.value)
+			assert.Equal(record.expected, actual)
+			assert.Equal(record.expectsError, err != nil)
+		})
+	}
+}
+
+func testSchemesDefaults(t *testing.T) {
+	var (
+		assert = assert.New(t)
+		s      = NewSchemes()
+	)
+
+	assert.Equal([]string{"dns", "mac", "serial", "uuid"}, s.Prefixes())
+}
+
+func testSchemesRegister(t *testing.T) {
+	s := NewSchemes()
+
+	s.Register("UUID", Lowercase)
+	s.Register("serial", TrimLeft("0"))
+	s.Register("IMEI", Pattern(regexp.MustCompile(`^[0-9]{15}$`)))
+	s.Register("event", nil)
+	assert.Equal(t, []string{"dns", "event", "imei", "mac", "serial", "uuid"}, s.Prefixes())
+
+	testData := []struct {
+		deviceName   string
+		expected     ID
+		expectsError bool
+	}{
+		{"uuid:A1B2C3D4-E5F6", "uuid:a1b2c3d4-e5f6", false},
+		{"Serial:0001234", "serial:1234", false},
+		{"serial:0000", "", true},
+		{"imei:490154203237518/service", "imei:490154203237518", false},
+		{"IMEI:4901542032375", "", true},
+		{"event:device-status", "event:device-status", false},
+		{"mac:11-aa-BB-44-55-66", "mac:11aabb445566", false},
+		{"unknown:123", "", true},
+	}
+
+	for _, record := range testData {
+		t.Run(record.deviceName, func(t *testing.T) {
+			assert := assert.New(t)
+			id, err := s.ParseID(record.deviceName)
+			assert.Equal(record.expected, id)
+			assert.Equal(record.expectsError, err != nil)
+		})
+	}
+}
+
+func testSchemesIDHashParser(t *testing.T) {
+	var (
+		assert  = assert.New(t)
+		require = require.New(t)
+		s       = NewSchemes()
+		request = httptest.NewRequest("GET", "/", nil)
+	)
+
+	s.Register("uuid", Lowercase)
+	request.Header.Set(DeviceNameHeader, "UUID:ABCDEF")
+
+	key, err := s.IDHashParser(request)
+	require.NoError(err)
+	assert.Equal([]byte("uuid:abcdef"), key)
+}
+
+func testSchemesParseLocator(t *testing.T) {
+	s := NewSchemes()
+	s.Register("serial", TrimLeft("0"))
+
+	testData := []struct {
+		value        string
+		expected     wrp.Locator
+		expectsError bool
+	}{
+		{"Serial:0001234/config", wrp.Locator{Scheme: "serial", Authority: "1234", Service: "config"}, false},
+		{"serial:0000", wrp.Locator{}, true},
+		{"MAC:11-aa-BB-44-55-66/config/path", wrp.Locator{Scheme: "mac", Authority: "11aabb445566", Service: "config", Path: "/path"}, false},
+		{"mac:invalid", wrp.Locator{}, true},
+		{"event:device-status/mac:112233445566", wrp.Locator{Scheme: "event", Authority: "device-status", Service: "mac:112233445566"}, false},
+	}
+
+	for _, record := range testData {
+		t.Run(record.value, func(t *testing.T) {
+			assert := assert.New(t)
+			locator, err := s.ParseLocator(record.value)
+			assert.Equal(record.expected, locator)
+			if record.expectsError {
+				assert.Equal(wrp.ErrInvalidLocator, err)
+			} else {
+				assert.NoError(err)
+			}
+		})
+	}
+}
+
+func testSchemesDefaultSchemes(t *testing.T) {
+	var (
+		assert = assert.New(t)
+		custom = NewSchemes()
+	)
+
+	defer SetDefaultSchemes(nil)
+	custom.Register("imei", nil)
+
+	_, err := ParseID("imei:490154203237518")
+	assert.Equal(ErrorInvalidDeviceName, err)
+
+	custom.Register("serial", TrimLeft("0"))
+	locator, err := ParseLocator("serial:0001234")
+	assert.Equal("serial:0001234", locator.ID())
+	assert.NoError(err)
+
+	SetDefaultSchemes(custom)
+	assert.True(custom == DefaultSchemes())
+
+	id, err := ParseID("imei:490154203237518")
+	assert.Equal(ID("imei:490154203237518"), id)
+	assert.NoError(err)
+
+	locator, err = ParseLocator("serial:0001234")
+	assert.Equal("serial:1234", locator.ID())
+	assert.NoError(err)
+
+	SetDefaultSchemes(nil)
+	assert.False(custom == DefaultSchemes())
+	_, err = ParseID("imei:490154203237518")
+	assert.Equal(ErrorInvalidDeviceName, err)
+}
+
+func TestSchemes(t *testing.T) {
+	t.Run("Defaults", testSchemesDefaults)
+	t.Run("Register", testSchemesRegister)
+	t.Run("IDHashParser", testSchemesIDHashParser)
+	t.Run("ParseLocator", testSchemesParseLocator)
+	t.Run("DefaultSchemes", testSchemesDefaultSchemes)
+}
@@ -25,6 +25,16 @@ type Request struct {
 	// then Routing will be encoded prior to sending to devices.
 	Contents []byte
 
+	// AcceptParts indicates that the caller can consume a multi-part response incrementally, via Response.Parts.
+	// When unset, only the first part of a multi-part response is returned and the remaining parts are discarded
+	// by Send before it returns.
+	AcceptParts bool
+
+	// PartnerIDs are the authenticated partner ids of the caller, e.g. from the caller's JWT claims.  When a
+	// Manager enforces partner ids, these are compared to the target device's partner ids.  The partner ids
+	// carried in Message are supplied by the caller and are not used for this purpose.
+	PartnerIDs []string
+
 	// ctx is the API context for this request, which can be nil.  Normally, it's best to
 	// set this to context.Background() if no cancellation semantics are desired.
 	ctx context.Context
@@ -78,18 +88,25 @@ func (r *Request) ID() (i ID, err error) {
 // DecodeRequest decodes a WRP source into a device Request.  Typically, this is used
 // to produce a device Request from an http.Request.
 //
-// The returned request will not be associated with any context.
-func DecodeRequest(source io.Reader, format wrp.Format) (*Request, error) {
+// The returned request will not be associated with any context.  If any validators are
+// supplied, the decoded message must pass each of them.
+func DecodeRequest(source io.Reader, format wrp.Format, validators ...wrp.Validator) (*Request, error) {
 	contents, err := ioutil.ReadAll(source)
 	if err != nil {
 		return nil, err
 	}
 
 	message := new(wrp.Message)
-	if err := wrp.NewDecoderBytes(contents, format).Decode(message); err != nil {
+	if err := wrp.DefaultDecoderPool(format).DecodeBytes(message, contents); err != nil {
 		return nil, err
 	}
 
+	for _, v := range validators {
+		if err := v.Validate(message); err != nil {
+			return nil, err
+		}
+	}
+
 	return &Request{
 		Message:  message,
 		Format:   format,
@@ -111,6 +128,16 @@ type Response struct {
 
 	// Contents is the encoded form of Message, formatted in Format
 	Contents []byte
+
+	// Part is set when Message is one part of a multi-part response.  For complete responses, this field is nil.
+	Part *Part
+
+	// Parts is set when this Response is the first part of a multi-part response and the request's AcceptParts
+	// field was set.  The remaining parts are delivered on this channel in sequence.  The channel is closed after
+	// the last part, or earlier if the transaction ends before the last part arrives, e.g. because the request's
+	// context is cancelled or the device disconnects.  Callers must either drain this channel or cancel the
+	// request's context.
+	Parts <-chan *Response
 }
 
 // EncodeResponse writes out a device transaction Response to an http Response.
@@ -143,21 +170,76 @@ func EncodeResponse(output http.ResponseWriter, response *Response, format wrp.F
 	}
 
 	output.Header().Set("Content-Type", format.ContentType())
-	err = wrp.NewEncoder(output, format).Encode(response.Message)
+	err = wrp.DefaultEncoderPool(format).Encode(output, response.Message)
 	return
 }
 
+// EncodeResponseParts writes out a multi-part device transaction Response to an http Response.  The parts
+// are written as a stream of WRP messages in the given format, as produced by wrp.StreamWriter, and each part
+// is flushed to the client as soon as it is received.  The Content-Type is format.StreamContentType().
+//
+// Writing stops after the last part.  If the transaction ends before the last part is received, the parts
+// already written are left as they are and ErrorTransactionCancelled is returned.  A Response that is
+// not part of a multi-part response is written as a stream containing just that message.
+func EncodeResponseParts(output http.ResponseWriter, response *Response, format wrp.Format) error {
+	var (
+		writer     = wrp.NewStreamWriter(output, format)
+		flusher, _ = output.(http.Flusher)
+		part       = response
+	)
+
+	output.Header().Set("Content-Type", format.StreamContentType())
+	for {
+		if err := writer.Write(part.Message); err != nil {
+			return err
+		}
+
+		if flusher != nil {
+			flusher.Flush()
+		}
+
+		if part.Part == nil || part.Part.Last {
+			return nil
+		}
+
+		var ok bool
+		if part, ok = <-response.Parts; !ok {
+			return ErrorTransactionCancelled
+		}
+	}
+}
+
+// pendingTransaction is a transaction awaiting either a single response or the parts of a multi-part response.
+// The responses channel starts out as the channel returned by Register, and is replaced by the channel of
+// remaining parts once the first part of a multi-part response is seen.
+type pendingTransaction struct {
+	responses chan *Response
+	next      int
+}
+
 // Transactions represents a set of pending transactions.  Instances are safe for
 // concurrent access.
 type Transactions struct {
-	lock    sync.RWMutex
-	closed  bool
-	pending map[string]chan *Response
+	lock            sync.RWMutex
+	closed          bool
+	pending         map[string]*pendingTransaction
+	maxPendingParts int
 }
 
+// NewTransactions creates an empty set of transactions that permits DefaultMaxPendingParts unconsumed
+// parts for each multi-part response.
 func NewTransactions() *Transactions {
+	return newTransactions(DefaultMaxPendingParts)
+}
+
+func newTransactions(maxPendingParts int) *Transactions {
+	if maxPendingParts < 1 {
+		maxPendingParts = DefaultMaxPendingParts
+	}
+
 	return &Transactions{
-		pending: make(map[string]chan *Response),
+		pending:         make(map[string]*pendingTransaction),
+		maxPendingParts: maxPendingParts,
 	}
 }
 
@@ -191,6 +273,12 @@ func (t *Transactions) Keys() []string {
 // goroutines that are servicing queues of messages, e.g. the read pump of a Manager.  Such goroutines
 // use this method to indicate that a transaction is complete.
 //
+// If the response's Part is set, the response is one part of a multi-part response.  Parts must be completed
+// in sequence, starting with zero, and the transaction remains pending until its last part is completed.  The
+// first part is delivered on the channel returned by Register with its Parts field set to a channel that receives
+// the remaining parts.  A part that is out of sequence, or that would exceed the maximum number of unconsumed
+// parts, cancels the transaction and results in an error.
+//
 // If this method is passed a nil response, it panics.
 func (t *Transactions) Complete(transactionKey string, response *Response) error {
 	if len(transactionKey) == 0 {
@@ -201,18 +289,52 @@ func (t *Transactions) Complete(transactionKey string, response *Response) error
 
 	defer t.lock.Unlock()
 	t.lock.Lock()
-	result, ok := t.pending[transactionKey]
-	delete(t.pending, transactionKey)
-
+	pt, ok := t.pending[transactionKey]
 	if !ok {
 		return ErrorNoSuchTransactionKey
 	}
 
-	result <- response
-	close(result)
+	last := true
+	if response.Part != nil {
+		if response.Part.Sequence != pt.next {
+			t.cancel(transactionKey, pt)
+			return ErrorPartOutOfSequence
+		}
+
+		last = response.Part.Last
+		if pt.next == 0 && !last {
+			// only now is this known to be a multi-part response, so switch to a channel that can buffer the remaining parts
+			parts := make(chan *Response, t.maxPendingParts)
+			response.Parts = parts
+			pt.responses <- response
+			close(pt.responses)
+			pt.responses = parts
+			pt.next++
+			return nil
+		}
+	}
+
+	select {
+	case pt.responses <- response:
+		pt.next++
+	default:
+		t.cancel(transactionKey, pt)
+		return ErrorTooManyPendingParts
+	}
+
+	if last {
+		t.cancel(transactionKey, pt)
+	}
+
 	return nil
 }
 
+// cancel removes a pending transaction and closes its channel.  This method must be invoked under the write lock.
+func (t *Transactions) cancel(transactionKey string, pt *pendingTransaction) {
+	delete(t.pending, transactionKey)
+	close(pt.responses)
+}
+
 // Cancel simply cancels a transaction.  The transaction key is removed from the pending set.  If that
 // transaction key is not registered, this method does nothing.  The channel returned from Register
 // is closed, which will cause any code waiting for a response to get a nil Response.
@@ -226,11 +348,8 @@ func (t *Transactions) Cancel(transactionKey string) {
 		return
 	}
 
-	result, ok := t.pending[transactionKey]
-	delete(t.pending, transactionKey)
-
-	if ok {
-		close(result)
+	if pt, ok := t.pending[transactionKey]; ok {
+		t.cancel(transactionKey, pt)
 	}
 }
 
@@ -244,9 +363,8 @@ func (t *Transactions) Close() error {
 	}
 
 	t.closed = true
-	for key, responses := range t.pending {
-		delete(t.pending, key)
-		close(responses)
+	for key, pt := range t.pending {
+		t.cancel(key, pt)
 	}
 
 	return nil
@@ -262,7 +380,8 @@ func (t *Transactions) Close() error {
 // instance expressly does not allow that case.
 //
 // The returned channel will either receive a non-nil response from some code calling Complete, or will
-// see a channel closure (nil Response) from some code calling Cancel.
+// see a channel closure (nil Response) from some code calling Cancel.  For multi-part responses, the channel
+// receives the first part, whose Parts field delivers the remaining parts in sequence.
 func (t *Transactions) Register(transactionKey string) (<-chan *Response, error) {
 	if len(transactionKey) == 0 {
 		return nil, ErrorInvalidTransactionKey
@@ -278,7 +397,10 @@ func (t *Transactions) Register(transactionKey string) (<-chan *Response, error)
 		return nil, ErrorTransactionAlreadyRegistered
 	}
 
-	result := make(chan *Response, 1)
-	t.pending[transactionKey] = result
-	return result, nil
+	pt := &pendingTransaction{
+		responses: make(chan *Response, 1),
+	}
+
+	t.pending[transactionKey] = pt
+	return pt.responses, nil
 }
@@ -4,6 +4,9 @@ import (
 	"bytes"
 	"context"
 	"errors"
+	"fmt"
+	"io"
+	"net/http/httptest"
 	"testing"
 
 	"github.com/Comcast/webpa-common/wrp"
@@ -115,6 +118,37 @@ func testDecodeRequestDecodeError(t *testing.T, format wrp.Format) {
 	assert.Error(err)
 }
 
+func testDecodeRequestValidation(t *testing.T, format wrp.Format) {
+	var (
+		assert   = assert.New(t)
+		require  = require.New(t)
+		contents []byte
+
+		validator = wrp.Validators{wrp.Required("transaction_uuid")}
+	)
+
+	require.NoError(wrp.NewEncoderBytes(&contents, format).Encode(&wrp.SimpleRequestResponse{
+		Source:          "dns:app.comcast.com",
+		Destination:     "uuid:1234/service",
+		TransactionUUID: "this-is-a-transaction-id",
+	}))
+
+	request, err := DecodeRequest(bytes.NewReader(contents), format, validator)
+	assert.NotNil(request)
+	assert.NoError(err)
+
+	contents = nil
+	require.NoError(wrp.NewEncoderBytes(&contents, format).Encode(&wrp.SimpleRequestResponse{
+		Source:      "dns:app.comcast.com",
+		Destination: "uuid:1234/service",
+	}))
+
+	request, err = DecodeRequest(bytes.NewReader(contents), format, validator)
+	assert.Nil(request)
+	require.Error(err)
+	assert.IsType(&wrp.ValidationError{}, err)
+}
+
 func TestDecodeRequest(t *testing.T) {
 	for _, format := range []wrp.Format{wrp.Msgpack, wrp.JSON} {
 		t.Run(format.String(), func(t *testing.T) {
@@ -155,6 +189,12 @@ func TestDecodeRequest(t *testing.T) {
 			testDecodeRequestDecodeError(t, format)
 		}
 	})
+
+	t.Run("Validation", func(t *testing.T) {
+		for _, format := range []wrp.Format{wrp.Msgpack, wrp.JSON} {
+			testDecodeRequestValidation(t, format)
+		}
+	})
 }
 
 func testTransactionsInitialState(t *testing.T) {
@@ -287,6 +327,117 @@ func testTransactionsCancellation(t *testing.T) {
 	<-finished
 }
 
+func testTransactionsMultiPart(t *testing.T) {
+	const transactionKey = "transaction-id"
+
+	var (
+		assert       = assert.New(t)
+		require      = require.New(t)
+		transactions = NewTransactions()
+		output, err  = transactions.Register(transactionKey)
+
+		parts = []*Response{
+			{Part: &Part{Sequence: 0}},
+			{Part: &Part{Sequence: 1}},
+			{Part: &Part{Sequence: 2, Last: true}},
+		}
+	)
+
+	require.NoError(err)
+	require.NotNil(output)
+
+	for _, part := range parts {
+		assert.NoError(transactions.Complete(transactionKey, part))
+	}
+
+	assert.Zero(transactions.Len())
+	first := <-output
+	require.True(parts[0] == first)
+	require.NotNil(first.Parts)
+
+	_, ok := <-output
+	assert.False(ok)
+
+	for _, expected := range parts[1:] {
+		assert.True(expected == <-first.Parts)
+	}
+
+	_, ok = <-first.Parts
+	assert.False(ok)
+}
+
+func testTransactionsSinglePart(t *testing.T) {
+	const transactionKey = "transaction-id"
+
+	var (
+		assert       = assert.New(t)
+		require      = require.New(t)
+		transactions = NewTransactions()
+		output, err  = transactions.Register(transactionKey)
+		response     = &Response{Part: &Part{Sequence: 0, Last: true}}
+	)
+
+	require.NoError(err)
+	assert.Equal(1, cap(output))
+	assert.NoError(transactions.Complete(transactionKey, response))
+	assert.Zero(transactions.Len())
+
+	assert.True(response == <-output)
+	assert.Nil(response.Parts)
+
+	_, ok := <-output
+	assert.False(ok)
+}
+
+func testTransactionsPartOutOfSequence(t *testing.T) {
+	const transactionKey = "transaction-id"
+
+	var (
+		assert       = assert.New(t)
+		require      = require.New(t)
+		transactions = NewTransactions()
+		output, err  = transactions.Register(transactionKey)
+	)
+
+	require.NoError(err)
+	assert.NoError(transactions.Complete(transactionKey, &Response{Part: &Part{Sequence: 0}}))
+	assert.Equal(ErrorPartOutOfSequence, transactions.Complete(transactionKey, &Response{Part: &Part{Sequence: 2}}))
+	assert.Zero(transactions.Len())
+
+	first := <-output
+	require.NotNil(first)
+	_, ok := <-output
+	assert.False(ok)
+	_, ok = <-first.Parts
+	assert.False(ok)
+
+	assert.Equal(ErrorNoSuchTransactionKey, transactions.Complete(transactionKey, &Response{Part: &Part{Sequence: 1}}))
+}
+
+func testTransactionsTooManyPendingParts(t *testing.T) {
+	const (
+		transactionKey  = "transaction-id"
+		maxPendingParts = 3
+	)
+
+	var (
+		assert       = assert.New(t)
+		require      = require.New(t)
+		transactions = newTransactions(maxPendingParts)
+		_, err       = transactions.Register(transactionKey)
+	)
+
+	require.NoError(err)
+
+	// the first part is delivered through the registered channel and does not count against the limit
+	for sequence := 0; sequence <= maxPendingParts; sequence++ {
+		require.NoError(transactions.Complete(transactionKey, &Response{Part: &Part{Sequence: sequence}}))
+	}
+
+	assert.Equal(ErrorTooManyPendingParts, transactions.Complete(transactionKey, &Response{Part: &Part{Sequence: maxPendingParts + 1}}))
+	assert.Zero(transactions.Len())
+}
+
 func TestTransactions(t *testing.T) {
 	t.Run("InitialState", testTransactionsInitialState)
 
@@ -303,4 +454,90 @@ func TestTransactions(t *testing.T) {
 
 	t.Run("Lifecycle", testTransactionsLifecycle)
 	t.Run("Cancellation", testTransactionsCancellation)
+	t.Run("MultiPart", testTransactionsMultiPart)
+	t.Run("SinglePart", testTransactionsSinglePart)
+	t.Run("PartOutOfSequence", testTransactionsPartOutOfSequence)
+	t.Run("TooManyPendingParts", testTransactionsTooManyPendingParts)
+}
+
+func testEncodeResponsePartsComplete(t *testing.T, format wrp.Format) {
+	var (
+		assert  = assert.New(t)
+		require = require.New(t)
+
+		output = httptest.NewRecorder()
+		parts  = make(chan *Response, 2)
+		first  = &Response{
+			Message: &wrp.Message{Type: wrp.SimpleRequestResponseMessageType, Payload: []byte("part 0")},
+			Part:    &Part{Sequence: 0},
+			Parts:   parts,
+		}
+	)
+
+	parts <- &Response{
+		Message: &wrp.Message{Type: wrp.SimpleRequestResponseMessageType, Payload: []byte("part 1")},
+		Part:    &Part{Sequence: 1},
+	}
+
+	parts <- &Response{
+		Message: &wrp.Message{Type: wrp.SimpleRequestResponseMessageType, Payload: []byte("part 2")},
+		Part:    &Part{Sequence: 2, Last: true},
+	}
+
+	require.NoError(EncodeResponseParts(output, first, format))
+	assert.Equal(format.StreamContentType(), output.HeaderMap.Get("Content-Type"))
+	assert.True(output.Flushed)
+
+	reader := wrp.NewStreamReader(output.Body, format)
+	for sequence := 0; sequence < 3; sequence++ {
+		var message wrp.Message
+		require.NoError(reader.Read(&message))
+		assert.Equal([]byte(fmt.Sprintf("part %d", sequence)), message.Payload)
+	}
+
+	assert.Equal(io.EOF, reader.Read(new(wrp.Message)))
+}
+
+func testEncodeResponsePartsCancelled(t *testing.T) {
+	var (
+		assert = assert.New(t)
+
+		output = httptest.NewRecorder()
+		parts  = make(chan *Response)
+		first  = &Response{
+			Message: &wrp.Message{Type: wrp.SimpleRequestResponseMessageType},
+			Part:    &Part{Sequence: 0},
+			Parts:   parts,
+		}
+	)
+
+	close(parts)
+	assert.Equal(ErrorTransactionCancelled, EncodeResponseParts(output, first, wrp.Msgpack))
+	assert.NotZero(output.Body.Len())
+}
+
+func testEncodeResponsePartsSingle(t *testing.T) {
+	var (
+		assert  = assert.New(t)
+		require = require.New(t)
+
+		output   = httptest.NewRecorder()
+		response = &Response{Message: &wrp.Message{Type: wrp.SimpleRequestResponseMessageType}}
+	)
+
+	require.NoError(EncodeResponseParts(output, response, wrp.JSON))
+	assert.Equal(wrp.JSON.StreamContentType(), output.HeaderMap.Get("Content-Type"))
+
+	reader := wrp.NewStreamReader(output.Body, wrp.JSON)
+	assert.NoError(reader.Read(new(wrp.Message)))
+	assert.Equal(io.EOF, reader.Read(new(wrp.Message)))
+}
+
+func TestEncodeResponseParts(t *testing.T) {
+	for _, format := range wrp.AllFormats() {
+		t.Run(format.String(), func(t *testing.T) { testEncodeResponsePartsComplete(t, format) })
+	}
+
+	t.Run("Cancelled", testEncodeResponsePartsCancelled)
+	t.Run("Single", testEncodeResponsePartsSingle)
 }
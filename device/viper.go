@@ -1,6 +1,8 @@
 package device
 
 import (
+	"regexp"
+
 	"github.com/go-kit/kit/log"
 	"github.com/spf13/viper"
 )
@@ -18,8 +20,60 @@ const (
 	//     }
 	//   }
 	DeviceManagerKey = "device.manager"
+
+	// DeviceSchemesKey is the Viper subkey under which device identifier schemes are typically stored.
+	// Each key is a scheme prefix, and each value is a SchemeConfig.  Viper ignores empty objects, so a scheme
+	// that accepts any value must still set at least one field:
+	//
+	//   {
+	//     "device": {
+	//       "schemes": {
+	//         "uuid": {"lowercase": true},
+	//         "serial": {"trimLeft": "0"},
+	//         "imei": {"pattern": "^[0-9]{15}$"},
+	//         "event": {"lowercase": false}
+	//       }
+	//     }
+	//   }
+	DeviceSchemesKey = "device.schemes"
 )
 
+// SchemeConfig is the configurable description of a device identifier scheme.  Normalization is applied
+// in field order:  padding is trimmed, the value is lowercased, and finally the value is matched against the pattern.
+type SchemeConfig struct {
+	// TrimLeft is the set of padding characters stripped from the start of values
+	TrimLeft string
+
+	// Lowercase indicates whether values are lowercased
+	Lowercase bool
+
+	// Pattern is an optional regular expression that values must match
+	Pattern string
+}
+
+// NewNormalizer produces the Normalizer described by this configuration
+func (sc SchemeConfig) NewNormalizer() (Normalizer, error) {
+	var chain []Normalizer
+	if len(sc.TrimLeft) > 0 {
+		chain = append(chain, TrimLeft(sc.TrimLeft))
+	}
+
+	if sc.Lowercase {
+		chain = append(chain, Lowercase)
+	}
+
+	if len(sc.Pattern) > 0 {
+		p, err := regexp.Compile(sc.Pattern)
+		if err != nil {
+			return nil, err
+		}
+
+		chain = append(chain, Pattern(p))
+	}
+
+	return Chain(chain...), nil
+}
+
 // NewOptions unmarshals a device.Options from a Viper environment.  Listeners
 // must be configured separately.
 func NewOptions(logger log.Logger, v *viper.Viper) (o *Options, err error) {
@@ -31,3 +85,29 @@ func NewOptions(logger log.Logger, v *viper.Viper) (o *Options, err error) {
 	o.Logger = logger
 	return
 }
+
+// NewSchemesFromViper produces a Schemes registry with the built-in schemes plus any schemes configured
+// in the given Viper environment, typically the DeviceSchemesKey subkey.  A configured scheme replaces a
+// built-in scheme with the same prefix.  If v is nil, only the built-in schemes are registered.
+func NewSchemesFromViper(v *viper.Viper) (*Schemes, error) {
+	s := NewSchemes()
+	if v == nil {
+		return s, nil
+	}
+
+	configs := make(map[string]SchemeConfig)
+	if err := v.Unmarshal(&configs); err != nil {
+		return nil, err
+	}
+
+	for prefix, sc := range configs {
+		n, err := sc.NewNormalizer()
+		if err != nil {
+			return nil, err
+		}
+
+		s.Register(prefix, n)
+	}
+
+	return s, nil
+}
@@ -80,3 +80,85 @@ func TestNewOptionsNilViper(t *testing.T) {
 
 	assert.Equal(Options{Logger: logger}, *o)
 }
+
+func TestNewSchemesFromViper(t *testing.T) {
+	var (
+		assert        = assert.New(t)
+		require       = require.New(t)
+		configuration = `{
+			"device": {
+				"schemes": {
+					"uuid": {"lowercase": true},
+					"serial": {"trimLeft": "0"},
+					"imei": {"pattern": "^[0-9]{15}$"},
+					"event": {"lowercase": false}
+				}
+			}
+		}`
+
+		v = viper.New()
+	)
+
+	v.SetConfigType("json")
+	require.Nil(v.ReadConfig(bytes.NewBufferString(configuration)))
+
+	s, err := NewSchemesFromViper(v.Sub(DeviceSchemesKey))
+	require.NotNil(s)
+	require.NoError(err)
+
+	assert.Equal([]string{"dns", "event", "imei", "mac", "serial", "uuid"}, s.Prefixes())
+
+	id, err := s.ParseID("uuid:ABC-DEF")
+	assert.Equal(ID("uuid:abc-def"), id)
+	assert.NoError(err)
+
+	id, err = s.ParseID("serial:00123")
+	assert.Equal(ID("serial:123"), id)
+	assert.NoError(err)
+
+	id, err = s.ParseID("imei:490154203237518")
+	assert.Equal(ID("imei:490154203237518"), id)
+	assert.NoError(err)
+
+	_, err = s.ParseID("imei:123")
+	assert.Equal(ErrorInvalidDeviceName, err)
+
+	id, err = s.ParseID("event:anything")
+	assert.Equal(ID("event:anything"), id)
+	assert.NoError(err)
+}
+
+func TestNewSchemesFromViperNil(t *testing.T) {
+	var (
+		assert  = assert.New(t)
+		require = require.New(t)
+	)
+
+	s, err := NewSchemesFromViper(nil)
+	require.NotNil(s)
+	assert.NoError(err)
+	assert.Equal([]string{"dns", "mac", "serial", "uuid"}, s.Prefixes())
+}
+
+func TestNewSchemesFromViperBadPattern(t *testing.T) {
+	var (
+		assert        = assert.New(t)
+		require       = require.New(t)
+		configuration = `{
+			"device": {
+				"schemes": {
+					"imei": {"pattern": "[0-9"}
+				}
+			}
+		}`
+
+		v = viper.New()
+	)
+
+	v.SetConfigType("json")
+	require.Nil(v.ReadConfig(bytes.NewBufferString(configuration)))
+
+	s, err := NewSchemesFromViper(v.Sub(DeviceSchemesKey))
+	assert.Nil(s)
+	assert.Error(err)
+}
@@ -0,0 +1,223 @@
+package wrp
+
+import (
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"strings"
+	"sync"
+)
+
+const (
+	// IdentityEncoding is the content encoding of an uncompressed payload.  It is equivalent to
+	// an empty content_encoding field.
+	IdentityEncoding = "identity"
+
+	// GzipEncoding is the content encoding for payloads compressed with gzip
+	GzipEncoding = "gzip"
+
+	// DeflateEncoding is the content encoding for payloads compressed with raw DEFLATE
+	DeflateEncoding = "deflate"
+
+	// DefaultMaxDecompressedSize is the largest decompressed payload, in bytes, permitted by DecompressPayload
+	DefaultMaxDecompressedSize = 16 * 1024 * 1024
+)
+
+var (
+	// ErrUnsupportedEncoding indicates that a payload's content encoding has no registered Compressor
+	ErrUnsupportedEncoding = errors.New("unsupported content encoding")
+
+	// ErrAlreadyEncoded indicates an attempt to compress a payload that is already compressed with a different encoding
+	ErrAlreadyEncoded = errors.New("payload already has a content encoding")
+)
+
+// PayloadTooLargeError is returned when a payload would decompress to more than the permitted number of bytes
+type PayloadTooLargeError struct {
+	// MaxSize is the maximum decompressed size that was exceeded
+	MaxSize int
+}
+
+func (ptle *PayloadTooLargeError) Error() string {
+	return fmt.Sprintf("decompressed payload exceeds %d bytes", ptle.MaxSize)
+}
+
+// Compressor compresses and decompresses payloads for a single content encoding.
+// Implementations must be safe for concurrent use.
+type Compressor interface {
+	Compress([]byte) ([]byte, error)
+
+	// Decompress returns a reader that produces the uncompressed form of the given compressed input.
+	// Implementations need not limit the amount of output, as DecompressPayloadLimit does that.
+	Decompress(io.Reader) (io.ReadCloser, error)
+}
+
+type gzipCompressor struct{}
+
+func (gzipCompressor) Compress(p []byte) ([]byte, error) {
+	var (
+		output bytes.Buffer
+		writer = gzip.NewWriter(&output)
+	)
+
+	if _, err := writer.Write(p); err != nil {
+		return nil, err
+	}
+
+	if err := writer.Close(); err != nil {
+		return nil, err
+	}
+
+	return output.Bytes(), nil
+}
+
+func (gzipCompressor) Decompress(input io.Reader) (io.ReadCloser, error) {
+	return gzip.NewReader(input)
+}
+
+type deflateCompressor struct{}
+
+func (deflateCompressor) Compress(p []byte) ([]byte, error) {
+	var output bytes.Buffer
+	writer, err := flate.NewWriter(&output, flate.DefaultCompression)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := writer.Write(p); err != nil {
+		return nil, err
+	}
+
+	if err := writer.Close(); err != nil {
+		return nil, err
+	}
+
+	return output.Bytes(), nil
+}
+
+func (deflateCompressor) Decompress(input io.Reader) (io.ReadCloser, error) {
+	return flate.NewReader(input), nil
+}
+
+var (
+	compressorLock sync.RWMutex
+	compressors    = map[string]Compressor{
+		GzipEncoding:    gzipCompressor{},
+		DeflateEncoding: deflateCompressor{},
+	}
+)
+
+// RegisterCompressor makes a Compressor available for the given content encoding, replacing any existing
+// Compressor.  Encodings are case-insensitive.  This is how additional encodings, such as zstd, are supported
+// without this package depending on their implementations.  The gzip and deflate encodings are built in.
+func RegisterCompressor(encoding string, c Compressor) {
+	defer compressorLock.Unlock()
+	compressorLock.Lock()
+	compressors[strings.ToLower(encoding)] = c
+}
+
+// isIdentity tests if a content encoding denotes an uncompressed payload
+func isIdentity(encoding string) bool {
+	return len(encoding) == 0 || strings.EqualFold(encoding, IdentityEncoding)
+}
+
+func compressor(encoding string) (Compressor, error) {
+	defer compressorLock.RUnlock()
+	compressorLock.RLock()
+	if c, ok := compressors[strings.ToLower(encoding)]; ok {
+		return c, nil
+	}
+
+	return nil, ErrUnsupportedEncoding
+}
+
+// CompressPayload compresses a message's payload with the given encoding and records that encoding in the
+// message's ContentEncoding.  A message with no payload is not modified.  Compressing a payload that already
+// has the requested encoding does nothing, while a payload compressed with a different encoding results in
+// ErrAlreadyEncoded.
+func CompressPayload(m *Message, encoding string) error {
+	if len(m.Payload) == 0 || isIdentity(encoding) {
+		return nil
+	}
+
+	if !isIdentity(m.ContentEncoding) {
+		if strings.EqualFold(m.ContentEncoding, encoding) {
+			return nil
+		}
+
+		return ErrAlreadyEncoded
+	}
+
+	c, err := compressor(encoding)
+	if err != nil {
+		return err
+	}
+
+	compressed, err := c.Compress(m.Payload)
+	if err != nil {
+		return err
+	}
+
+	m.Payload = compressed
+	m.ContentEncoding = strings.ToLower(encoding)
+	return nil
+}
+
+// CompressPayloadOver is like CompressPayload, but only compresses payloads larger than threshold bytes.
+// Small payloads usually grow when compressed, so this is the function most callers want.
+func CompressPayloadOver(m *Message, encoding string, threshold int) error {
+	if len(m.Payload) <= threshold {
+		return nil
+	}
+
+	return CompressPayload(m, encoding)
+}
+
+// DecompressPayload restores a message's payload to its uncompressed form and clears the message's
+// ContentEncoding.  Messages without a content encoding are not modified.  Payloads are limited to
+// DefaultMaxDecompressedSize bytes once decompressed.
+func DecompressPayload(m *Message) error {
+	return DecompressPayloadLimit(m, DefaultMaxDecompressedSize)
+}
+
+// DecompressPayloadLimit is like DecompressPayload, but permits at most maxSize bytes of decompressed payload.
+// A payload that would decompress to more than maxSize bytes results in a *PayloadTooLargeError, and the
+// message is not modified.  If maxSize is nonpositive, DefaultMaxDecompressedSize is used.
+func DecompressPayloadLimit(m *Message, maxSize int) error {
+	if isIdentity(m.ContentEncoding) {
+		m.ContentEncoding = ""
+		return nil
+	}
+
+	if maxSize < 1 {
+		maxSize = DefaultMaxDecompressedSize
+	}
+
+	c, err := compressor(m.ContentEncoding)
+	if err != nil {
+		return err
+	}
+
+	if len(m.Payload) > 0 {
+		reader, err := c.Decompress(bytes.NewReader(m.Payload))
+		if err != nil {
+			return err
+		}
+
+		defer reader.Close()
+		decompressed, err := ioutil.ReadAll(io.LimitReader(reader, int64(maxSize)+1))
+		if err != nil {
+			return err
+		} else if len(decompressed) > maxSize {
+			return &PayloadTooLargeError{MaxSize: maxSize}
+		}
+
+		m.Payload = decompressed
+	}
+
+	m.ContentEncoding = ""
+	return nil
+}
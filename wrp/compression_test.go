@@ -0,0 +1,258 @@
+package wrp
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"io/ioutil"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testLargePayload = bytes.Repeat([]byte("a large, compressible payload "), 100)
+
+func testCompressPayloadRoundTrip(t *testing.T, encoding string) {
+	var (
+		assert  = assert.New(t)
+		require = require.New(t)
+
+		message = Message{
+			Type:        SimpleRequestResponseMessageType,
+			ContentType: "text/plain",
+			Payload:     append([]byte(nil), testLargePayload...),
+		}
+	)
+
+	require.NoError(CompressPayload(&message, encoding))
+	assert.Equal(encoding, message.ContentEncoding)
+	assert.True(len(message.Payload) < len(testLargePayload))
+	assert.Equal("text/plain", message.ContentType)
+
+	// compressing again with the same encoding does nothing
+	compressed := append([]byte(nil), message.Payload...)
+	require.NoError(CompressPayload(&message, encoding))
+	assert.Equal(compressed, message.Payload)
+
+	for _, format := range allFormats {
+		var (
+			encoded []byte
+			decoded Message
+		)
+
+		require.NoError(NewEncoderBytes(&encoded, format).Encode(&message))
+		require.NoError(NewDecoderBytes(encoded, format).Decode(&decoded))
+		assert.Equal(message, decoded, format.String())
+	}
+
+	require.NoError(DecompressPayload(&message))
+	assert.Empty(message.ContentEncoding)
+	assert.Equal(testLargePayload, message.Payload)
+}
+
+func testCompressPayloadEmpty(t *testing.T) {
+	var (
+		assert  = assert.New(t)
+		message = Message{Type: SimpleEventMessageType}
+	)
+
+	assert.NoError(CompressPayload(&message, GzipEncoding))
+	assert.Empty(message.ContentEncoding)
+	assert.Empty(message.Payload)
+}
+
+func testCompressPayloadIdentity(t *testing.T) {
+	var (
+		assert  = assert.New(t)
+		message = Message{Payload: []byte("payload")}
+	)
+
+	assert.NoError(CompressPayload(&message, IdentityEncoding))
+	assert.NoError(CompressPayload(&message, ""))
+	assert.Empty(message.ContentEncoding)
+	assert.Equal([]byte("payload"), message.Payload)
+}
+
+func testCompressPayloadUnsupported(t *testing.T) {
+	var (
+		assert  = assert.New(t)
+		message = Message{Payload: []byte("payload")}
+	)
+
+	assert.Equal(ErrUnsupportedEncoding, CompressPayload(&message, "nosuch"))
+	assert.Empty(message.ContentEncoding)
+	assert.Equal([]byte("payload"), message.Payload)
+}
+
+func testCompressPayloadAlreadyEncoded(t *testing.T) {
+	var (
+		assert  = assert.New(t)
+		message = Message{Payload: []byte("payload"), ContentEncoding: DeflateEncoding}
+	)
+
+	assert.Equal(ErrAlreadyEncoded, CompressPayload(&message, GzipEncoding))
+	assert.Equal(DeflateEncoding, message.ContentEncoding)
+	assert.Equal([]byte("payload"), message.Payload)
+}
+
+func TestCompressPayload(t *testing.T) {
+	for _, encoding := range []string{GzipEncoding, DeflateEncoding} {
+		t.Run(encoding, func(t *testing.T) {
+			testCompressPayloadRoundTrip(t, encoding)
+		})
+	}
+
+	t.Run("Empty", testCompressPayloadEmpty)
+	t.Run("Identity", testCompressPayloadIdentity)
+	t.Run("Unsupported", testCompressPayloadUnsupported)
+	t.Run("AlreadyEncoded", testCompressPayloadAlreadyEncoded)
+}
+
+func TestCompressPayloadOver(t *testing.T) {
+	var (
+		assert  = assert.New(t)
+		require = require.New(t)
+
+		small = Message{Payload: []byte("small")}
+		large = Message{Payload: append([]byte(nil), testLargePayload...)}
+	)
+
+	require.NoError(CompressPayloadOver(&small, GzipEncoding, 1024))
+	assert.Empty(small.ContentEncoding)
+	assert.Equal([]byte("small"), small.Payload)
+
+	require.NoError(CompressPayloadOver(&large, GzipEncoding, 1024))
+	assert.Equal(GzipEncoding, large.ContentEncoding)
+	assert.NotEqual(testLargePayload, large.Payload)
+}
+
+func TestDecompressPayload(t *testing.T) {
+	t.Run("Identity", func(t *testing.T) {
+		var (
+			assert  = assert.New(t)
+			message = Message{Payload: []byte("payload"), ContentEncoding: "IDENTITY"}
+		)
+
+		assert.NoError(DecompressPayload(&message))
+		assert.Empty(message.ContentEncoding)
+		assert.Equal([]byte("payload"), message.Payload)
+	})
+
+	t.Run("Unsupported", func(t *testing.T) {
+		var (
+			assert  = assert.New(t)
+			message = Message{Payload: []byte("payload"), ContentEncoding: "nosuch"}
+		)
+
+		assert.Equal(ErrUnsupportedEncoding, DecompressPayload(&message))
+		assert.Equal("nosuch", message.ContentEncoding)
+	})
+
+	t.Run("Corrupt", func(t *testing.T) {
+		var (
+			assert  = assert.New(t)
+			message = Message{Payload: []byte("this is not gzip"), ContentEncoding: GzipEncoding}
+		)
+
+		assert.Error(DecompressPayload(&message))
+		assert.Equal(GzipEncoding, message.ContentEncoding)
+		assert.Equal([]byte("this is not gzip"), message.Payload)
+	})
+}
+
+func testDecompressPayloadLimit(t *testing.T, encoding string) {
+	var (
+		assert  = assert.New(t)
+		require = require.New(t)
+
+		maxSize = len(testLargePayload)
+		atLimit = Message{Payload: testLargePayload}
+		bomb    = Message{Payload: bytes.Repeat([]byte{0}, 10*DefaultMaxDecompressedSize)}
+	)
+
+	require.NoError(CompressPayload(&atLimit, encoding))
+	assert.NoError(DecompressPayloadLimit(&atLimit, maxSize))
+	assert.Equal(testLargePayload, atLimit.Payload)
+	assert.Empty(atLimit.ContentEncoding)
+
+	require.NoError(CompressPayload(&bomb, encoding))
+	compressed := bomb.Payload
+	require.True(len(compressed) < DefaultMaxDecompressedSize)
+
+	err := DecompressPayload(&bomb)
+	require.IsType(&PayloadTooLargeError{}, err)
+	assert.Equal(DefaultMaxDecompressedSize, err.(*PayloadTooLargeError).MaxSize)
+	assert.Equal(compressed, bomb.Payload)
+	assert.Equal(encoding, bomb.ContentEncoding)
+
+	err = DecompressPayloadLimit(&bomb, maxSize)
+	require.IsType(&PayloadTooLargeError{}, err)
+	assert.Equal(maxSize, err.(*PayloadTooLargeError).MaxSize)
+	assert.Equal(compressed, bomb.Payload)
+	assert.Equal(encoding, bomb.ContentEncoding)
+}
+
+func TestDecompressPayloadLimit(t *testing.T) {
+	for _, encoding := range []string{GzipEncoding, DeflateEncoding} {
+		t.Run(encoding, func(t *testing.T) {
+			testDecompressPayloadLimit(t, encoding)
+		})
+	}
+}
+
+// reverseCompressor is a trivial, invertible Compressor used to test registration
+type reverseCompressor struct {
+	err error
+}
+
+func (rc reverseCompressor) reverse(p []byte) ([]byte, error) {
+	if rc.err != nil {
+		return nil, rc.err
+	}
+
+	reversed := make([]byte, len(p))
+	for i, b := range p {
+		reversed[len(p)-1-i] = b
+	}
+
+	return reversed, nil
+}
+
+func (rc reverseCompressor) Compress(p []byte) ([]byte, error) { return rc.reverse(p) }
+
+func (rc reverseCompressor) Decompress(input io.Reader) (io.ReadCloser, error) {
+	p, err := ioutil.ReadAll(input)
+	if err != nil {
+		return nil, err
+	}
+
+	reversed, err := rc.reverse(p)
+	if err != nil {
+		return nil, err
+	}
+
+	return ioutil.NopCloser(bytes.NewReader(reversed)), nil
+}
+
+func TestRegisterCompressor(t *testing.T) {
+	var (
+		assert  = assert.New(t)
+		require = require.New(t)
+
+		expectedErr = errors.New("expected")
+		message     = Message{Payload: []byte("abc")}
+	)
+
+	RegisterCompressor("Reverse", reverseCompressor{})
+	require.NoError(CompressPayload(&message, "REVERSE"))
+	assert.Equal("reverse", message.ContentEncoding)
+	assert.Equal([]byte("cba"), message.Payload)
+
+	require.NoError(DecompressPayload(&message))
+	assert.Equal([]byte("abc"), message.Payload)
+
+	RegisterCompressor("reverse", reverseCompressor{err: expectedErr})
+	assert.Equal(expectedErr, CompressPayload(&message, "reverse"))
+	assert.Empty(message.ContentEncoding)
+}
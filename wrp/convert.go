@@ -0,0 +1,234 @@
+package wrp
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// ErrFieldNotSupported indicates that a Message had a field set that the typed struct for its message type
+// does not have, so that the field would be lost in a conversion
+var ErrFieldNotSupported = errors.New("field not supported by message type")
+
+var (
+	messageStructType = reflect.TypeOf(Message{})
+
+	// typedStructs maps each message type onto the struct that represents it
+	typedStructs = map[MessageType]reflect.Type{
+		AuthMessageType:                  reflect.TypeOf(Authorization{}),
+		SimpleRequestResponseMessageType: reflect.TypeOf(SimpleRequestResponse{}),
+		SimpleEventMessageType:           reflect.TypeOf(SimpleEvent{}),
+		CreateMessageType:                reflect.TypeOf(CRUD{}),
+		RetrieveMessageType:              reflect.TypeOf(CRUD{}),
+		UpdateMessageType:                reflect.TypeOf(CRUD{}),
+		DeleteMessageType:                reflect.TypeOf(CRUD{}),
+		ServiceRegistrationMessageType:   reflect.TypeOf(ServiceRegistration{}),
+		ServiceAliveMessageType:          reflect.TypeOf(ServiceAlive{}),
+	}
+
+	// defaultMessageTypes maps the structs that represent exactly one message type onto that type.  A struct
+	// converted with FromTyped that does not have its Type set uses this default, as with BeforeEncode.
+	defaultMessageTypes = map[reflect.Type]MessageType{
+		reflect.TypeOf(Authorization{}):         AuthMessageType,
+		reflect.TypeOf(SimpleRequestResponse{}): SimpleRequestResponseMessageType,
+		reflect.TypeOf(SimpleEvent{}):           SimpleEventMessageType,
+		reflect.TypeOf(ServiceRegistration{}):   ServiceRegistrationMessageType,
+		reflect.TypeOf(ServiceAlive{}):          ServiceAliveMessageType,
+	}
+
+	// fieldMappings holds the field mapping for each typed struct, computed from the wrp struct tags
+	fieldMappings = func() map[reflect.Type][]fieldMapping {
+		mappings := make(map[reflect.Type][]fieldMapping)
+		for _, t := range typedStructs {
+			if _, ok := mappings[t]; !ok {
+				mappings[t] = newFieldMappings(t)
+			}
+		}
+
+		return mappings
+	}()
+)
+
+// fieldMapping relates a field of a typed struct to the field of Message with the same WRP name
+type fieldMapping struct {
+	name         string
+	messageIndex int
+
+	// typedIndex is the index of the field in the typed struct, or -1 if the typed struct does not have the field
+	typedIndex int
+}
+
+// wrpFieldName returns the WRP name of a struct field, e.g. "dest"
+func wrpFieldName(f reflect.StructField) string {
+	return strings.Split(f.Tag.Get("wrp"), ",")[0]
+}
+
+// newFieldMappings relates each field of Message to the field of a typed struct with the same WRP name.  This
+// function panics if the typed struct has a field that Message does not, or if the field types are not compatible,
+// since that means Message is no longer the union of all WRP fields.
+func newFieldMappings(typed reflect.Type) []fieldMapping {
+	typedIndices := make(map[string]int, typed.NumField())
+	for i := 0; i < typed.NumField(); i++ {
+		typedIndices[wrpFieldName(typed.Field(i))] = i
+	}
+
+	var mappings []fieldMapping
+	for i := 0; i < messageStructType.NumField(); i++ {
+		messageField := messageStructType.Field(i)
+		name := wrpFieldName(messageField)
+		if name == "-" {
+			continue
+		}
+
+		typedIndex, ok := typedIndices[name]
+		if !ok {
+			mappings = append(mappings, fieldMapping{name: name, messageIndex: i, typedIndex: -1})
+			continue
+		}
+
+		typedType := typed.Field(typedIndex).Type
+		if typedType != messageField.Type && (messageField.Type.Kind() != reflect.Ptr || messageField.Type.Elem() != typedType) {
+			panic(fmt.Errorf("The %s field of %s has type %s, which is not compatible with Message", name, typed, typedType))
+		}
+
+		delete(typedIndices, name)
+		mappings = append(mappings, fieldMapping{name: name, messageIndex: i, typedIndex: typedIndex})
+	}
+
+	for name := range typedIndices {
+		panic(fmt.Errorf("The %s field of %s is missing from Message", name, typed))
+	}
+
+	return mappings
+}
+
+// hasValue tests if a field has a value that would be encoded
+func hasValue(field reflect.Value) bool {
+	switch field.Kind() {
+	case reflect.Ptr, reflect.Slice, reflect.Map:
+		return !field.IsNil()
+	case reflect.String:
+		return field.Len() > 0
+	case reflect.Int, reflect.Int64:
+		return field.Int() != 0
+	default:
+		return true
+	}
+}
+
+// ToTyped converts a Message into the typed struct for its message type, e.g. a *SimpleEvent for
+// SimpleEventMessageType or a *CRUD for any of the CRUD message types.  The returned value is always a pointer.
+//
+// The conversion is lossless.  If the message has a field set that the typed struct does not support, including
+// any UnknownFields, a *ValidationError is returned that contains a *FieldError with ErrFieldNotSupported for
+// each such field.  A message type with no typed struct results in ErrInvalidMessageType, and a required field
+// of the typed struct, e.g. the status of an Authorization, results in ErrMissingField if not set.
+func ToTyped(m *Message) (interface{}, error) {
+	typed, ok := typedStructs[m.Type]
+	if !ok {
+		return nil, &ValidationError{
+			Type:   m.Type,
+			Errors: []error{&FieldError{Field: "msg_type", Err: ErrInvalidMessageType}},
+		}
+	}
+
+	var (
+		errs    []error
+		source  = reflect.ValueOf(m).Elem()
+		pointer = reflect.New(typed)
+		target  = pointer.Elem()
+	)
+
+	for _, mapping := range fieldMappings[typed] {
+		field := source.Field(mapping.messageIndex)
+		switch {
+		case mapping.typedIndex < 0:
+			if hasValue(field) {
+				errs = append(errs, &FieldError{Field: mapping.name, Err: ErrFieldNotSupported})
+			}
+
+		case field.Type() == target.Field(mapping.typedIndex).Type():
+			target.Field(mapping.typedIndex).Set(field)
+
+		case field.IsNil():
+			errs = append(errs, &FieldError{Field: mapping.name, Err: ErrMissingField})
+
+		default:
+			target.Field(mapping.typedIndex).Set(field.Elem())
+		}
+	}
+
+	for _, key := range m.unknownFieldKeys() {
+		errs = append(errs, &FieldError{Field: key, Err: ErrFieldNotSupported})
+	}
+
+	if len(errs) > 0 {
+		return nil, &ValidationError{Type: m.Type, Errors: errs}
+	}
+
+	return pointer.Interface(), nil
+}
+
+// FromTyped converts a typed struct, such as a *SimpleRequestResponse or a *CRUD, into a Message.  Either a
+// pointer to a typed struct or the struct itself may be passed.  This function is the inverse of ToTyped.
+//
+// For typed structs that represent a single message type, an unset Type defaults to that message type.  An error
+// is returned if the value is not a typed struct, or if its Type is not one that the typed struct represents.
+func FromTyped(v interface{}) (*Message, error) {
+	source := reflect.ValueOf(v)
+	if source.Kind() == reflect.Ptr {
+		if source.IsNil() {
+			return nil, fmt.Errorf("Cannot convert a nil %T into a Message", v)
+		}
+
+		source = source.Elem()
+	}
+
+	if !source.IsValid() {
+		return nil, errors.New("Cannot convert a nil value into a Message")
+	}
+
+	mappings, ok := fieldMappings[source.Type()]
+	if !ok {
+		return nil, fmt.Errorf("%T is not a typed WRP struct", v)
+	}
+
+	var (
+		m      = new(Message)
+		target = reflect.ValueOf(m).Elem()
+	)
+
+	for _, mapping := range mappings {
+		if mapping.typedIndex < 0 {
+			continue
+		}
+
+		var (
+			field       = source.Field(mapping.typedIndex)
+			targetField = target.Field(mapping.messageIndex)
+		)
+
+		if field.Type() == targetField.Type() {
+			targetField.Set(field)
+		} else {
+			// the Message field is a pointer to the typed struct's field, e.g. *int64 versus int64
+			copy := reflect.New(field.Type())
+			copy.Elem().Set(field)
+			targetField.Set(copy)
+		}
+	}
+
+	if m.Type == 0 {
+		m.Type = defaultMessageTypes[source.Type()]
+	}
+
+	if typedStructs[m.Type] != source.Type() {
+		return nil, &ValidationError{
+			Type:   m.Type,
+			Errors: []error{&FieldError{Field: "msg_type", Err: ErrInvalidMessageType}},
+		}
+	}
+
+	return m, nil
+}
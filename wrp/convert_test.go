@@ -0,0 +1,252 @@
+package wrp
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// populate sets every exported field of a struct to a distinct, non-zero value.  Since this is driven by
+// reflection, fields added to any WRP struct are covered by the conversion tests automatically.
+func populate(t *testing.T, v reflect.Value) {
+	for i := 0; i < v.NumField(); i++ {
+		var (
+			field = v.Field(i)
+			name  = wrpFieldName(v.Type().Field(i))
+		)
+
+		if name == "-" || name == "msg_type" {
+			continue
+		}
+
+		switch field.Kind() {
+		case reflect.String:
+			field.SetString(name + "-value")
+		case reflect.Int, reflect.Int64:
+			field.SetInt(int64(10 + i))
+		case reflect.Bool:
+			field.SetBool(true)
+		case reflect.Ptr:
+			elem := reflect.New(field.Type().Elem())
+			switch elem.Elem().Kind() {
+			case reflect.Int64:
+				elem.Elem().SetInt(int64(100 + i))
+			case reflect.Bool:
+				elem.Elem().SetBool(true)
+			default:
+				t.Fatalf("Unsupported pointer field %s", name)
+			}
+
+			field.Set(elem)
+		case reflect.Slice:
+			switch value := field.Addr().Interface().(type) {
+			case *[]byte:
+				*value = []byte(name + "-bytes")
+			case *[]string:
+				*value = []string{name + "-1", name + "-2"}
+			case *[][]string:
+				*value = [][]string{{name, "1", "2"}}
+			default:
+				t.Fatalf("Unsupported slice field %s", name)
+			}
+		case reflect.Map:
+			field.Set(reflect.ValueOf(map[string]string{"/" + name: "value"}))
+		default:
+			t.Fatalf("Unsupported field %s of kind %s", name, field.Kind())
+		}
+	}
+}
+
+// wrpFieldNames returns the sorted WRP field names of a struct type, excluding msg_type
+func wrpFieldNames(typed reflect.Type) []string {
+	var names []string
+	for i := 0; i < typed.NumField(); i++ {
+		if name := wrpFieldName(typed.Field(i)); name != "-" && name != "msg_type" {
+			names = append(names, name)
+		}
+	}
+
+	sort.Strings(names)
+	return names
+}
+
+// fieldErrorNames returns the sorted field names of the *FieldErrors in a *ValidationError
+func fieldErrorNames(t *testing.T, err error, expected error) []string {
+	validationError, ok := err.(*ValidationError)
+	if !ok {
+		t.Fatalf("Expected a *ValidationError, got %v", err)
+	}
+
+	var names []string
+	for _, e := range validationError.Errors {
+		fieldError, ok := e.(*FieldError)
+		if !ok || fieldError.Err != expected {
+			t.Errorf("Unexpected error: %v", e)
+			continue
+		}
+
+		names = append(names, fieldError.Field)
+	}
+
+	sort.Strings(names)
+	return names
+}
+
+func testConvertRoundTrip(t *testing.T, mt MessageType) {
+	var (
+		assert  = assert.New(t)
+		require = require.New(t)
+
+		typed    = typedStructs[mt]
+		original = reflect.New(typed)
+	)
+
+	populate(t, original.Elem())
+	original.Elem().FieldByName("Type").SetInt(int64(mt))
+
+	m, err := FromTyped(original.Interface())
+	require.NoError(err)
+	require.NotNil(m)
+	assert.Equal(mt, m.Type)
+
+	// every field of the typed struct must have been copied into the Message field with the same WRP name
+	var (
+		messageValue  = reflect.ValueOf(m).Elem()
+		expectedNames = wrpFieldNames(typed)
+		actualNames   []string
+	)
+
+	for i := 0; i < messageValue.NumField(); i++ {
+		name := wrpFieldName(messageStructType.Field(i))
+		if name != "-" && name != "msg_type" && hasValue(messageValue.Field(i)) {
+			actualNames = append(actualNames, name)
+		}
+	}
+
+	sort.Strings(actualNames)
+	assert.Equal(expectedNames, actualNames)
+
+	converted, err := ToTyped(m)
+	require.NoError(err)
+	assert.Equal(original.Interface(), converted)
+
+	// the struct itself, rather than a pointer, is also accepted
+	fromValue, err := FromTyped(original.Elem().Interface())
+	require.NoError(err)
+	assert.Equal(m, fromValue)
+
+	// the Message must encode identically to the typed struct
+	for _, f := range AllFormats() {
+		var fromMessage, fromStruct Message
+		require.NoError(NewDecoderBytes(MustEncode(m, f), f).Decode(&fromMessage))
+		require.NoError(NewDecoderBytes(MustEncode(original.Interface(), f), f).Decode(&fromStruct))
+		assert.Equal(fromStruct, fromMessage, f.String())
+	}
+}
+
+func testConvertUnsupportedFields(t *testing.T, mt MessageType) {
+	var (
+		assert = assert.New(t)
+
+		m = Message{
+			Type:          mt,
+			UnknownFields: map[string]interface{}{"future_field": "value"},
+		}
+
+		supported = make(map[string]bool)
+		expected  = []string{"future_field"}
+	)
+
+	populate(t, reflect.ValueOf(&m).Elem())
+	for _, name := range wrpFieldNames(typedStructs[mt]) {
+		supported[name] = true
+	}
+
+	for _, name := range wrpFieldNames(messageStructType) {
+		if !supported[name] {
+			expected = append(expected, name)
+		}
+	}
+
+	sort.Strings(expected)
+	converted, err := ToTyped(&m)
+	assert.Nil(converted)
+	assert.Equal(expected, fieldErrorNames(t, err, ErrFieldNotSupported))
+}
+
+func TestConvert(t *testing.T) {
+	for mt := range typedStructs {
+		t.Run(mt.String(), func(t *testing.T) {
+			t.Run("RoundTrip", func(t *testing.T) { testConvertRoundTrip(t, mt) })
+			t.Run("UnsupportedFields", func(t *testing.T) { testConvertUnsupportedFields(t, mt) })
+		})
+	}
+}
+
+func TestConvertAllMessageTypes(t *testing.T) {
+	assert := assert.New(t)
+	for mt := AuthMessageType; mt < lastMessageType; mt++ {
+		assert.Contains(typedStructs, mt, "No typed struct for %s", mt)
+	}
+}
+
+func TestToTypedInvalidMessageType(t *testing.T) {
+	assert := assert.New(t)
+
+	converted, err := ToTyped(&Message{Type: lastMessageType})
+	assert.Nil(converted)
+	assert.Equal([]string{"msg_type"}, fieldErrorNames(t, err, ErrInvalidMessageType))
+}
+
+func TestToTypedMissingStatus(t *testing.T) {
+	assert := assert.New(t)
+
+	converted, err := ToTyped(&Message{Type: AuthMessageType})
+	assert.Nil(converted)
+	assert.Equal([]string{"status"}, fieldErrorNames(t, err, ErrMissingField))
+}
+
+func TestToTypedCRUD(t *testing.T) {
+	var (
+		assert  = assert.New(t)
+		require = require.New(t)
+	)
+
+	converted, err := ToTyped(&Message{Type: DeleteMessageType, Source: "dns:foo.com", Path: "/test"})
+	require.NoError(err)
+	assert.Equal(&CRUD{Type: DeleteMessageType, Source: "dns:foo.com", Path: "/test"}, converted)
+}
+
+func TestFromTypedDefaultMessageType(t *testing.T) {
+	assert := assert.New(t)
+
+	m, err := FromTyped(&SimpleEvent{Destination: "event:test"})
+	assert.Equal(&Message{Type: SimpleEventMessageType, Destination: "event:test"}, m)
+	assert.NoError(err)
+
+	m, err = FromTyped(&ServiceAlive{})
+	assert.Equal(&Message{Type: ServiceAliveMessageType}, m)
+	assert.NoError(err)
+
+	// CRUD structs have no default message type
+	m, err = FromTyped(&CRUD{Path: "/test"})
+	assert.Nil(m)
+	assert.Equal([]string{"msg_type"}, fieldErrorNames(t, err, ErrInvalidMessageType))
+}
+
+func TestFromTypedInvalid(t *testing.T) {
+	assert := assert.New(t)
+
+	m, err := FromTyped(&SimpleEvent{Type: CreateMessageType})
+	assert.Nil(m)
+	assert.Equal([]string{"msg_type"}, fieldErrorNames(t, err, ErrInvalidMessageType))
+
+	for _, v := range []interface{}{nil, (*CRUD)(nil), new(Message), "not a struct", new(int)} {
+		m, err := FromTyped(v)
+		assert.Nil(m)
+		assert.Error(err)
+	}
+}
@@ -68,6 +68,10 @@ Some common uses of this package include:
 		defer decoderPool.Put(decoder)
 		defer encoderPool.Put(encoder)
 
+		// pooled encoders and decoders must be reset before each use
+		decoder.Reset(source)
+		encoder.Reset(&buffer)
+
 		// TranscodeMessage returns a *Message as its first value, which contains
 		// the generic WRP message data
 		if _, err := TranscodeMessage(encoder, decoder); err != nil {
@@ -77,5 +81,14 @@ Some common uses of this package include:
 		return buffer.Bytes(), nil
 	}
 
+For one-off operations, the pools also have convenience methods.  DefaultEncoderPool and DefaultDecoderPool
+return shared pools for each Format:
+
+	func decodeMsgpack(contents []byte) (*Message, error) {
+		message := new(Message)
+		err := DefaultDecoderPool(Msgpack).DecodeBytes(message, contents)
+		return message, err
+	}
+
 */
 package wrp
@@ -1,7 +1,6 @@
 package wrp
 
 import (
-	"bytes"
 	"errors"
 	"fmt"
 	"io"
@@ -20,18 +19,22 @@ type Format int
 const (
 	Msgpack Format = iota
 	JSON
+	CBOR
 	lastFormat
 )
 
 // AllFormats returns a distinct slice of all supported formats.
 func AllFormats() []Format {
-	return []Format{Msgpack, JSON}
+	return []Format{Msgpack, JSON, CBOR}
 }
 
+// Each handle allows Raw values to be encoded, which is how messages with UnknownFields are written
+// by Encoders created WithUnknownFields.
 var (
 	jsonHandle = codec.JsonHandle{
 		BasicHandle: codec.BasicHandle{
-			TypeInfos: codec.NewTypeInfos([]string{"wrp"}),
+			TypeInfos:     codec.NewTypeInfos([]string{"wrp"}),
+			EncodeOptions: codec.EncodeOptions{Raw: true},
 		},
 		IntegerAsString: 'L',
 	}
@@ -43,7 +46,42 @@ var (
 		WriteExt:    true,
 		RawToString: true,
 		BasicHandle: codec.BasicHandle{
-			TypeInfos: codec.NewTypeInfos([]string{"wrp"}),
+			TypeInfos:     codec.NewTypeInfos([]string{"wrp"}),
+			EncodeOptions: codec.EncodeOptions{Raw: true},
+		},
+	}
+
+	// cborHandle is the RFC 7049 configuration for WRP messages.  Payloads are encoded as CBOR byte strings.
+	cborHandle = codec.CborHandle{
+		BasicHandle: codec.BasicHandle{
+			TypeInfos:     codec.NewTypeInfos([]string{"wrp"}),
+			EncodeOptions: codec.EncodeOptions{Raw: true},
+		},
+	}
+
+	// the canonical handles are the same as the handles above, except that map keys are written in sorted
+	// order.  They are used to write messages with UnknownFields, whose fields are merged into a single map.
+	jsonCanonicalHandle = codec.JsonHandle{
+		BasicHandle: codec.BasicHandle{
+			TypeInfos:     codec.NewTypeInfos([]string{"wrp"}),
+			EncodeOptions: codec.EncodeOptions{Canonical: true, Raw: true},
+		},
+		IntegerAsString: 'L',
+	}
+
+	msgpackCanonicalHandle = codec.MsgpackHandle{
+		WriteExt:    true,
+		RawToString: true,
+		BasicHandle: codec.BasicHandle{
+			TypeInfos:     codec.NewTypeInfos([]string{"wrp"}),
+			EncodeOptions: codec.EncodeOptions{Canonical: true, Raw: true},
+		},
+	}
+
+	cborCanonicalHandle = codec.CborHandle{
+		BasicHandle: codec.BasicHandle{
+			TypeInfos:     codec.NewTypeInfos([]string{"wrp"}),
+			EncodeOptions: codec.EncodeOptions{Canonical: true, Raw: true},
 		},
 	}
 )
@@ -55,6 +93,8 @@ func (f Format) ContentType() string {
 		return "application/msgpack"
 	case JSON:
 		return "application/json"
+	case CBOR:
+		return "application/cbor"
 	default:
 		return "application/octet-stream"
 	}
@@ -82,6 +122,8 @@ func FormatFromContentType(contentType string, fallback ...Format) (Format, erro
 		return JSON, nil
 	} else if strings.Contains(contentType, "msgpack") {
 		return Msgpack, nil
+	} else if strings.Contains(contentType, "cbor") {
+		return CBOR, nil
 	}
 
 	return Format(-1), fmt.Errorf("Invalid WRP content type: %s", contentType)
@@ -95,6 +137,23 @@ func (f Format) handle() codec.Handle {
 		return &msgpackHandle
 	case JSON:
 		return &jsonHandle
+	case CBOR:
+		return &cborHandle
+	}
+
+	panic(fmt.Errorf("Invalid format constant: %d", f))
+}
+
+// canonicalHandle looks up the codec.Handle for this format that writes map keys in sorted order.
+// This method panics if the format is not a valid value.
+func (f Format) canonicalHandle() codec.Handle {
+	switch f {
+	case Msgpack:
+		return &msgpackCanonicalHandle
+	case JSON:
+		return &jsonCanonicalHandle
+	case CBOR:
+		return &cborCanonicalHandle
 	}
 
 	panic(fmt.Errorf("Invalid format constant: %d", f))
@@ -114,14 +173,52 @@ type Encoder interface {
 	ResetBytes(*[]byte)
 }
 
+// CodecOption configures the Encoders and Decoders created by this package
+type CodecOption func(*codecOptions)
+
+type codecOptions struct {
+	unknownFields bool
+}
+
+func newCodecOptions(options []CodecOption) codecOptions {
+	var co codecOptions
+	for _, o := range options {
+		o(&co)
+	}
+
+	return co
+}
+
+// WithUnknownFields configures whether Encoders and Decoders handle the UnknownFields of a *Message.  When
+// set, Decoders retain unrecognized fields in UnknownFields and Encoders write them back out.  This costs
+// additional passes over each message, so it is disabled by default.  When unset, Decoders discard
+// unrecognized fields and Encoders ignore UnknownFields.
+func WithUnknownFields(retain bool) CodecOption {
+	return func(co *codecOptions) {
+		co.unknownFields = retain
+	}
+}
+
 // encoderDecorator wraps a ugorji Encoder and implements the wrp.Encoder interface.
 type encoderDecorator struct {
 	*codec.Encoder
+	format        Format
+	unknownFields bool
+
+	// the following are used to write messages with UnknownFields, and are created on first use
+	known     []byte
+	merged    []byte
+	encoder   *codec.Encoder
+	canonical *codec.Encoder
+	decoder   *codec.Decoder
 }
 
 // Encode checks to see if value implements EncoderTo and if it does, uses the
 // value.EncodeTo() method.  Otherwise, the value is passed as is to the decorated
 // ugorji Encoder.
+//
+// If this Encoder was created WithUnknownFields, a *Message with UnknownFields is written as a single map
+// that merges its recognized fields with its unknown fields.  In that case, the fields are written in sorted order.
 func (ed *encoderDecorator) Encode(value interface{}) error {
 	if listener, ok := value.(EncodeListener); ok {
 		if err := listener.BeforeEncode(); err != nil {
@@ -129,9 +226,56 @@ func (ed *encoderDecorator) Encode(value interface{}) error {
 		}
 	}
 
+	if m, ok := value.(*Message); ok && ed.unknownFields {
+		if keys := m.unknownFieldKeys(); len(keys) > 0 {
+			return ed.encodeUnknownFields(m, keys)
+		}
+	}
+
 	return ed.Encoder.Encode(value)
 }
 
+// encodeUnknownFields writes a message together with the given keys from its UnknownFields
+func (ed *encoderDecorator) encodeUnknownFields(m *Message, keys []string) error {
+	h := ed.format.handle()
+	if ed.encoder == nil {
+		ed.encoder = codec.NewEncoderBytes(&ed.known, h)
+		ed.canonical = codec.NewEncoderBytes(&ed.merged, ed.format.canonicalHandle())
+		ed.decoder = codec.NewDecoderBytes(nil, h)
+	}
+
+	ed.known = ed.known[:0]
+	ed.encoder.ResetBytes(&ed.known)
+	if err := ed.encoder.Encode(m); err != nil {
+		return err
+	}
+
+	var known map[string]codec.Raw
+	ed.decoder.ResetBytes(ed.known)
+	err := ed.decoder.Decode(&known)
+	ed.decoder.ResetBytes(nil)
+	if err != nil {
+		return err
+	}
+
+	merged := make(map[string]interface{}, len(known)+len(keys))
+	for key, value := range known {
+		merged[key] = value
+	}
+
+	for _, key := range keys {
+		merged[key] = m.UnknownFields[key]
+	}
+
+	ed.merged = ed.merged[:0]
+	ed.canonical.ResetBytes(&ed.merged)
+	if err := ed.canonical.Encode(merged); err != nil {
+		return err
+	}
+
+	return ed.Encoder.Encode(codec.Raw(ed.merged))
+}
+
 // Decoder represents the underlying ugorji behavior that WRP supports
 type Decoder interface {
 	Decode(interface{}) error
@@ -139,32 +283,110 @@ type Decoder interface {
 	ResetBytes([]byte)
 }
 
+// decoderDecorator wraps a ugorji Decoder and implements the wrp.Decoder interface.
+type decoderDecorator struct {
+	*codec.Decoder
+	format        Format
+	unknownFields bool
+
+	// fields is used to decode the individual fields of a *Message, and is created on first use
+	fields *codec.Decoder
+}
+
+// decodeField decodes a value from the raw form of a field
+func (dd *decoderDecorator) decodeField(raw []byte, value interface{}) error {
+	if dd.fields == nil {
+		dd.fields = codec.NewDecoderBytes(raw, dd.format.handle())
+	} else {
+		dd.fields.ResetBytes(raw)
+	}
+
+	err := dd.fields.Decode(value)
+	dd.fields.ResetBytes(nil)
+	return err
+}
+
+// Decode passes the value as is to the decorated ugorji Decoder.  If this Decoder was created
+// WithUnknownFields, the fields of a *Message that are not recognized are retained in its UnknownFields.
+func (dd *decoderDecorator) Decode(value interface{}) error {
+	m, ok := value.(*Message)
+	if !ok || !dd.unknownFields {
+		return dd.Decoder.Decode(value)
+	}
+
+	var raw codec.Raw
+	if err := dd.Decoder.Decode(&raw); err != nil {
+		return err
+	}
+
+	if err := dd.decodeField(raw, m); err != nil {
+		return err
+	}
+
+	var fields map[string]codec.Raw
+	if err := dd.decodeField(raw, &fields); err != nil {
+		// a message that is not a map with string keys, e.g. one encoded as an array, has no field names
+		return nil
+	}
+
+	for key, value := range fields {
+		if messageFieldNames[key] {
+			continue
+		}
+
+		var unknown interface{}
+		if err := dd.decodeField(value, &unknown); err != nil {
+			return err
+		}
+
+		if m.UnknownFields == nil {
+			m.UnknownFields = make(map[string]interface{})
+		}
+
+		m.UnknownFields[key] = unknown
+	}
+
+	return nil
+}
+
 // NewEncoder produces a ugorji Encoder using the appropriate WRP configuration
 // for the given format
-func NewEncoder(output io.Writer, f Format) Encoder {
+func NewEncoder(output io.Writer, f Format, options ...CodecOption) Encoder {
 	return &encoderDecorator{
-		codec.NewEncoder(output, f.handle()),
+		Encoder:       codec.NewEncoder(output, f.handle()),
+		format:        f,
+		unknownFields: newCodecOptions(options).unknownFields,
 	}
 }
 
 // NewEncoderBytes produces a ugorji Encoder using the appropriate WRP configuration
 // for the given format
-func NewEncoderBytes(output *[]byte, f Format) Encoder {
+func NewEncoderBytes(output *[]byte, f Format, options ...CodecOption) Encoder {
 	return &encoderDecorator{
-		codec.NewEncoderBytes(output, f.handle()),
+		Encoder:       codec.NewEncoderBytes(output, f.handle()),
+		format:        f,
+		unknownFields: newCodecOptions(options).unknownFields,
 	}
 }
 
 // NewDecoder produces a ugorji Decoder using the appropriate WRP configuration
 // for the given format
-func NewDecoder(input io.Reader, f Format) Decoder {
-	return codec.NewDecoder(input, f.handle())
+func NewDecoder(input io.Reader, f Format, options ...CodecOption) Decoder {
+	return &decoderDecorator{
+		Decoder:       codec.NewDecoder(input, f.handle()),
+		format:        f,
+		unknownFields: newCodecOptions(options).unknownFields,
+	}
 }
 
 // NewDecoderBytes produces a ugorji Decoder using the appropriate WRP configuration
 // for the given format
-func NewDecoderBytes(input []byte, f Format) Decoder {
-	return codec.NewDecoderBytes(input, f.handle())
+func NewDecoderBytes(input []byte, f Format, options ...CodecOption) Decoder {
+	return &decoderDecorator{
+		Decoder:       codec.NewDecoderBytes(input, f.handle()),
+		format:        f,
+		unknownFields: newCodecOptions(options).unknownFields,
+	}
 }
 
 // TranscodeMessage converts a WRP message of any type from one format into another,
@@ -183,14 +405,10 @@ func TranscodeMessage(target Encoder, source Decoder) (msg *Message, err error)
 // MustEncode is a convenience function that attempts to encode a given message.  A panic
 // is raised on any error.  This function is handy for package initialization.
 func MustEncode(message interface{}, f Format) []byte {
-	var (
-		output  bytes.Buffer
-		encoder = NewEncoder(&output, f)
-	)
-
-	if err := encoder.Encode(message); err != nil {
+	output, err := DefaultEncoderPool(f).EncodeBytes(message)
+	if err != nil {
 		panic(err)
 	}
 
-	return output.Bytes()
+	return output
 }
@@ -12,7 +12,7 @@ import (
 	"github.com/stretchr/testify/require"
 )
 
-func testPayload(t *testing.T, payload []byte) {
+func testPayload(t *testing.T, f Format, payload []byte) {
 	var (
 		assert   = assert.New(t)
 		require  = require.New(t)
@@ -23,8 +23,8 @@ func testPayload(t *testing.T, payload []byte) {
 		decoded Message
 
 		output  bytes.Buffer
-		encoder = NewEncoder(nil, Msgpack)
-		decoder = NewDecoder(nil, Msgpack)
+		encoder = NewEncoder(nil, f)
+		decoder = NewDecoder(nil, f)
 	)
 
 	encoder.Reset(&output)
@@ -44,20 +44,24 @@ func testPayload(t *testing.T, payload []byte) {
 }
 
 func TestPayload(t *testing.T) {
-	t.Run("UTF8", func(t *testing.T) {
-		testPayload(t, []byte("this is clearly a UTF8 string"))
-	})
+	for _, f := range allFormats {
+		t.Run(f.String(), func(t *testing.T) {
+			t.Run("UTF8", func(t *testing.T) {
+				testPayload(t, f, []byte("this is clearly a UTF8 string"))
+			})
 
-	t.Run("Binary", func(t *testing.T) {
-		testPayload(t, []byte{0x00, 0x06, 0xFF, 0xF0})
-	})
+			t.Run("Binary", func(t *testing.T) {
+				testPayload(t, f, []byte{0x00, 0x06, 0xFF, 0xF0})
+			})
 
-	t.Run("LargePayload", func(t *testing.T) {
-		// generate a very large random payload
-		payload := make([]byte, 70*1024)
-		rand.Read(payload)
-		testPayload(t, payload)
-	})
+			t.Run("LargePayload", func(t *testing.T) {
+				// generate a very large random payload
+				payload := make([]byte, 70*1024)
+				rand.Read(payload)
+				testPayload(t, f, payload)
+			})
+		})
+	}
 }
 
 func TestSampleMsgpack(t *testing.T) {
@@ -193,6 +197,7 @@ func TestFormatFromContentType(t *testing.T) {
 		testFormatFromContentTypeValid(t, "application/msgpack", Msgpack)
 		testFormatFromContentTypeValid(t, "application/json", JSON)
 		testFormatFromContentTypeValid(t, "text/json", JSON)
+		testFormatFromContentTypeValid(t, "application/cbor", CBOR)
 	})
 
 	t.Run("Fallback", testFormatFromContentTypeFallback)
@@ -204,7 +209,10 @@ func testFormatString(t *testing.T) {
 	assert.NotEmpty(JSON.String())
 	assert.NotEmpty(Msgpack.String())
 	assert.NotEmpty(Format(-1).String())
+	assert.NotEmpty(CBOR.String())
 	assert.NotEqual(JSON.String(), Msgpack.String())
+	assert.NotEqual(CBOR.String(), Msgpack.String())
+	assert.NotEqual(CBOR.String(), JSON.String())
 }
 
 func testFormatHandle(t *testing.T) {
@@ -212,6 +220,7 @@ func testFormatHandle(t *testing.T) {
 
 	assert.NotNil(JSON.handle())
 	assert.NotNil(Msgpack.handle())
+	assert.NotNil(CBOR.handle())
 	assert.Panics(func() { Format(999).handle() })
 }
 
@@ -220,11 +229,20 @@ func testFormatContentType(t *testing.T) {
 
 	assert.NotEmpty(JSON.ContentType())
 	assert.NotEmpty(Msgpack.ContentType())
+	assert.Equal("application/cbor", CBOR.ContentType())
 	assert.NotEqual(JSON.ContentType(), Msgpack.ContentType())
 	assert.Equal("application/octet-stream", Format(999).ContentType())
 }
 
+func testFormatAllFormats(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Equal([]Format{Msgpack, JSON, CBOR}, AllFormats())
+	assert.Len(AllFormats(), int(lastFormat))
+}
+
 func TestFormat(t *testing.T) {
+	t.Run("AllFormats", testFormatAllFormats)
 	t.Run("String", testFormatString)
 	t.Run("Handle", testFormatHandle)
 	t.Run("ContentType", testFormatContentType)
@@ -305,6 +323,10 @@ func TestTranscodeMessage(t *testing.T) {
 				Headers:                 []string{"X-Header-1", "X-Header-2"},
 				Metadata:                map[string]string{"hi": "there"},
 				Payload:                 []byte("hi!"),
+				SessionID:               "session-1",
+				QualityOfService:        QOSHighValue,
+				RequestID:               "request-1",
+				ContentEncoding:         "gzip",
 			},
 		}
 	)
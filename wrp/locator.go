@@ -0,0 +1,156 @@
+package wrp
+
+import (
+	"strings"
+	"unicode"
+)
+
+const (
+	hexDigits     = "0123456789abcdefABCDEF"
+	macDelimiters = ":-.,"
+	macScheme     = "mac"
+	macLength     = 12
+)
+
+// Locator is the parsed form of a WRP source or destination, such as mac:112233445566/config/path.
+// A locator consists of a scheme, an authority, an optional service, and an optional trailing path.
+type Locator struct {
+	// Scheme is the lowercased prefix of the locator, e.g. "mac" or "dns"
+	Scheme string
+
+	// Authority is the text between the scheme and the service, e.g. a device's MAC address.
+	// For the mac scheme, this is canonicalized to 12 lowercase hexadecimal digits.
+	Authority string
+
+	// Service is the optional first path segment following the authority, e.g. "config"
+	Service string
+
+	// Path is the optional remainder of the locator following the service, including its leading slash
+	Path string
+}
+
+// AuthorityNormalizer validates and canonicalizes the authority of a locator, given the locator's lowercased
+// scheme.  It returns false if the authority is not valid for the scheme.
+type AuthorityNormalizer func(scheme, authority string) (string, bool)
+
+// BuiltinAuthorities is the AuthorityNormalizer used by ParseLocator.  It only knows the built-in mac scheme,
+// whose authorities are canonicalized with CanonicalMAC.  Authorities of any other scheme are unchanged.
+// Schemes registered with a device.Schemes are not known to this package; use device.Schemes.ParseLocator
+// to parse locators with them.
+func BuiltinAuthorities(scheme, authority string) (string, bool) {
+	if scheme == macScheme {
+		return CanonicalMAC(authority)
+	}
+
+	return authority, true
+}
+
+// ParseLocator parses a WRP source or destination using BuiltinAuthorities.  The scheme is lowercased, and
+// mac authorities are canonicalized the same way the built-in mac scheme of device.ParseID canonicalizes
+// MAC device names.  ErrInvalidLocator is returned if the value does not have the syntax of a locator.
+func ParseLocator(value string) (Locator, error) {
+	return ParseLocatorWith(value, BuiltinAuthorities)
+}
+
+// ParseLocatorWith is like ParseLocator, but canonicalizes authorities with the given AuthorityNormalizer.
+// If n is nil, BuiltinAuthorities is used.  ErrInvalidLocator is returned if the normalizer rejects the authority.
+func ParseLocatorWith(value string, n AuthorityNormalizer) (Locator, error) {
+	if n == nil {
+		n = BuiltinAuthorities
+	}
+
+	if strings.IndexFunc(value, unicode.IsSpace) >= 0 {
+		return Locator{}, ErrInvalidLocator
+	}
+
+	colon := strings.IndexByte(value, ':')
+	if colon < 1 || !validScheme(value[:colon]) {
+		return Locator{}, ErrInvalidLocator
+	}
+
+	var (
+		l    = Locator{Scheme: strings.ToLower(value[:colon])}
+		rest = value[colon+1:]
+	)
+
+	if slash := strings.IndexByte(rest, '/'); slash >= 0 {
+		l.Authority, rest = rest[:slash], rest[slash+1:]
+		if slash = strings.IndexByte(rest, '/'); slash >= 0 {
+			l.Service, l.Path = rest[:slash], rest[slash:]
+		} else {
+			l.Service = rest
+		}
+	} else {
+		l.Authority = rest
+	}
+
+	if len(l.Authority) == 0 {
+		return Locator{}, ErrInvalidLocator
+	}
+
+	var ok bool
+	if l.Authority, ok = n(l.Scheme, l.Authority); !ok || len(l.Authority) == 0 {
+		return Locator{}, ErrInvalidLocator
+	}
+
+	return l, nil
+}
+
+// validScheme tests if a scheme begins with a letter and contains only letters and digits
+func validScheme(scheme string) bool {
+	for i, r := range scheme {
+		if r > unicode.MaxASCII || !(unicode.IsLetter(r) || (i > 0 && unicode.IsDigit(r))) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// CanonicalMAC strips delimiters from a MAC address and lowercases its hexadecimal digits.  It returns false
+// if the result is not exactly 12 hexadecimal digits.  This is the canonical form of both mac locators and
+// mac device identifiers.
+func CanonicalMAC(value string) (string, bool) {
+	var invalidCharacter rune = -1
+	value = strings.Map(
+		func(r rune) rune {
+			switch {
+			case strings.ContainsRune(hexDigits, r):
+				return unicode.ToLower(r)
+			case strings.ContainsRune(macDelimiters, r):
+				return -1
+			default:
+				invalidCharacter = r
+				return -1
+			}
+		},
+		value,
+	)
+
+	return value, invalidCharacter == -1 && len(value) == macLength
+}
+
+// ID returns the scheme and authority of this locator, e.g. mac:112233445566.  For device locators, this
+// is the same as the device identifier produced by device.ParseID.
+func (l Locator) ID() string {
+	return l.Scheme + ":" + l.Authority
+}
+
+// String returns the canonical text form of this locator.  The result can be parsed by ParseLocator.
+func (l Locator) String() string {
+	if len(l.Service) > 0 || len(l.Path) > 0 {
+		return l.ID() + "/" + l.Service + l.Path
+	}
+
+	return l.ID()
+}
+
+// SourceLocator parses the source of a Routable
+func SourceLocator(r Routable) (Locator, error) {
+	return ParseLocator(r.From())
+}
+
+// DestinationLocator parses the destination of a Routable
+func DestinationLocator(r Routable) (Locator, error) {
+	return ParseLocator(r.To())
+}
@@ -0,0 +1,148 @@
+package wrp
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseLocator(t *testing.T) {
+	testData := []struct {
+		value     string
+		expected  Locator
+		canonical string
+	}{
+		{"mac:112233445566", Locator{Scheme: "mac", Authority: "112233445566"}, "mac:112233445566"},
+		{"MAC:11:22:33:AA:bb:66", Locator{Scheme: "mac", Authority: "112233aabb66"}, "mac:112233aabb66"},
+		{"mac:11-22-33-44-55-66/config", Locator{Scheme: "mac", Authority: "112233445566", Service: "config"}, "mac:112233445566/config"},
+		{
+			"mac:112233445566/config/path/to/thing",
+			Locator{Scheme: "mac", Authority: "112233445566", Service: "config", Path: "/path/to/thing"},
+			"mac:112233445566/config/path/to/thing",
+		},
+		{"mac:112233445566/config/", Locator{Scheme: "mac", Authority: "112233445566", Service: "config", Path: "/"}, "mac:112233445566/config/"},
+		{"mac:112233445566/", Locator{Scheme: "mac", Authority: "112233445566"}, "mac:112233445566"},
+		{"dns:webpa.comcast.com/v2-device-config", Locator{Scheme: "dns", Authority: "webpa.comcast.com", Service: "v2-device-config"}, "dns:webpa.comcast.com/v2-device-config"},
+		{"uuid:ABC-123/service", Locator{Scheme: "uuid", Authority: "ABC-123", Service: "service"}, "uuid:ABC-123/service"},
+		{
+			"event:device-status/mac:112233445566/online",
+			Locator{Scheme: "event", Authority: "device-status", Service: "mac:112233445566", Path: "/online"},
+			"event:device-status/mac:112233445566/online",
+		},
+		{"serial:1234//path", Locator{Scheme: "serial", Authority: "1234", Path: "/path"}, "serial:1234//path"},
+	}
+
+	for _, record := range testData {
+		t.Run(record.value, func(t *testing.T) {
+			var (
+				assert  = assert.New(t)
+				require = require.New(t)
+			)
+
+			actual, err := ParseLocator(record.value)
+			require.NoError(err)
+			assert.Equal(record.expected, actual)
+			assert.Equal(record.canonical, actual.String())
+			assert.Equal(record.expected.Scheme+":"+record.expected.Authority, actual.ID())
+
+			reparsed, err := ParseLocator(actual.String())
+			assert.NoError(err)
+			assert.Equal(actual, reparsed)
+		})
+	}
+}
+
+func TestParseLocatorInvalid(t *testing.T) {
+	for _, value := range []string{"", "foobar.com", "mac:", ":112233445566", "1mac:112233445566", "m-ac:112233445566", "mac:1122 33445566", "mac:invalid", "mac:1122334455", "dns:/service"} {
+		t.Run(value, func(t *testing.T) {
+			assert := assert.New(t)
+			actual, err := ParseLocator(value)
+			assert.Equal(Locator{}, actual)
+			assert.Equal(ErrInvalidLocator, err)
+		})
+	}
+}
+
+func TestCanonicalMAC(t *testing.T) {
+	testData := []struct {
+		value    string
+		expected string
+		valid    bool
+	}{
+		{"112233445566", "112233445566", true},
+		{"11:22:33:AA:bb:66", "112233aabb66", true},
+		{"11-22-33-44-55-66", "112233445566", true},
+		{"1122.3344.5566", "112233445566", true},
+		{"1122334455", "1122334455", false},
+		{"11223344556677", "11223344556677", false},
+		{"invalid", "", false},
+	}
+
+	for _, record := range testData {
+		t.Run(record.value, func(t *testing.T) {
+			assert := assert.New(t)
+			actual, valid := CanonicalMAC(record.value)
+			assert.Equal(record.valid, valid)
+			if record.valid {
+				assert.Equal(record.expected, actual)
+			}
+		})
+	}
+}
+
+func TestParseLocatorWith(t *testing.T) {
+	var (
+		assert  = assert.New(t)
+		require = require.New(t)
+
+		normalizer = func(scheme, authority string) (string, bool) {
+			if scheme == "serial" {
+				trimmed := strings.TrimLeft(authority, "0")
+				return trimmed, len(trimmed) > 0
+			}
+
+			return BuiltinAuthorities(scheme, authority)
+		}
+	)
+
+	actual, err := ParseLocatorWith("serial:000123/config", normalizer)
+	require.NoError(err)
+	assert.Equal(Locator{Scheme: "serial", Authority: "123", Service: "config"}, actual)
+
+	actual, err = ParseLocatorWith("MAC:11:22:33:44:55:66", normalizer)
+	require.NoError(err)
+	assert.Equal(Locator{Scheme: "mac", Authority: "112233445566"}, actual)
+
+	actual, err = ParseLocatorWith("serial:000", normalizer)
+	assert.Equal(Locator{}, actual)
+	assert.Equal(ErrInvalidLocator, err)
+
+	actual, err = ParseLocatorWith("serial:000123", nil)
+	require.NoError(err)
+	assert.Equal(Locator{Scheme: "serial", Authority: "000123"}, actual)
+}
+
+func TestRoutableLocators(t *testing.T) {
+	var (
+		assert  = assert.New(t)
+		require = require.New(t)
+
+		message = &SimpleRequestResponse{
+			Source:      "dns:talaria.comcast.com/api",
+			Destination: "MAC:11:22:33:44:55:66/config",
+		}
+	)
+
+	source, err := SourceLocator(message)
+	require.NoError(err)
+	assert.Equal(Locator{Scheme: "dns", Authority: "talaria.comcast.com", Service: "api"}, source)
+
+	destination, err := DestinationLocator(message)
+	require.NoError(err)
+	assert.Equal(Locator{Scheme: "mac", Authority: "112233445566", Service: "config"}, destination)
+
+	_, err = DestinationLocator(&Message{Destination: "invalid"})
+	assert.Equal(ErrInvalidLocator, err)
+}
@@ -1,5 +1,11 @@
 package wrp
 
+import (
+	"reflect"
+	"sort"
+	"strings"
+)
+
 //go:generate codecgen -st "wrp" -o messages_codec.go messages.go
 
 // Typed is implemented by any WRP type which is associated with a MessageType.  All
@@ -58,6 +64,12 @@ type Routable interface {
 // those new fields must be added to this struct for transcoding to work properly.  And of course:
 // update the tests!
 //
+// Fields that this version of the package does not recognize, such as those from newer WRP versions,
+// can be retained in UnknownFields when decoding and written back out when encoding.  This allows intermediaries
+// to pass such fields through untouched, though they cannot be used by code in this package.  Unknown fields
+// are only handled by Encoders, Decoders, and pools created WithUnknownFields, and only when a *Message is
+// passed to them directly rather than embedded in some other value.  They are not covered by wrpsign signatures.
+//
 // For server code that sends specific messages, use one of the other WRP structs in this package.
 //
 // For server code that needs to read one format and emit another, use this struct as it allows
@@ -80,6 +92,48 @@ type Message struct {
 	ServiceName             string            `wrp:"service_name,omitempty"`
 	URL                     string            `wrp:"url,omitempty"`
 	PartnerIDs              []string          `wrp:"partner_ids,omitempty"`
+	SessionID               string            `wrp:"session_id,omitempty"`
+	QualityOfService        QOSValue          `wrp:"qos,omitempty"`
+	RequestID               string            `wrp:"request_id,omitempty"`
+	ContentEncoding         string            `wrp:"content_encoding,omitempty"`
+
+	// UnknownFields holds any decoded fields that are not recognized, keyed by WRP field name.  Values
+	// are the generic decoded form, e.g. strings, numbers, []byte, slices, and maps.  Entries whose keys
+	// collide with recognized fields are ignored when encoding.
+	UnknownFields map[string]interface{} `wrp:"-"`
+}
+
+// messageFieldNames is the set of WRP field names recognized by Message
+var messageFieldNames = func() map[string]bool {
+	var (
+		t     = reflect.TypeOf(Message{})
+		names = make(map[string]bool, t.NumField())
+	)
+
+	for i := 0; i < t.NumField(); i++ {
+		if name := strings.Split(t.Field(i).Tag.Get("wrp"), ",")[0]; name != "-" {
+			names[name] = true
+		}
+	}
+
+	return names
+}()
+
+// unknownFieldKeys returns the sorted keys of UnknownFields that should be encoded
+func (msg *Message) unknownFieldKeys() []string {
+	if len(msg.UnknownFields) == 0 {
+		return nil
+	}
+
+	keys := make([]string, 0, len(msg.UnknownFields))
+	for key := range msg.UnknownFields {
+		if !messageFieldNames[key] {
+			keys = append(keys, key)
+		}
+	}
+
+	sort.Strings(keys)
+	return keys
 }
 
 func (msg *Message) MessageType() MessageType {
@@ -108,6 +162,7 @@ func (msg *Message) Response(newSource string, requestDeliveryResponse int64) Ro
 	response.Source = newSource
 	response.RequestDeliveryResponse = &requestDeliveryResponse
 	response.Payload = nil
+	response.ContentEncoding = ""
 
 	return &response
 }
@@ -130,6 +185,27 @@ func (msg *Message) SetIncludeSpans(value bool) *Message {
 	return msg
 }
 
+// Authorization represents a WRP message of type AuthMessageType.  Servers send this message to a device
+// immediately after it connects, to inform the device of the outcome of authorization.  The Status field
+// uses HTTP status code semantics, e.g. 200 for an authorized device.
+//
+// https://github.com/Comcast/wrp-c/wiki/Web-Routing-Protocol#authorization-status-definition
+type Authorization struct {
+	// Type is exposed principally for encoding.  This field *must* be set to AuthMessageType,
+	// and is automatically set by the BeforeEncode method.
+	Type   MessageType `wrp:"msg_type"`
+	Status int64       `wrp:"status"`
+}
+
+func (msg *Authorization) BeforeEncode() error {
+	msg.Type = AuthMessageType
+	return nil
+}
+
+func (msg *Authorization) MessageType() MessageType {
+	return msg.Type
+}
+
 // SimpleRequestResponse represents a WRP message of type SimpleRequestResponseMessageType.
 //
 // https://github.com/Comcast/wrp-c/wiki/Web-Routing-Protocol#simple-request-response-definition
@@ -150,6 +226,10 @@ type SimpleRequestResponse struct {
 	IncludeSpans            *bool             `wrp:"include_spans,omitempty"`
 	Payload                 []byte            `wrp:"payload,omitempty"`
 	PartnerIDs              []string          `wrp:"partner_ids,omitempty"`
+	SessionID               string            `wrp:"session_id,omitempty"`
+	QualityOfService        QOSValue          `wrp:"qos,omitempty"`
+	RequestID               string            `wrp:"request_id,omitempty"`
+	ContentEncoding         string            `wrp:"content_encoding,omitempty"`
 }
 
 // SetStatus simplifies setting the optional Status field, which is a pointer type tagged with omitempty.
@@ -201,6 +281,7 @@ func (msg *SimpleRequestResponse) Response(newSource string, requestDeliveryResp
 	response.Source = newSource
 	response.RequestDeliveryResponse = &requestDeliveryResponse
 	response.Payload = nil
+	response.ContentEncoding = ""
 
 	return &response
 }
@@ -215,14 +296,18 @@ func (msg *SimpleRequestResponse) Response(newSource string, requestDeliveryResp
 type SimpleEvent struct {
 	// Type is exposed principally for encoding.  This field *must* be set to SimpleEventMessageType,
 	// and is automatically set by the BeforeEncode method.
-	Type        MessageType       `wrp:"msg_type"`
-	Source      string            `wrp:"source"`
-	Destination string            `wrp:"dest"`
-	ContentType string            `wrp:"content_type,omitempty"`
-	Headers     []string          `wrp:"headers,omitempty"`
-	Metadata    map[string]string `wrp:"metadata,omitempty"`
-	Payload     []byte            `wrp:"payload,omitempty"`
-	PartnerIDs  []string          `wrp:"partner_ids,omitempty"`
+	Type             MessageType       `wrp:"msg_type"`
+	Source           string            `wrp:"source"`
+	Destination      string            `wrp:"dest"`
+	ContentType      string            `wrp:"content_type,omitempty"`
+	Headers          []string          `wrp:"headers,omitempty"`
+	Metadata         map[string]string `wrp:"metadata,omitempty"`
+	Payload          []byte            `wrp:"payload,omitempty"`
+	PartnerIDs       []string          `wrp:"partner_ids,omitempty"`
+	SessionID        string            `wrp:"session_id,omitempty"`
+	QualityOfService QOSValue          `wrp:"qos,omitempty"`
+	RequestID        string            `wrp:"request_id,omitempty"`
+	ContentEncoding  string            `wrp:"content_encoding,omitempty"`
 }
 
 func (msg *SimpleEvent) BeforeEncode() error {
@@ -256,6 +341,7 @@ func (msg *SimpleEvent) Response(newSource string, requestDeliveryResponse int64
 	response.Destination = msg.Source
 	response.Source = newSource
 	response.Payload = nil
+	response.ContentEncoding = ""
 
 	return &response
 }
@@ -279,6 +365,10 @@ type CRUD struct {
 	Path                    string            `wrp:"path"`
 	Payload                 []byte            `wrp:"payload,omitempty"`
 	PartnerIDs              []string          `wrp:"partner_ids,omitempty"`
+	SessionID               string            `wrp:"session_id,omitempty"`
+	QualityOfService        QOSValue          `wrp:"qos,omitempty"`
+	RequestID               string            `wrp:"request_id,omitempty"`
+	ContentEncoding         string            `wrp:"content_encoding,omitempty"`
 }
 
 // SetStatus simplifies setting the optional Status field, which is a pointer type tagged with omitempty.
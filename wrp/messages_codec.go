@@ -60,7 +60,7 @@ func (x *Message) CodecEncodeSelf(e *codec1978.Encoder) {
 		} else {
 			yysep2 := !z.EncBinary()
 			yy2arr2 := z.EncBasicHandle().StructToArray
-			var yyq2 [17]bool
+			var yyq2 [21]bool
 			_ = yyq2
 			_, _ = yysep2, yy2arr2
 			const yyr2 bool = false
@@ -80,8 +80,12 @@ func (x *Message) CodecEncodeSelf(e *codec1978.Encoder) {
 			yyq2[14] = x.ServiceName != ""
 			yyq2[15] = x.URL != ""
 			yyq2[16] = len(x.PartnerIDs) != 0
+			yyq2[17] = x.SessionID != ""
+			yyq2[18] = x.QualityOfService != 0
+			yyq2[19] = x.RequestID != ""
+			yyq2[20] = x.ContentEncoding != ""
 			if yyr2 || yy2arr2 {
-				r.WriteArrayStart(17)
+				r.WriteArrayStart(21)
 			} else {
 				var yynn2 = 1
 				for _, b := range yyq2 {
@@ -628,6 +632,108 @@ func (x *Message) CodecEncodeSelf(e *codec1978.Encoder) {
 					}
 				}
 			}
+			if yyr2 || yy2arr2 {
+				r.WriteArrayElem()
+				if yyq2[17] {
+					yym61 := z.EncBinary()
+					_ = yym61
+					if false {
+					} else {
+						r.EncodeString(codecSelferC_UTF8306, string(x.SessionID))
+					}
+				} else {
+					r.EncodeString(codecSelferC_UTF8306, "")
+				}
+			} else {
+				if yyq2[17] {
+					r.WriteMapElemKey()
+					r.EncodeString(codecSelferC_UTF8306, string("session_id"))
+					r.WriteMapElemValue()
+					yym62 := z.EncBinary()
+					_ = yym62
+					if false {
+					} else {
+						r.EncodeString(codecSelferC_UTF8306, string(x.SessionID))
+					}
+				}
+			}
+			if yyr2 || yy2arr2 {
+				r.WriteArrayElem()
+				if yyq2[18] {
+					yym63 := z.EncBinary()
+					_ = yym63
+					if false {
+					} else if z.HasExtensions() && z.EncExt(x.QualityOfService) {
+					} else {
+						r.EncodeInt(int64(x.QualityOfService))
+					}
+				} else {
+					r.EncodeInt(0)
+				}
+			} else {
+				if yyq2[18] {
+					r.WriteMapElemKey()
+					r.EncodeString(codecSelferC_UTF8306, string("qos"))
+					r.WriteMapElemValue()
+					yym64 := z.EncBinary()
+					_ = yym64
+					if false {
+					} else if z.HasExtensions() && z.EncExt(x.QualityOfService) {
+					} else {
+						r.EncodeInt(int64(x.QualityOfService))
+					}
+				}
+			}
+			if yyr2 || yy2arr2 {
+				r.WriteArrayElem()
+				if yyq2[19] {
+					yym65 := z.EncBinary()
+					_ = yym65
+					if false {
+					} else {
+						r.EncodeString(codecSelferC_UTF8306, string(x.RequestID))
+					}
+				} else {
+					r.EncodeString(codecSelferC_UTF8306, "")
+				}
+			} else {
+				if yyq2[19] {
+					r.WriteMapElemKey()
+					r.EncodeString(codecSelferC_UTF8306, string("request_id"))
+					r.WriteMapElemValue()
+					yym66 := z.EncBinary()
+					_ = yym66
+					if false {
+					} else {
+						r.EncodeString(codecSelferC_UTF8306, string(x.RequestID))
+					}
+				}
+			}
+			if yyr2 || yy2arr2 {
+				r.WriteArrayElem()
+				if yyq2[20] {
+					yym68 := z.EncBinary()
+					_ = yym68
+					if false {
+					} else {
+						r.EncodeString(codecSelferC_UTF8306, string(x.ContentEncoding))
+					}
+				} else {
+					r.EncodeString(codecSelferC_UTF8306, "")
+				}
+			} else {
+				if yyq2[20] {
+					r.WriteMapElemKey()
+					r.EncodeString(codecSelferC_UTF8306, string("content_encoding"))
+					r.WriteMapElemValue()
+					yym69 := z.EncBinary()
+					_ = yym69
+					if false {
+					} else {
+						r.EncodeString(codecSelferC_UTF8306, string(x.ContentEncoding))
+					}
+				}
+			}
 			if yyr2 || yy2arr2 {
 				r.WriteArrayEnd()
 			} else {
@@ -915,6 +1021,55 @@ func (x *Message) codecDecodeSelfFromMap(l int, d *codec1978.Decoder) {
 					z.F.DecSliceStringX(yyv36, d)
 				}
 			}
+		case "session_id":
+			if r.TryDecodeAsNil() {
+				x.SessionID = ""
+			} else {
+				yyv39 := &x.SessionID
+				yym40 := z.DecBinary()
+				_ = yym40
+				if false {
+				} else {
+					*((*string)(yyv39)) = r.DecodeString()
+				}
+			}
+		case "qos":
+			if r.TryDecodeAsNil() {
+				x.QualityOfService = 0
+			} else {
+				yyv41 := &x.QualityOfService
+				yym42 := z.DecBinary()
+				_ = yym42
+				if false {
+				} else if z.HasExtensions() && z.DecExt(yyv41) {
+				} else {
+					*((*int)(yyv41)) = int(r.DecodeInt(codecSelferBitsize306))
+				}
+			}
+		case "request_id":
+			if r.TryDecodeAsNil() {
+				x.RequestID = ""
+			} else {
+				yyv43 := &x.RequestID
+				yym44 := z.DecBinary()
+				_ = yym44
+				if false {
+				} else {
+					*((*string)(yyv43)) = r.DecodeString()
+				}
+			}
+		case "content_encoding":
+			if r.TryDecodeAsNil() {
+				x.ContentEncoding = ""
+			} else {
+				yyv46 := &x.ContentEncoding
+				yym47 := z.DecBinary()
+				_ = yym47
+				if false {
+				} else {
+					*((*string)(yyv46)) = r.DecodeString()
+				}
+			}
 		default:
 			z.DecStructFieldNotFound(-1, yys3)
 		} // end switch yys3
@@ -1325,6 +1480,95 @@ func (x *Message) codecDecodeSelfFromArray(l int, d *codec1978.Decoder) {
 			z.F.DecSliceStringX(yyv71, d)
 		}
 	}
+	yyj38++
+	if yyhl38 {
+		yyb38 = yyj38 > l
+	} else {
+		yyb38 = r.CheckBreak()
+	}
+	if yyb38 {
+		r.ReadArrayEnd()
+		return
+	}
+	r.ReadArrayElem()
+	if r.TryDecodeAsNil() {
+		x.SessionID = ""
+	} else {
+		yyv74 := &x.SessionID
+		yym75 := z.DecBinary()
+		_ = yym75
+		if false {
+		} else {
+			*((*string)(yyv74)) = r.DecodeString()
+		}
+	}
+	yyj38++
+	if yyhl38 {
+		yyb38 = yyj38 > l
+	} else {
+		yyb38 = r.CheckBreak()
+	}
+	if yyb38 {
+		r.ReadArrayEnd()
+		return
+	}
+	r.ReadArrayElem()
+	if r.TryDecodeAsNil() {
+		x.QualityOfService = 0
+	} else {
+		yyv76 := &x.QualityOfService
+		yym77 := z.DecBinary()
+		_ = yym77
+		if false {
+		} else if z.HasExtensions() && z.DecExt(yyv76) {
+		} else {
+			*((*int)(yyv76)) = int(r.DecodeInt(codecSelferBitsize306))
+		}
+	}
+	yyj38++
+	if yyhl38 {
+		yyb38 = yyj38 > l
+	} else {
+		yyb38 = r.CheckBreak()
+	}
+	if yyb38 {
+		r.ReadArrayEnd()
+		return
+	}
+	r.ReadArrayElem()
+	if r.TryDecodeAsNil() {
+		x.RequestID = ""
+	} else {
+		yyv78 := &x.RequestID
+		yym79 := z.DecBinary()
+		_ = yym79
+		if false {
+		} else {
+			*((*string)(yyv78)) = r.DecodeString()
+		}
+	}
+	yyj38++
+	if yyhl38 {
+		yyb38 = yyj38 > l
+	} else {
+		yyb38 = r.CheckBreak()
+	}
+	if yyb38 {
+		r.ReadArrayEnd()
+		return
+	}
+	r.ReadArrayElem()
+	if r.TryDecodeAsNil() {
+		x.ContentEncoding = ""
+	} else {
+		yyv81 := &x.ContentEncoding
+		yym82 := z.DecBinary()
+		_ = yym82
+		if false {
+		} else {
+			*((*string)(yyv81)) = r.DecodeString()
+		}
+	}
 	for {
 		yyj38++
 		if yyhl38 {
@@ -1341,6 +1585,228 @@ func (x *Message) codecDecodeSelfFromArray(l int, d *codec1978.Decoder) {
 	r.ReadArrayEnd()
 }
 
+func (x *Authorization) CodecEncodeSelf(e *codec1978.Encoder) {
+	var h codecSelfer306
+	z, r := codec1978.GenHelperEncoder(e)
+	_, _, _ = h, z, r
+	if x == nil {
+		r.EncodeNil()
+	} else {
+		yym1 := z.EncBinary()
+		_ = yym1
+		if false {
+		} else if z.HasExtensions() && z.EncExt(x) {
+		} else {
+			yysep2 := !z.EncBinary()
+			yy2arr2 := z.EncBasicHandle().StructToArray
+			_, _ = yysep2, yy2arr2
+			const yyr2 bool = false
+			if yyr2 || yy2arr2 {
+				r.WriteArrayStart(2)
+			} else {
+				r.WriteMapStart(2)
+			}
+			if yyr2 || yy2arr2 {
+				r.WriteArrayElem()
+				yym4 := z.EncBinary()
+				_ = yym4
+				if false {
+				} else if z.HasExtensions() && z.EncExt(x.Type) {
+				} else {
+					r.EncodeInt(int64(x.Type))
+				}
+			} else {
+				r.WriteMapElemKey()
+				r.EncodeString(codecSelferC_UTF8306, string("msg_type"))
+				r.WriteMapElemValue()
+				yym5 := z.EncBinary()
+				_ = yym5
+				if false {
+				} else if z.HasExtensions() && z.EncExt(x.Type) {
+				} else {
+					r.EncodeInt(int64(x.Type))
+				}
+			}
+			if yyr2 || yy2arr2 {
+				r.WriteArrayElem()
+				yym7 := z.EncBinary()
+				_ = yym7
+				if false {
+				} else {
+					r.EncodeInt(int64(x.Status))
+				}
+			} else {
+				r.WriteMapElemKey()
+				r.EncodeString(codecSelferC_UTF8306, string("status"))
+				r.WriteMapElemValue()
+				yym8 := z.EncBinary()
+				_ = yym8
+				if false {
+				} else {
+					r.EncodeInt(int64(x.Status))
+				}
+			}
+			if yyr2 || yy2arr2 {
+				r.WriteArrayEnd()
+			} else {
+				r.WriteMapEnd()
+			}
+		}
+	}
+}
+
+func (x *Authorization) CodecDecodeSelf(d *codec1978.Decoder) {
+	var h codecSelfer306
+	z, r := codec1978.GenHelperDecoder(d)
+	_, _, _ = h, z, r
+	yym1 := z.DecBinary()
+	_ = yym1
+	if false {
+	} else if z.HasExtensions() && z.DecExt(x) {
+	} else {
+		yyct2 := r.ContainerType()
+		if yyct2 == codecSelferValueTypeMap306 {
+			yyl2 := r.ReadMapStart()
+			if yyl2 == 0 {
+				r.ReadMapEnd()
+			} else {
+				x.codecDecodeSelfFromMap(yyl2, d)
+			}
+		} else if yyct2 == codecSelferValueTypeArray306 {
+			yyl2 := r.ReadArrayStart()
+			if yyl2 == 0 {
+				r.ReadArrayEnd()
+			} else {
+				x.codecDecodeSelfFromArray(yyl2, d)
+			}
+		} else {
+			panic(codecSelferOnlyMapOrArrayEncodeToStructErr306)
+		}
+	}
+}
+
+func (x *Authorization) codecDecodeSelfFromMap(l int, d *codec1978.Decoder) {
+	var h codecSelfer306
+	z, r := codec1978.GenHelperDecoder(d)
+	_, _, _ = h, z, r
+	var yys3Slc = z.DecScratchBuffer() // default slice to decode into
+	_ = yys3Slc
+	var yyhl3 bool = l >= 0
+	for yyj3 := 0; ; yyj3++ {
+		if yyhl3 {
+			if yyj3 >= l {
+				break
+			}
+		} else {
+			if r.CheckBreak() {
+				break
+			}
+		}
+		r.ReadMapElemKey()
+		yys3Slc = r.DecodeStringAsBytes()
+		yys3 := string(yys3Slc)
+		r.ReadMapElemValue()
+		switch yys3 {
+		case "msg_type":
+			if r.TryDecodeAsNil() {
+				x.Type = 0
+			} else {
+				yyv4 := &x.Type
+				yym5 := z.DecBinary()
+				_ = yym5
+				if false {
+				} else if z.HasExtensions() && z.DecExt(yyv4) {
+				} else {
+					*((*int64)(yyv4)) = int64(r.DecodeInt(64))
+				}
+			}
+		case "status":
+			if r.TryDecodeAsNil() {
+				x.Status = 0
+			} else {
+				yyv6 := &x.Status
+				yym7 := z.DecBinary()
+				_ = yym7
+				if false {
+				} else {
+					*((*int64)(yyv6)) = int64(r.DecodeInt(64))
+				}
+			}
+		default:
+			z.DecStructFieldNotFound(-1, yys3)
+		} // end switch yys3
+	} // end for yyj3
+	r.ReadMapEnd()
+}
+
+func (x *Authorization) codecDecodeSelfFromArray(l int, d *codec1978.Decoder) {
+	var h codecSelfer306
+	z, r := codec1978.GenHelperDecoder(d)
+	_, _, _ = h, z, r
+	var yyj10 int
+	var yyb10 bool
+	var yyhl10 bool = l >= 0
+	yyj10++
+	if yyhl10 {
+		yyb10 = yyj10 > l
+	} else {
+		yyb10 = r.CheckBreak()
+	}
+	if yyb10 {
+		r.ReadArrayEnd()
+		return
+	}
+	r.ReadArrayElem()
+	if r.TryDecodeAsNil() {
+		x.Type = 0
+	} else {
+		yyv11 := &x.Type
+		yym12 := z.DecBinary()
+		_ = yym12
+		if false {
+		} else if z.HasExtensions() && z.DecExt(yyv11) {
+		} else {
+			*((*int64)(yyv11)) = int64(r.DecodeInt(64))
+		}
+	}
+	yyj10++
+	if yyhl10 {
+		yyb10 = yyj10 > l
+	} else {
+		yyb10 = r.CheckBreak()
+	}
+	if yyb10 {
+		r.ReadArrayEnd()
+		return
+	}
+	r.ReadArrayElem()
+	if r.TryDecodeAsNil() {
+		x.Status = 0
+	} else {
+		yyv13 := &x.Status
+		yym14 := z.DecBinary()
+		_ = yym14
+		if false {
+		} else {
+			*((*int64)(yyv13)) = int64(r.DecodeInt(64))
+		}
+	}
+	for {
+		yyj10++
+		if yyhl10 {
+			yyb10 = yyj10 > l
+		} else {
+			yyb10 = r.CheckBreak()
+		}
+		if yyb10 {
+			break
+		}
+		r.ReadArrayElem()
+		z.DecStructFieldNotFound(yyj10-1, "")
+	}
+	r.ReadArrayEnd()
+}
+
 func (x *SimpleRequestResponse) CodecEncodeSelf(e *codec1978.Encoder) {
 	var h codecSelfer306
 	z, r := codec1978.GenHelperEncoder(e)
@@ -1355,7 +1821,7 @@ func (x *SimpleRequestResponse) CodecEncodeSelf(e *codec1978.Encoder) {
 		} else {
 			yysep2 := !z.EncBinary()
 			yy2arr2 := z.EncBasicHandle().StructToArray
-			var yyq2 [14]bool
+			var yyq2 [18]bool
 			_ = yyq2
 			_, _ = yysep2, yy2arr2
 			const yyr2 bool = false
@@ -1370,8 +1836,12 @@ func (x *SimpleRequestResponse) CodecEncodeSelf(e *codec1978.Encoder) {
 			yyq2[11] = x.IncludeSpans != nil
 			yyq2[12] = len(x.Payload) != 0
 			yyq2[13] = len(x.PartnerIDs) != 0
+			yyq2[14] = x.SessionID != ""
+			yyq2[15] = x.QualityOfService != 0
+			yyq2[16] = x.RequestID != ""
+			yyq2[17] = x.ContentEncoding != ""
 			if yyr2 || yy2arr2 {
-				r.WriteArrayStart(14)
+				r.WriteArrayStart(18)
 			} else {
 				var yynn2 = 3
 				for _, b := range yyq2 {
@@ -1767,67 +2237,169 @@ func (x *SimpleRequestResponse) CodecEncodeSelf(e *codec1978.Encoder) {
 			}
 			if yyr2 || yy2arr2 {
 				r.WriteArrayElem()
-				if yyq2[12] {
-					if x.Payload == nil {
-						r.EncodeNil()
+				if yyq2[12] {
+					if x.Payload == nil {
+						r.EncodeNil()
+					} else {
+						yym46 := z.EncBinary()
+						_ = yym46
+						if false {
+						} else {
+							r.EncodeStringBytes(codecSelferC_RAW306, []byte(x.Payload))
+						}
+					}
+				} else {
+					r.EncodeNil()
+				}
+			} else {
+				if yyq2[12] {
+					r.WriteMapElemKey()
+					r.EncodeString(codecSelferC_UTF8306, string("payload"))
+					r.WriteMapElemValue()
+					if x.Payload == nil {
+						r.EncodeNil()
+					} else {
+						yym47 := z.EncBinary()
+						_ = yym47
+						if false {
+						} else {
+							r.EncodeStringBytes(codecSelferC_RAW306, []byte(x.Payload))
+						}
+					}
+				}
+			}
+			if yyr2 || yy2arr2 {
+				r.WriteArrayElem()
+				if yyq2[13] {
+					if x.PartnerIDs == nil {
+						r.EncodeNil()
+					} else {
+						yym49 := z.EncBinary()
+						_ = yym49
+						if false {
+						} else {
+							z.F.EncSliceStringV(x.PartnerIDs, e)
+						}
+					}
+				} else {
+					r.EncodeNil()
+				}
+			} else {
+				if yyq2[13] {
+					r.WriteMapElemKey()
+					r.EncodeString(codecSelferC_UTF8306, string("partner_ids"))
+					r.WriteMapElemValue()
+					if x.PartnerIDs == nil {
+						r.EncodeNil()
+					} else {
+						yym50 := z.EncBinary()
+						_ = yym50
+						if false {
+						} else {
+							z.F.EncSliceStringV(x.PartnerIDs, e)
+						}
+					}
+				}
+			}
+			if yyr2 || yy2arr2 {
+				r.WriteArrayElem()
+				if yyq2[14] {
+					yym52 := z.EncBinary()
+					_ = yym52
+					if false {
+					} else {
+						r.EncodeString(codecSelferC_UTF8306, string(x.SessionID))
+					}
+				} else {
+					r.EncodeString(codecSelferC_UTF8306, "")
+				}
+			} else {
+				if yyq2[14] {
+					r.WriteMapElemKey()
+					r.EncodeString(codecSelferC_UTF8306, string("session_id"))
+					r.WriteMapElemValue()
+					yym53 := z.EncBinary()
+					_ = yym53
+					if false {
+					} else {
+						r.EncodeString(codecSelferC_UTF8306, string(x.SessionID))
+					}
+				}
+			}
+			if yyr2 || yy2arr2 {
+				r.WriteArrayElem()
+				if yyq2[15] {
+					yym54 := z.EncBinary()
+					_ = yym54
+					if false {
+					} else if z.HasExtensions() && z.EncExt(x.QualityOfService) {
+					} else {
+						r.EncodeInt(int64(x.QualityOfService))
+					}
+				} else {
+					r.EncodeInt(0)
+				}
+			} else {
+				if yyq2[15] {
+					r.WriteMapElemKey()
+					r.EncodeString(codecSelferC_UTF8306, string("qos"))
+					r.WriteMapElemValue()
+					yym55 := z.EncBinary()
+					_ = yym55
+					if false {
+					} else if z.HasExtensions() && z.EncExt(x.QualityOfService) {
+					} else {
+						r.EncodeInt(int64(x.QualityOfService))
+					}
+				}
+			}
+			if yyr2 || yy2arr2 {
+				r.WriteArrayElem()
+				if yyq2[16] {
+					yym56 := z.EncBinary()
+					_ = yym56
+					if false {
 					} else {
-						yym46 := z.EncBinary()
-						_ = yym46
-						if false {
-						} else {
-							r.EncodeStringBytes(codecSelferC_RAW306, []byte(x.Payload))
-						}
+						r.EncodeString(codecSelferC_UTF8306, string(x.RequestID))
 					}
 				} else {
-					r.EncodeNil()
+					r.EncodeString(codecSelferC_UTF8306, "")
 				}
 			} else {
-				if yyq2[12] {
+				if yyq2[16] {
 					r.WriteMapElemKey()
-					r.EncodeString(codecSelferC_UTF8306, string("payload"))
+					r.EncodeString(codecSelferC_UTF8306, string("request_id"))
 					r.WriteMapElemValue()
-					if x.Payload == nil {
-						r.EncodeNil()
+					yym57 := z.EncBinary()
+					_ = yym57
+					if false {
 					} else {
-						yym47 := z.EncBinary()
-						_ = yym47
-						if false {
-						} else {
-							r.EncodeStringBytes(codecSelferC_RAW306, []byte(x.Payload))
-						}
+						r.EncodeString(codecSelferC_UTF8306, string(x.RequestID))
 					}
 				}
 			}
 			if yyr2 || yy2arr2 {
 				r.WriteArrayElem()
-				if yyq2[13] {
-					if x.PartnerIDs == nil {
-						r.EncodeNil()
+				if yyq2[17] {
+					yym59 := z.EncBinary()
+					_ = yym59
+					if false {
 					} else {
-						yym49 := z.EncBinary()
-						_ = yym49
-						if false {
-						} else {
-							z.F.EncSliceStringV(x.PartnerIDs, e)
-						}
+						r.EncodeString(codecSelferC_UTF8306, string(x.ContentEncoding))
 					}
 				} else {
-					r.EncodeNil()
+					r.EncodeString(codecSelferC_UTF8306, "")
 				}
 			} else {
-				if yyq2[13] {
+				if yyq2[17] {
 					r.WriteMapElemKey()
-					r.EncodeString(codecSelferC_UTF8306, string("partner_ids"))
+					r.EncodeString(codecSelferC_UTF8306, string("content_encoding"))
 					r.WriteMapElemValue()
-					if x.PartnerIDs == nil {
-						r.EncodeNil()
+					yym60 := z.EncBinary()
+					_ = yym60
+					if false {
 					} else {
-						yym50 := z.EncBinary()
-						_ = yym50
-						if false {
-						} else {
-							z.F.EncSliceStringV(x.PartnerIDs, e)
-						}
+						r.EncodeString(codecSelferC_UTF8306, string(x.ContentEncoding))
 					}
 				}
 			}
@@ -2082,6 +2654,55 @@ func (x *SimpleRequestResponse) codecDecodeSelfFromMap(l int, d *codec1978.Decod
 					z.F.DecSliceStringX(yyv30, d)
 				}
 			}
+		case "session_id":
+			if r.TryDecodeAsNil() {
+				x.SessionID = ""
+			} else {
+				yyv33 := &x.SessionID
+				yym34 := z.DecBinary()
+				_ = yym34
+				if false {
+				} else {
+					*((*string)(yyv33)) = r.DecodeString()
+				}
+			}
+		case "qos":
+			if r.TryDecodeAsNil() {
+				x.QualityOfService = 0
+			} else {
+				yyv35 := &x.QualityOfService
+				yym36 := z.DecBinary()
+				_ = yym36
+				if false {
+				} else if z.HasExtensions() && z.DecExt(yyv35) {
+				} else {
+					*((*int)(yyv35)) = int(r.DecodeInt(codecSelferBitsize306))
+				}
+			}
+		case "request_id":
+			if r.TryDecodeAsNil() {
+				x.RequestID = ""
+			} else {
+				yyv37 := &x.RequestID
+				yym38 := z.DecBinary()
+				_ = yym38
+				if false {
+				} else {
+					*((*string)(yyv37)) = r.DecodeString()
+				}
+			}
+		case "content_encoding":
+			if r.TryDecodeAsNil() {
+				x.ContentEncoding = ""
+			} else {
+				yyv40 := &x.ContentEncoding
+				yym41 := z.DecBinary()
+				_ = yym41
+				if false {
+				} else {
+					*((*string)(yyv40)) = r.DecodeString()
+				}
+			}
 		default:
 			z.DecStructFieldNotFound(-1, yys3)
 		} // end switch yys3
@@ -2426,6 +3047,95 @@ func (x *SimpleRequestResponse) codecDecodeSelfFromArray(l int, d *codec1978.Dec
 			z.F.DecSliceStringX(yyv59, d)
 		}
 	}
+	yyj32++
+	if yyhl32 {
+		yyb32 = yyj32 > l
+	} else {
+		yyb32 = r.CheckBreak()
+	}
+	if yyb32 {
+		r.ReadArrayEnd()
+		return
+	}
+	r.ReadArrayElem()
+	if r.TryDecodeAsNil() {
+		x.SessionID = ""
+	} else {
+		yyv62 := &x.SessionID
+		yym63 := z.DecBinary()
+		_ = yym63
+		if false {
+		} else {
+			*((*string)(yyv62)) = r.DecodeString()
+		}
+	}
+	yyj32++
+	if yyhl32 {
+		yyb32 = yyj32 > l
+	} else {
+		yyb32 = r.CheckBreak()
+	}
+	if yyb32 {
+		r.ReadArrayEnd()
+		return
+	}
+	r.ReadArrayElem()
+	if r.TryDecodeAsNil() {
+		x.QualityOfService = 0
+	} else {
+		yyv64 := &x.QualityOfService
+		yym65 := z.DecBinary()
+		_ = yym65
+		if false {
+		} else if z.HasExtensions() && z.DecExt(yyv64) {
+		} else {
+			*((*int)(yyv64)) = int(r.DecodeInt(codecSelferBitsize306))
+		}
+	}
+	yyj32++
+	if yyhl32 {
+		yyb32 = yyj32 > l
+	} else {
+		yyb32 = r.CheckBreak()
+	}
+	if yyb32 {
+		r.ReadArrayEnd()
+		return
+	}
+	r.ReadArrayElem()
+	if r.TryDecodeAsNil() {
+		x.RequestID = ""
+	} else {
+		yyv66 := &x.RequestID
+		yym67 := z.DecBinary()
+		_ = yym67
+		if false {
+		} else {
+			*((*string)(yyv66)) = r.DecodeString()
+		}
+	}
+	yyj32++
+	if yyhl32 {
+		yyb32 = yyj32 > l
+	} else {
+		yyb32 = r.CheckBreak()
+	}
+	if yyb32 {
+		r.ReadArrayEnd()
+		return
+	}
+	r.ReadArrayElem()
+	if r.TryDecodeAsNil() {
+		x.ContentEncoding = ""
+	} else {
+		yyv69 := &x.ContentEncoding
+		yym70 := z.DecBinary()
+		_ = yym70
+		if false {
+		} else {
+			*((*string)(yyv69)) = r.DecodeString()
+		}
+	}
 	for {
 		yyj32++
 		if yyhl32 {
@@ -2456,7 +3166,7 @@ func (x *SimpleEvent) CodecEncodeSelf(e *codec1978.Encoder) {
 		} else {
 			yysep2 := !z.EncBinary()
 			yy2arr2 := z.EncBasicHandle().StructToArray
-			var yyq2 [8]bool
+			var yyq2 [12]bool
 			_ = yyq2
 			_, _ = yysep2, yy2arr2
 			const yyr2 bool = false
@@ -2465,8 +3175,12 @@ func (x *SimpleEvent) CodecEncodeSelf(e *codec1978.Encoder) {
 			yyq2[5] = len(x.Metadata) != 0
 			yyq2[6] = len(x.Payload) != 0
 			yyq2[7] = len(x.PartnerIDs) != 0
+			yyq2[8] = x.SessionID != ""
+			yyq2[9] = x.QualityOfService != 0
+			yyq2[10] = x.RequestID != ""
+			yyq2[11] = x.ContentEncoding != ""
 			if yyr2 || yy2arr2 {
-				r.WriteArrayStart(8)
+				r.WriteArrayStart(12)
 			} else {
 				var yynn2 = 3
 				for _, b := range yyq2 {
@@ -2693,6 +3407,108 @@ func (x *SimpleEvent) CodecEncodeSelf(e *codec1978.Encoder) {
 					}
 				}
 			}
+			if yyr2 || yy2arr2 {
+				r.WriteArrayElem()
+				if yyq2[8] {
+					yym28 := z.EncBinary()
+					_ = yym28
+					if false {
+					} else {
+						r.EncodeString(codecSelferC_UTF8306, string(x.SessionID))
+					}
+				} else {
+					r.EncodeString(codecSelferC_UTF8306, "")
+				}
+			} else {
+				if yyq2[8] {
+					r.WriteMapElemKey()
+					r.EncodeString(codecSelferC_UTF8306, string("session_id"))
+					r.WriteMapElemValue()
+					yym29 := z.EncBinary()
+					_ = yym29
+					if false {
+					} else {
+						r.EncodeString(codecSelferC_UTF8306, string(x.SessionID))
+					}
+				}
+			}
+			if yyr2 || yy2arr2 {
+				r.WriteArrayElem()
+				if yyq2[9] {
+					yym30 := z.EncBinary()
+					_ = yym30
+					if false {
+					} else if z.HasExtensions() && z.EncExt(x.QualityOfService) {
+					} else {
+						r.EncodeInt(int64(x.QualityOfService))
+					}
+				} else {
+					r.EncodeInt(0)
+				}
+			} else {
+				if yyq2[9] {
+					r.WriteMapElemKey()
+					r.EncodeString(codecSelferC_UTF8306, string("qos"))
+					r.WriteMapElemValue()
+					yym31 := z.EncBinary()
+					_ = yym31
+					if false {
+					} else if z.HasExtensions() && z.EncExt(x.QualityOfService) {
+					} else {
+						r.EncodeInt(int64(x.QualityOfService))
+					}
+				}
+			}
+			if yyr2 || yy2arr2 {
+				r.WriteArrayElem()
+				if yyq2[10] {
+					yym32 := z.EncBinary()
+					_ = yym32
+					if false {
+					} else {
+						r.EncodeString(codecSelferC_UTF8306, string(x.RequestID))
+					}
+				} else {
+					r.EncodeString(codecSelferC_UTF8306, "")
+				}
+			} else {
+				if yyq2[10] {
+					r.WriteMapElemKey()
+					r.EncodeString(codecSelferC_UTF8306, string("request_id"))
+					r.WriteMapElemValue()
+					yym33 := z.EncBinary()
+					_ = yym33
+					if false {
+					} else {
+						r.EncodeString(codecSelferC_UTF8306, string(x.RequestID))
+					}
+				}
+			}
+			if yyr2 || yy2arr2 {
+				r.WriteArrayElem()
+				if yyq2[11] {
+					yym35 := z.EncBinary()
+					_ = yym35
+					if false {
+					} else {
+						r.EncodeString(codecSelferC_UTF8306, string(x.ContentEncoding))
+					}
+				} else {
+					r.EncodeString(codecSelferC_UTF8306, "")
+				}
+			} else {
+				if yyq2[11] {
+					r.WriteMapElemKey()
+					r.EncodeString(codecSelferC_UTF8306, string("content_encoding"))
+					r.WriteMapElemValue()
+					yym36 := z.EncBinary()
+					_ = yym36
+					if false {
+					} else {
+						r.EncodeString(codecSelferC_UTF8306, string(x.ContentEncoding))
+					}
+				}
+			}
 			if yyr2 || yy2arr2 {
 				r.WriteArrayEnd()
 			} else {
@@ -2831,24 +3647,73 @@ func (x *SimpleEvent) codecDecodeSelfFromMap(l int, d *codec1978.Decoder) {
 			if r.TryDecodeAsNil() {
 				x.Payload = nil
 			} else {
-				yyv16 := &x.Payload
-				yym17 := z.DecBinary()
-				_ = yym17
+				yyv16 := &x.Payload
+				yym17 := z.DecBinary()
+				_ = yym17
+				if false {
+				} else {
+					*yyv16 = r.DecodeBytes(*(*[]byte)(yyv16), false)
+				}
+			}
+		case "partner_ids":
+			if r.TryDecodeAsNil() {
+				x.PartnerIDs = nil
+			} else {
+				yyv18 := &x.PartnerIDs
+				yym19 := z.DecBinary()
+				_ = yym19
+				if false {
+				} else {
+					z.F.DecSliceStringX(yyv18, d)
+				}
+			}
+		case "session_id":
+			if r.TryDecodeAsNil() {
+				x.SessionID = ""
+			} else {
+				yyv21 := &x.SessionID
+				yym22 := z.DecBinary()
+				_ = yym22
+				if false {
+				} else {
+					*((*string)(yyv21)) = r.DecodeString()
+				}
+			}
+		case "qos":
+			if r.TryDecodeAsNil() {
+				x.QualityOfService = 0
+			} else {
+				yyv23 := &x.QualityOfService
+				yym24 := z.DecBinary()
+				_ = yym24
+				if false {
+				} else if z.HasExtensions() && z.DecExt(yyv23) {
+				} else {
+					*((*int)(yyv23)) = int(r.DecodeInt(codecSelferBitsize306))
+				}
+			}
+		case "request_id":
+			if r.TryDecodeAsNil() {
+				x.RequestID = ""
+			} else {
+				yyv25 := &x.RequestID
+				yym26 := z.DecBinary()
+				_ = yym26
 				if false {
 				} else {
-					*yyv16 = r.DecodeBytes(*(*[]byte)(yyv16), false)
+					*((*string)(yyv25)) = r.DecodeString()
 				}
 			}
-		case "partner_ids":
+		case "content_encoding":
 			if r.TryDecodeAsNil() {
-				x.PartnerIDs = nil
+				x.ContentEncoding = ""
 			} else {
-				yyv18 := &x.PartnerIDs
-				yym19 := z.DecBinary()
-				_ = yym19
+				yyv28 := &x.ContentEncoding
+				yym29 := z.DecBinary()
+				_ = yym29
 				if false {
 				} else {
-					z.F.DecSliceStringX(yyv18, d)
+					*((*string)(yyv28)) = r.DecodeString()
 				}
 			}
 		default:
@@ -3042,6 +3907,95 @@ func (x *SimpleEvent) codecDecodeSelfFromArray(l int, d *codec1978.Decoder) {
 			z.F.DecSliceStringX(yyv35, d)
 		}
 	}
+	yyj20++
+	if yyhl20 {
+		yyb20 = yyj20 > l
+	} else {
+		yyb20 = r.CheckBreak()
+	}
+	if yyb20 {
+		r.ReadArrayEnd()
+		return
+	}
+	r.ReadArrayElem()
+	if r.TryDecodeAsNil() {
+		x.SessionID = ""
+	} else {
+		yyv38 := &x.SessionID
+		yym39 := z.DecBinary()
+		_ = yym39
+		if false {
+		} else {
+			*((*string)(yyv38)) = r.DecodeString()
+		}
+	}
+	yyj20++
+	if yyhl20 {
+		yyb20 = yyj20 > l
+	} else {
+		yyb20 = r.CheckBreak()
+	}
+	if yyb20 {
+		r.ReadArrayEnd()
+		return
+	}
+	r.ReadArrayElem()
+	if r.TryDecodeAsNil() {
+		x.QualityOfService = 0
+	} else {
+		yyv40 := &x.QualityOfService
+		yym41 := z.DecBinary()
+		_ = yym41
+		if false {
+		} else if z.HasExtensions() && z.DecExt(yyv40) {
+		} else {
+			*((*int)(yyv40)) = int(r.DecodeInt(codecSelferBitsize306))
+		}
+	}
+	yyj20++
+	if yyhl20 {
+		yyb20 = yyj20 > l
+	} else {
+		yyb20 = r.CheckBreak()
+	}
+	if yyb20 {
+		r.ReadArrayEnd()
+		return
+	}
+	r.ReadArrayElem()
+	if r.TryDecodeAsNil() {
+		x.RequestID = ""
+	} else {
+		yyv42 := &x.RequestID
+		yym43 := z.DecBinary()
+		_ = yym43
+		if false {
+		} else {
+			*((*string)(yyv42)) = r.DecodeString()
+		}
+	}
+	yyj20++
+	if yyhl20 {
+		yyb20 = yyj20 > l
+	} else {
+		yyb20 = r.CheckBreak()
+	}
+	if yyb20 {
+		r.ReadArrayEnd()
+		return
+	}
+	r.ReadArrayElem()
+	if r.TryDecodeAsNil() {
+		x.ContentEncoding = ""
+	} else {
+		yyv45 := &x.ContentEncoding
+		yym46 := z.DecBinary()
+		_ = yym46
+		if false {
+		} else {
+			*((*string)(yyv45)) = r.DecodeString()
+		}
+	}
 	for {
 		yyj20++
 		if yyhl20 {
@@ -3072,7 +4026,7 @@ func (x *CRUD) CodecEncodeSelf(e *codec1978.Encoder) {
 		} else {
 			yysep2 := !z.EncBinary()
 			yy2arr2 := z.EncBasicHandle().StructToArray
-			var yyq2 [14]bool
+			var yyq2 [18]bool
 			_ = yyq2
 			_, _ = yysep2, yy2arr2
 			const yyr2 bool = false
@@ -3086,8 +4040,12 @@ func (x *CRUD) CodecEncodeSelf(e *codec1978.Encoder) {
 			yyq2[10] = x.RequestDeliveryResponse != nil
 			yyq2[12] = len(x.Payload) != 0
 			yyq2[13] = len(x.PartnerIDs) != 0
+			yyq2[14] = x.SessionID != ""
+			yyq2[15] = x.QualityOfService != 0
+			yyq2[16] = x.RequestID != ""
+			yyq2[17] = x.ContentEncoding != ""
 			if yyr2 || yy2arr2 {
-				r.WriteArrayStart(14)
+				r.WriteArrayStart(18)
 			} else {
 				var yynn2 = 4
 				for _, b := range yyq2 {
@@ -3541,6 +4499,108 @@ func (x *CRUD) CodecEncodeSelf(e *codec1978.Encoder) {
 					}
 				}
 			}
+			if yyr2 || yy2arr2 {
+				r.WriteArrayElem()
+				if yyq2[14] {
+					yym52 := z.EncBinary()
+					_ = yym52
+					if false {
+					} else {
+						r.EncodeString(codecSelferC_UTF8306, string(x.SessionID))
+					}
+				} else {
+					r.EncodeString(codecSelferC_UTF8306, "")
+				}
+			} else {
+				if yyq2[14] {
+					r.WriteMapElemKey()
+					r.EncodeString(codecSelferC_UTF8306, string("session_id"))
+					r.WriteMapElemValue()
+					yym53 := z.EncBinary()
+					_ = yym53
+					if false {
+					} else {
+						r.EncodeString(codecSelferC_UTF8306, string(x.SessionID))
+					}
+				}
+			}
+			if yyr2 || yy2arr2 {
+				r.WriteArrayElem()
+				if yyq2[15] {
+					yym54 := z.EncBinary()
+					_ = yym54
+					if false {
+					} else if z.HasExtensions() && z.EncExt(x.QualityOfService) {
+					} else {
+						r.EncodeInt(int64(x.QualityOfService))
+					}
+				} else {
+					r.EncodeInt(0)
+				}
+			} else {
+				if yyq2[15] {
+					r.WriteMapElemKey()
+					r.EncodeString(codecSelferC_UTF8306, string("qos"))
+					r.WriteMapElemValue()
+					yym55 := z.EncBinary()
+					_ = yym55
+					if false {
+					} else if z.HasExtensions() && z.EncExt(x.QualityOfService) {
+					} else {
+						r.EncodeInt(int64(x.QualityOfService))
+					}
+				}
+			}
+			if yyr2 || yy2arr2 {
+				r.WriteArrayElem()
+				if yyq2[16] {
+					yym56 := z.EncBinary()
+					_ = yym56
+					if false {
+					} else {
+						r.EncodeString(codecSelferC_UTF8306, string(x.RequestID))
+					}
+				} else {
+					r.EncodeString(codecSelferC_UTF8306, "")
+				}
+			} else {
+				if yyq2[16] {
+					r.WriteMapElemKey()
+					r.EncodeString(codecSelferC_UTF8306, string("request_id"))
+					r.WriteMapElemValue()
+					yym57 := z.EncBinary()
+					_ = yym57
+					if false {
+					} else {
+						r.EncodeString(codecSelferC_UTF8306, string(x.RequestID))
+					}
+				}
+			}
+			if yyr2 || yy2arr2 {
+				r.WriteArrayElem()
+				if yyq2[17] {
+					yym59 := z.EncBinary()
+					_ = yym59
+					if false {
+					} else {
+						r.EncodeString(codecSelferC_UTF8306, string(x.ContentEncoding))
+					}
+				} else {
+					r.EncodeString(codecSelferC_UTF8306, "")
+				}
+			} else {
+				if yyq2[17] {
+					r.WriteMapElemKey()
+					r.EncodeString(codecSelferC_UTF8306, string("content_encoding"))
+					r.WriteMapElemValue()
+					yym60 := z.EncBinary()
+					_ = yym60
+					if false {
+					} else {
+						r.EncodeString(codecSelferC_UTF8306, string(x.ContentEncoding))
+					}
+				}
+			}
 			if yyr2 || yy2arr2 {
 				r.WriteArrayEnd()
 			} else {
@@ -3792,6 +4852,55 @@ func (x *CRUD) codecDecodeSelfFromMap(l int, d *codec1978.Decoder) {
 					z.F.DecSliceStringX(yyv30, d)
 				}
 			}
+		case "session_id":
+			if r.TryDecodeAsNil() {
+				x.SessionID = ""
+			} else {
+				yyv33 := &x.SessionID
+				yym34 := z.DecBinary()
+				_ = yym34
+				if false {
+				} else {
+					*((*string)(yyv33)) = r.DecodeString()
+				}
+			}
+		case "qos":
+			if r.TryDecodeAsNil() {
+				x.QualityOfService = 0
+			} else {
+				yyv35 := &x.QualityOfService
+				yym36 := z.DecBinary()
+				_ = yym36
+				if false {
+				} else if z.HasExtensions() && z.DecExt(yyv35) {
+				} else {
+					*((*int)(yyv35)) = int(r.DecodeInt(codecSelferBitsize306))
+				}
+			}
+		case "request_id":
+			if r.TryDecodeAsNil() {
+				x.RequestID = ""
+			} else {
+				yyv37 := &x.RequestID
+				yym38 := z.DecBinary()
+				_ = yym38
+				if false {
+				} else {
+					*((*string)(yyv37)) = r.DecodeString()
+				}
+			}
+		case "content_encoding":
+			if r.TryDecodeAsNil() {
+				x.ContentEncoding = ""
+			} else {
+				yyv40 := &x.ContentEncoding
+				yym41 := z.DecBinary()
+				_ = yym41
+				if false {
+				} else {
+					*((*string)(yyv40)) = r.DecodeString()
+				}
+			}
 		default:
 			z.DecStructFieldNotFound(-1, yys3)
 		} // end switch yys3
@@ -4136,6 +5245,95 @@ func (x *CRUD) codecDecodeSelfFromArray(l int, d *codec1978.Decoder) {
 			z.F.DecSliceStringX(yyv59, d)
 		}
 	}
+	yyj32++
+	if yyhl32 {
+		yyb32 = yyj32 > l
+	} else {
+		yyb32 = r.CheckBreak()
+	}
+	if yyb32 {
+		r.ReadArrayEnd()
+		return
+	}
+	r.ReadArrayElem()
+	if r.TryDecodeAsNil() {
+		x.SessionID = ""
+	} else {
+		yyv62 := &x.SessionID
+		yym63 := z.DecBinary()
+		_ = yym63
+		if false {
+		} else {
+			*((*string)(yyv62)) = r.DecodeString()
+		}
+	}
+	yyj32++
+	if yyhl32 {
+		yyb32 = yyj32 > l
+	} else {
+		yyb32 = r.CheckBreak()
+	}
+	if yyb32 {
+		r.ReadArrayEnd()
+		return
+	}
+	r.ReadArrayElem()
+	if r.TryDecodeAsNil() {
+		x.QualityOfService = 0
+	} else {
+		yyv64 := &x.QualityOfService
+		yym65 := z.DecBinary()
+		_ = yym65
+		if false {
+		} else if z.HasExtensions() && z.DecExt(yyv64) {
+		} else {
+			*((*int)(yyv64)) = int(r.DecodeInt(codecSelferBitsize306))
+		}
+	}
+	yyj32++
+	if yyhl32 {
+		yyb32 = yyj32 > l
+	} else {
+		yyb32 = r.CheckBreak()
+	}
+	if yyb32 {
+		r.ReadArrayEnd()
+		return
+	}
+	r.ReadArrayElem()
+	if r.TryDecodeAsNil() {
+		x.RequestID = ""
+	} else {
+		yyv66 := &x.RequestID
+		yym67 := z.DecBinary()
+		_ = yym67
+		if false {
+		} else {
+			*((*string)(yyv66)) = r.DecodeString()
+		}
+	}
+	yyj32++
+	if yyhl32 {
+		yyb32 = yyj32 > l
+	} else {
+		yyb32 = r.CheckBreak()
+	}
+	if yyb32 {
+		r.ReadArrayEnd()
+		return
+	}
+	r.ReadArrayElem()
+	if r.TryDecodeAsNil() {
+		x.ContentEncoding = ""
+	} else {
+		yyv69 := &x.ContentEncoding
+		yym70 := z.DecBinary()
+		_ = yym70
+		if false {
+		} else {
+			*((*string)(yyv69)) = r.DecodeString()
+		}
+	}
 	for {
 		yyj32++
 		if yyhl32 {
@@ -11,7 +11,7 @@ import (
 
 var (
 	// allFormats enumerates all of the supported formats to use in testing
-	allFormats = []Format{JSON, Msgpack}
+	allFormats = []Format{JSON, Msgpack, CBOR}
 )
 
 func testMessageSetStatus(t *testing.T) {
@@ -141,14 +141,18 @@ func TestMessage(t *testing.T) {
 				Spans:           [][]string{{"1", "2"}, {"3"}},
 				Payload:         []byte{1, 2, 3, 4, 0xff, 0xce},
 				PartnerIDs:      []string{"foo"},
+				SessionID:       "session-1",
+				RequestID:       "request-1",
+				ContentEncoding: "gzip",
 			},
 			{
-				Type:        CreateMessageType,
-				Source:      "wherever.webpa.comcast.net/glorious",
-				Destination: "uuid:1111-11-111111-11111",
-				Path:        "/some/where/over/the/rainbow",
-				Payload:     []byte{1, 2, 3, 4, 0xff, 0xce},
-				PartnerIDs:  []string{"foo", "bar"},
+				Type:             CreateMessageType,
+				Source:           "wherever.webpa.comcast.net/glorious",
+				Destination:      "uuid:1111-11-111111-11111",
+				Path:             "/some/where/over/the/rainbow",
+				Payload:          []byte{1, 2, 3, 4, 0xff, 0xce},
+				PartnerIDs:       []string{"foo", "bar"},
+				QualityOfService: QOSHighValue,
 			},
 		}
 	)
@@ -285,13 +289,17 @@ func TestSimpleRequestResponse(t *testing.T) {
 				IncludeSpans:            &expectedIncludeSpans,
 			},
 			{
-				Source:          "external.com",
-				Destination:     "mac:FFEEAADD44443333",
-				TransactionUUID: "DEADBEEF",
-				Headers:         []string{"Header1", "Header2"},
-				Metadata:        map[string]string{"name": "value"},
-				Spans:           [][]string{{"1", "2"}, {"3"}},
-				Payload:         []byte{1, 2, 3, 4, 0xff, 0xce},
+				Source:           "external.com",
+				Destination:      "mac:FFEEAADD44443333",
+				TransactionUUID:  "DEADBEEF",
+				Headers:          []string{"Header1", "Header2"},
+				Metadata:         map[string]string{"name": "value"},
+				Spans:            [][]string{{"1", "2"}, {"3"}},
+				Payload:          []byte{1, 2, 3, 4, 0xff, 0xce},
+				SessionID:        "session-1",
+				QualityOfService: 12,
+				RequestID:        "request-1",
+				ContentEncoding:  "gzip",
 			},
 		}
 	)
@@ -361,12 +369,16 @@ func TestSimpleEvent(t *testing.T) {
 			Payload:     []byte("this is a lovely payloed"),
 		},
 		{
-			Source:      "mac:123123123123123123",
-			Destination: "something.webpa.comcast.net:9090/here/is/a/path",
-			ContentType: "text/plain",
-			Headers:     []string{"header1"},
-			Metadata:    map[string]string{"a": "b", "c": "d"},
-			Payload:     []byte("check this out!"),
+			Source:           "mac:123123123123123123",
+			Destination:      "something.webpa.comcast.net:9090/here/is/a/path",
+			ContentType:      "text/plain",
+			Headers:          []string{"header1"},
+			Metadata:         map[string]string{"a": "b", "c": "d"},
+			Payload:          []byte("check this out!"),
+			SessionID:        "session-1",
+			QualityOfService: QOSCriticalValue,
+			RequestID:        "request-1",
+			ContentEncoding:  "gzip",
 		},
 	}
 
@@ -504,6 +516,10 @@ func TestCRUD(t *testing.T) {
 				IncludeSpans:            &expectedIncludeSpans,
 				Path:                    "/somewhere/over/rainbow",
 				Payload:                 []byte{1, 2, 3, 4, 0xff, 0xce},
+				SessionID:               "session-1",
+				QualityOfService:        QOSMediumValue,
+				RequestID:               "request-1",
+				ContentEncoding:         "gzip",
 			},
 			{
 				Type:            UpdateMessageType,
@@ -532,6 +548,215 @@ func TestCRUD(t *testing.T) {
 	}
 }
 
+func testMessageUnknownFields(t *testing.T, source, target Format) {
+	var (
+		assert  = assert.New(t)
+		require = require.New(t)
+
+		original = map[string]interface{}{
+			"msg_type":      int64(SimpleEventMessageType),
+			"source":        "mac:112233445566",
+			"dest":          "event:device-status",
+			"future_string": "value",
+			"future_number": 42,
+			"future_list":   []interface{}{"a", "b"},
+		}
+
+		encoded []byte
+		message Message
+	)
+
+	require.NoError(NewEncoderBytes(&encoded, source).Encode(original))
+	require.NoError(NewDecoderBytes(encoded, source, WithUnknownFields(true)).Decode(&message))
+	assert.Equal(SimpleEventMessageType, message.Type)
+	assert.Equal("mac:112233445566", message.Source)
+	assert.Equal("event:device-status", message.Destination)
+	require.Len(message.UnknownFields, 3)
+	assert.Equal("value", message.UnknownFields["future_string"])
+	assert.EqualValues(42, message.UnknownFields["future_number"])
+
+	var (
+		transcoded []byte
+		decoded    map[string]interface{}
+	)
+
+	require.NoError(NewEncoderBytes(&transcoded, target, WithUnknownFields(true)).Encode(&message))
+	require.NoError(NewDecoderBytes(transcoded, target).Decode(&decoded))
+	assert.Len(decoded, len(original))
+	assert.Equal("value", decoded["future_string"])
+	assert.EqualValues(42, decoded["future_number"])
+	assert.Len(decoded["future_list"], 2)
+
+	var roundTrip Message
+	require.NoError(NewDecoderBytes(transcoded, target, WithUnknownFields(true)).Decode(&roundTrip))
+	assert.Equal(message.Source, roundTrip.Source)
+	assert.Equal(message.Destination, roundTrip.Destination)
+	assert.Len(roundTrip.UnknownFields, 3)
+}
+
+func testMessageUnknownFieldsCollision(t *testing.T, f Format) {
+	var (
+		assert  = assert.New(t)
+		require = require.New(t)
+
+		message = Message{
+			Type:          SimpleEventMessageType,
+			Source:        "mac:112233445566",
+			UnknownFields: map[string]interface{}{"source": "ignored", "msg_type": 999, "future": true},
+		}
+
+		encoded []byte
+		decoded Message
+	)
+
+	require.NoError(NewEncoderBytes(&encoded, f, WithUnknownFields(true)).Encode(&message))
+	require.NoError(NewDecoderBytes(encoded, f, WithUnknownFields(true)).Decode(&decoded))
+	assert.Equal(SimpleEventMessageType, decoded.Type)
+	assert.Equal("mac:112233445566", decoded.Source)
+	assert.Equal(map[string]interface{}{"future": true}, decoded.UnknownFields)
+}
+
+func testMessageUnknownFieldsStream(t *testing.T, f Format) {
+	var (
+		assert  = assert.New(t)
+		require = require.New(t)
+
+		output  bytes.Buffer
+		encoder = NewEncoder(&output, f, WithUnknownFields(true))
+
+		first = Message{
+			Type:          SimpleEventMessageType,
+			Source:        "mac:112233445566",
+			UnknownFields: map[string]interface{}{"future": "first"},
+		}
+
+		second = Message{
+			Type:   SimpleEventMessageType,
+			Source: "mac:665544332211",
+		}
+	)
+
+	require.NoError(encoder.Encode(&first))
+	require.NoError(encoder.Encode(&second))
+
+	decoder := NewDecoder(&output, f, WithUnknownFields(true))
+
+	var actual Message
+	require.NoError(decoder.Decode(&actual))
+	assert.Equal(first, actual)
+
+	actual = Message{}
+	require.NoError(decoder.Decode(&actual))
+	assert.Equal(second, actual)
+}
+
+func testMessageUnknownFieldsDisabled(t *testing.T, f Format) {
+	var (
+		assert  = assert.New(t)
+		require = require.New(t)
+
+		message = Message{
+			Type:          SimpleEventMessageType,
+			Source:        "mac:112233445566",
+			UnknownFields: map[string]interface{}{"future": "value"},
+		}
+
+		encoded []byte
+		fields  map[string]interface{}
+		decoded Message
+	)
+
+	// by default, UnknownFields are neither written nor read
+	require.NoError(NewEncoderBytes(&encoded, f).Encode(&message))
+	require.NoError(NewDecoderBytes(encoded, f).Decode(&fields))
+	assert.NotContains(fields, "future")
+
+	encoded = nil
+	require.NoError(NewEncoderBytes(&encoded, f, WithUnknownFields(true)).Encode(&message))
+	require.NoError(NewDecoderBytes(encoded, f).Decode(&decoded))
+	assert.Equal("mac:112233445566", decoded.Source)
+	assert.Nil(decoded.UnknownFields)
+
+	decoded = Message{}
+	require.NoError(NewDecoderPool(1, f, WithUnknownFields(true)).DecodeBytes(&decoded, encoded))
+	assert.Equal(map[string]interface{}{"future": "value"}, decoded.UnknownFields)
+
+	pooled, err := NewEncoderPool(1, f, WithUnknownFields(true)).EncodeBytes(&message)
+	require.NoError(err)
+	assert.Equal(encoded, pooled)
+}
+
+func TestMessageUnknownFields(t *testing.T) {
+	for _, source := range allFormats {
+		for _, target := range allFormats {
+			t.Run(fmt.Sprintf("%s/%s", source, target), func(t *testing.T) {
+				testMessageUnknownFields(t, source, target)
+			})
+		}
+	}
+
+	for _, f := range allFormats {
+		t.Run(fmt.Sprintf("Collision%s", f), func(t *testing.T) {
+			testMessageUnknownFieldsCollision(t, f)
+		})
+	}
+
+	for _, f := range allFormats {
+		t.Run(fmt.Sprintf("Stream%s", f), func(t *testing.T) {
+			testMessageUnknownFieldsStream(t, f)
+		})
+	}
+
+	for _, f := range allFormats {
+		t.Run(fmt.Sprintf("Disabled%s", f), func(t *testing.T) {
+			testMessageUnknownFieldsDisabled(t, f)
+		})
+	}
+}
+
+func testAuthorizationEncode(t *testing.T, f Format, original Authorization) {
+	var (
+		assert  = assert.New(t)
+		decoded Authorization
+		message Message
+
+		buffer  bytes.Buffer
+		encoder = NewEncoder(&buffer, f)
+	)
+
+	assert.NoError(encoder.Encode(&original))
+	assert.True(buffer.Len() > 0)
+	assert.Equal(AuthMessageType, original.Type)
+
+	encoded := buffer.Bytes()
+	assert.NoError(NewDecoderBytes(encoded, f).Decode(&decoded))
+	assert.Equal(original, decoded)
+	assert.Equal(AuthMessageType, decoded.MessageType())
+
+	// an Authorization must be readable as a Message, so that it can be transcoded
+	assert.NoError(NewDecoderBytes(encoded, f).Decode(&message))
+	assert.Equal(AuthMessageType, message.Type)
+	if assert.NotNil(message.Status) {
+		assert.Equal(original.Status, *message.Status)
+	}
+}
+
+func TestAuthorization(t *testing.T) {
+	var messages = []Authorization{
+		{},
+		{Status: 200},
+		{Status: 401},
+	}
+
+	for _, format := range allFormats {
+		t.Run(fmt.Sprintf("Encode%s", format), func(t *testing.T) {
+			for _, message := range messages {
+				testAuthorizationEncode(t, format, message)
+			}
+		})
+	}
+}
+
 func testServiceRegistrationEncode(t *testing.T, f Format, original ServiceRegistration) {
 	var (
 		assert  = assert.New(t)
@@ -11,7 +11,8 @@ import (
 type MessageType int64
 
 const (
-	SimpleRequestResponseMessageType  MessageType = iota + 3
+	AuthMessageType MessageType = iota + 2
+	SimpleRequestResponseMessageType
 	SimpleEventMessageType
 	CreateMessageType
 	RetrieveMessageType
@@ -27,6 +28,8 @@ const (
 // where applicable).
 func (mt MessageType) SupportsTransaction() bool {
 	switch mt {
+	case AuthMessageType:
+		return false
 	case SimpleEventMessageType:
 		return false
 	case ServiceRegistrationMessageType:
@@ -63,7 +66,7 @@ func init() {
 	// The integral value of the constant
 	// The String() value
 	// The String() value minus the MessageType suffix
-	for v := SimpleRequestResponseMessageType; v < lastMessageType; v++ {
+	for v := AuthMessageType; v < lastMessageType; v++ {
 		stringToMessageType[strconv.Itoa(int(v))] = v
 
 		vs := v.String()
@@ -4,14 +4,14 @@ package wrp
 
 import "strconv"
 
-const _MessageType_name = "SimpleRequestResponseMessageTypeSimpleEventMessageTypeCreateMessageTypeRetrieveMessageTypeUpdateMessageTypeDeleteMessageTypeServiceRegistrationMessageTypeServiceAliveMessageTypelastMessageType"
+const _MessageType_name = "AuthMessageTypeSimpleRequestResponseMessageTypeSimpleEventMessageTypeCreateMessageTypeRetrieveMessageTypeUpdateMessageTypeDeleteMessageTypeServiceRegistrationMessageTypeServiceAliveMessageTypelastMessageType"
 
-var _MessageType_index = [...]uint8{0, 32, 54, 71, 90, 107, 124, 154, 177, 192}
+var _MessageType_index = [...]uint8{0, 15, 47, 69, 86, 105, 122, 139, 169, 192, 207}
 
 func (i MessageType) String() string {
-	i -= 3
+	i -= 2
 	if i < 0 || i >= MessageType(len(_MessageType_index)-1) {
-		return "MessageType(" + strconv.FormatInt(int64(i+3), 10) + ")"
+		return "MessageType(" + strconv.FormatInt(int64(i+2), 10) + ")"
 	}
 	return _MessageType_name[_MessageType_index[i]:_MessageType_index[i+1]]
 }
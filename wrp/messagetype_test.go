@@ -11,6 +11,7 @@ func TestMessageTypeString(t *testing.T) {
 	var (
 		assert       = assert.New(t)
 		messageTypes = []MessageType{
+			AuthMessageType,
 			SimpleRequestResponseMessageType,
 			SimpleEventMessageType,
 			CreateMessageType,
@@ -40,6 +41,7 @@ func TestMessageTypeSupportsTransaction(t *testing.T) {
 	var (
 		assert                      = assert.New(t)
 		expectedSupportsTransaction = map[MessageType]bool{
+			AuthMessageType:                  false,
 			SimpleRequestResponseMessageType: true,
 			SimpleEventMessageType:           false,
 			CreateMessageType:                true,
@@ -85,7 +87,7 @@ func testStringToMessageTypeInvalid(t *testing.T, invalid string) {
 
 func TestStringToMessageType(t *testing.T) {
 	t.Run("Valid", func(t *testing.T) {
-		for v := SimpleRequestResponseMessageType; v < lastMessageType; v++ {
+		for v := AuthMessageType; v < lastMessageType; v++ {
 			testStringToMessageTypeValid(t, v)
 		}
 	})
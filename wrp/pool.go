@@ -0,0 +1,186 @@
+package wrp
+
+import (
+	"io"
+	"io/ioutil"
+)
+
+// DefaultPoolSize is the number of idle encoders or decoders retained by a pool when no size is specified
+const DefaultPoolSize = 100
+
+// noBytes is the empty input used to release references held by idle decoders
+var noBytes = []byte{}
+
+// EncoderPool is a concurrency-safe pool of Encoders for a single Format.  Encoders are created on demand,
+// and at most the configured number of idle Encoders are retained.  Encoders returned by Get are not
+// attached to any output, so callers must use Reset or ResetBytes before encoding.
+type EncoderPool struct {
+	format  Format
+	options []CodecOption
+	pool    chan Encoder
+}
+
+// NewEncoderPool creates an EncoderPool for the given format.  If poolSize is nonpositive, DefaultPoolSize is used.
+// The options are applied to each Encoder the pool creates.
+func NewEncoderPool(poolSize int, f Format, options ...CodecOption) *EncoderPool {
+	if poolSize < 1 {
+		poolSize = DefaultPoolSize
+	}
+
+	f.handle() // panics for invalid formats, so that the pool fails fast
+	return &EncoderPool{
+		format:  f,
+		options: options,
+		pool:    make(chan Encoder, poolSize),
+	}
+}
+
+// Format returns the format of the Encoders in this pool
+func (ep *EncoderPool) Format() Format {
+	return ep.format
+}
+
+// Get obtains an Encoder from this pool, creating one if no idle Encoder is available
+func (ep *EncoderPool) Get() Encoder {
+	select {
+	case encoder := <-ep.pool:
+		return encoder
+	default:
+		return NewEncoder(nil, ep.format, ep.options...)
+	}
+}
+
+// Put returns an Encoder to this pool.  The Encoder is detached from its output, so that the pool does not
+// retain references to caller buffers.  If the pool is full, the Encoder is discarded.
+func (ep *EncoderPool) Put(encoder Encoder) {
+	encoder.Reset(ioutil.Discard)
+	select {
+	case ep.pool <- encoder:
+	default:
+	}
+}
+
+// Encode uses a pooled Encoder to write a value to the given output
+func (ep *EncoderPool) Encode(output io.Writer, value interface{}) error {
+	encoder := ep.Get()
+	defer ep.Put(encoder)
+
+	encoder.Reset(output)
+	return encoder.Encode(value)
+}
+
+// EncodeBytes uses a pooled Encoder to produce the encoded form of a value
+func (ep *EncoderPool) EncodeBytes(value interface{}) ([]byte, error) {
+	var (
+		output  []byte
+		encoder = ep.Get()
+	)
+
+	defer ep.Put(encoder)
+	encoder.ResetBytes(&output)
+	err := encoder.Encode(value)
+	return output, err
+}
+
+// DecoderPool is a concurrency-safe pool of Decoders for a single Format.  Decoders are created on demand,
+// and at most the configured number of idle Decoders are retained.  Decoders returned by Get are not
+// attached to any input, so callers must use Reset or ResetBytes before decoding.
+type DecoderPool struct {
+	format  Format
+	options []CodecOption
+	pool    chan Decoder
+}
+
+// NewDecoderPool creates a DecoderPool for the given format.  If poolSize is nonpositive, DefaultPoolSize is used.
+// The options are applied to each Decoder the pool creates.
+func NewDecoderPool(poolSize int, f Format, options ...CodecOption) *DecoderPool {
+	if poolSize < 1 {
+		poolSize = DefaultPoolSize
+	}
+
+	f.handle() // panics for invalid formats, so that the pool fails fast
+	return &DecoderPool{
+		format:  f,
+		options: options,
+		pool:    make(chan Decoder, poolSize),
+	}
+}
+
+// Format returns the format of the Decoders in this pool
+func (dp *DecoderPool) Format() Format {
+	return dp.format
+}
+
+// Get obtains a Decoder from this pool, creating one if no idle Decoder is available
+func (dp *DecoderPool) Get() Decoder {
+	select {
+	case decoder := <-dp.pool:
+		return decoder
+	default:
+		return NewDecoderBytes(noBytes, dp.format, dp.options...)
+	}
+}
+
+// Put returns a Decoder to this pool.  The Decoder is detached from its input, so that the pool does not
+// retain references to caller buffers.  If the pool is full, the Decoder is discarded.
+func (dp *DecoderPool) Put(decoder Decoder) {
+	decoder.ResetBytes(noBytes)
+	select {
+	case dp.pool <- decoder:
+	default:
+	}
+}
+
+// Decode uses a pooled Decoder to read a value from the given input
+func (dp *DecoderPool) Decode(value interface{}, input io.Reader) error {
+	decoder := dp.Get()
+	defer dp.Put(decoder)
+
+	decoder.Reset(input)
+	return decoder.Decode(value)
+}
+
+// DecodeBytes uses a pooled Decoder to read a value from the given bytes
+func (dp *DecoderPool) DecodeBytes(value interface{}, input []byte) error {
+	decoder := dp.Get()
+	defer dp.Put(decoder)
+
+	decoder.ResetBytes(input)
+	return decoder.Decode(value)
+}
+
+// defaultEncoderPools and defaultDecoderPools are indexed by Format.  They are initialized as package
+// variables, rather than in init, so that other package variables can safely use MustEncode.
+var (
+	defaultEncoderPools = func() []*EncoderPool {
+		pools := make([]*EncoderPool, lastFormat)
+		for _, f := range AllFormats() {
+			pools[f] = NewEncoderPool(DefaultPoolSize, f)
+		}
+
+		return pools
+	}()
+
+	defaultDecoderPools = func() []*DecoderPool {
+		pools := make([]*DecoderPool, lastFormat)
+		for _, f := range AllFormats() {
+			pools[f] = NewDecoderPool(DefaultPoolSize, f)
+		}
+
+		return pools
+	}()
+)
+
+// DefaultEncoderPool returns the package-wide EncoderPool for the given format.  This function panics
+// if the format is not valid.
+func DefaultEncoderPool(f Format) *EncoderPool {
+	f.handle()
+	return defaultEncoderPools[f]
+}
+
+// DefaultDecoderPool returns the package-wide DecoderPool for the given format.  This function panics
+// if the format is not valid.
+func DefaultDecoderPool(f Format) *DecoderPool {
+	f.handle()
+	return defaultDecoderPools[f]
+}
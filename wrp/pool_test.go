@@ -0,0 +1,284 @@
+package wrp
+
+import (
+	"bytes"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testPoolMessage = Message{
+	Type:            SimpleRequestResponseMessageType,
+	Source:          "dns:talaria.example.com",
+	Destination:     "mac:112233445566/config",
+	TransactionUUID: "1234",
+	ContentType:     "application/json",
+	Metadata:        map[string]string{"/boot-time": "1234"},
+	PartnerIDs:      []string{"comcast"},
+	Payload:         []byte(`{"names": ["Device.DeviceInfo.SerialNumber"]}`),
+}
+
+func testEncoderPoolEncode(t *testing.T, f Format) {
+	var (
+		assert  = assert.New(t)
+		require = require.New(t)
+
+		pool = NewEncoderPool(2, f)
+	)
+
+	require.NotNil(pool)
+	assert.Equal(f, pool.Format())
+
+	for repeat := 0; repeat < 5; repeat++ {
+		var (
+			output  bytes.Buffer
+			decoded Message
+		)
+
+		require.NoError(pool.Encode(&output, &testPoolMessage))
+		require.NoError(NewDecoder(&output, f).Decode(&decoded))
+		assert.Equal(testPoolMessage, decoded)
+
+		encoded, err := pool.EncodeBytes(&testPoolMessage)
+		require.NoError(err)
+		decoded = Message{}
+		require.NoError(NewDecoderBytes(encoded, f).Decode(&decoded))
+		assert.Equal(testPoolMessage, decoded)
+	}
+}
+
+func testEncoderPoolGetPut(t *testing.T, f Format) {
+	var (
+		assert  = assert.New(t)
+		require = require.New(t)
+
+		pool     = NewEncoderPool(1, f)
+		first    = pool.Get()
+		second   = pool.Get()
+		expected []byte
+	)
+
+	require.NotNil(first)
+	require.NotNil(second)
+	assert.False(first == second)
+
+	first.ResetBytes(&expected)
+	require.NoError(first.Encode(&testPoolMessage))
+
+	pool.Put(first)
+	pool.Put(second) // the pool is full, so this encoder is discarded
+
+	reused := pool.Get()
+	assert.True(reused == first)
+
+	var actual []byte
+	reused.ResetBytes(&actual)
+	require.NoError(reused.Encode(&testPoolMessage))
+	assert.Equal(expected, actual)
+}
+
+func testDecoderPoolDecode(t *testing.T, f Format) {
+	var (
+		assert  = assert.New(t)
+		require = require.New(t)
+
+		pool    = NewDecoderPool(2, f)
+		encoded = MustEncode(&testPoolMessage, f)
+	)
+
+	require.NotNil(pool)
+	assert.Equal(f, pool.Format())
+
+	for repeat := 0; repeat < 5; repeat++ {
+		var decoded Message
+		require.NoError(pool.Decode(&decoded, bytes.NewReader(encoded)))
+		assert.Equal(testPoolMessage, decoded)
+
+		decoded = Message{}
+		require.NoError(pool.DecodeBytes(&decoded, encoded))
+		assert.Equal(testPoolMessage, decoded)
+	}
+
+	// a failed decode must not affect subsequent decodes
+	assert.Error(pool.DecodeBytes(new(Message), encoded[:len(encoded)/2]))
+
+	var decoded Message
+	require.NoError(pool.DecodeBytes(&decoded, encoded))
+	assert.Equal(testPoolMessage, decoded)
+}
+
+func testDecoderPoolGetPut(t *testing.T, f Format) {
+	var (
+		assert  = assert.New(t)
+		require = require.New(t)
+
+		pool    = NewDecoderPool(1, f)
+		first   = pool.Get()
+		second  = pool.Get()
+		encoded = MustEncode(&testPoolMessage, f)
+	)
+
+	require.NotNil(first)
+	require.NotNil(second)
+	assert.False(first == second)
+
+	pool.Put(first)
+	pool.Put(second)
+
+	reused := pool.Get()
+	assert.True(reused == first)
+
+	var decoded Message
+	reused.ResetBytes(encoded)
+	require.NoError(reused.Decode(&decoded))
+	assert.Equal(testPoolMessage, decoded)
+}
+
+func testPoolsConcurrency(t *testing.T, f Format) {
+	var (
+		assert = assert.New(t)
+
+		encoderPool = NewEncoderPool(4, f)
+		decoderPool = NewDecoderPool(4, f)
+		encoded     = MustEncode(&testPoolMessage, f)
+
+		waitGroup sync.WaitGroup
+	)
+
+	for g := 0; g < 20; g++ {
+		waitGroup.Add(1)
+		go func() {
+			defer waitGroup.Done()
+			for repeat := 0; repeat < 50; repeat++ {
+				actual, err := encoderPool.EncodeBytes(&testPoolMessage)
+				assert.NoError(err)
+				assert.Equal(encoded, actual)
+
+				var decoded Message
+				assert.NoError(decoderPool.DecodeBytes(&decoded, encoded))
+				assert.Equal(testPoolMessage, decoded)
+			}
+		}()
+	}
+
+	waitGroup.Wait()
+}
+
+func TestPools(t *testing.T) {
+	for _, f := range allFormats {
+		t.Run(f.String(), func(t *testing.T) {
+			t.Run("EncoderPool", func(t *testing.T) {
+				t.Run("Encode", func(t *testing.T) { testEncoderPoolEncode(t, f) })
+				t.Run("GetPut", func(t *testing.T) { testEncoderPoolGetPut(t, f) })
+			})
+
+			t.Run("DecoderPool", func(t *testing.T) {
+				t.Run("Decode", func(t *testing.T) { testDecoderPoolDecode(t, f) })
+				t.Run("GetPut", func(t *testing.T) { testDecoderPoolGetPut(t, f) })
+			})
+
+			t.Run("Concurrency", func(t *testing.T) { testPoolsConcurrency(t, f) })
+		})
+	}
+}
+
+func TestNewPoolsDefaultSize(t *testing.T) {
+	assert := assert.New(t)
+	assert.Equal(DefaultPoolSize, cap(NewEncoderPool(0, JSON).pool))
+	assert.Equal(DefaultPoolSize, cap(NewDecoderPool(-1, Msgpack).pool))
+}
+
+func TestNewPoolsInvalidFormat(t *testing.T) {
+	assert := assert.New(t)
+	assert.Panics(func() { NewEncoderPool(1, lastFormat) })
+	assert.Panics(func() { NewDecoderPool(1, Format(-1)) })
+	assert.Panics(func() { DefaultEncoderPool(lastFormat) })
+	assert.Panics(func() { DefaultDecoderPool(Format(-1)) })
+}
+
+func TestDefaultPools(t *testing.T) {
+	assert := assert.New(t)
+	for _, f := range allFormats {
+		assert.Equal(f, DefaultEncoderPool(f).Format())
+		assert.Equal(f, DefaultDecoderPool(f).Format())
+		assert.True(DefaultEncoderPool(f) == DefaultEncoderPool(f))
+		assert.True(DefaultDecoderPool(f) == DefaultDecoderPool(f))
+	}
+}
+
+func benchmarkEncodeUnpooled(b *testing.B, f Format) {
+	b.ReportAllocs()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			var output []byte
+			if err := NewEncoderBytes(&output, f).Encode(&testPoolMessage); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
+
+func benchmarkEncodePooled(b *testing.B, f Format, options ...CodecOption) {
+	pool := NewEncoderPool(0, f, options...)
+	b.ReportAllocs()
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			if _, err := pool.EncodeBytes(&testPoolMessage); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
+
+func benchmarkDecodeUnpooled(b *testing.B, f Format) {
+	encoded := MustEncode(&testPoolMessage, f)
+	b.ReportAllocs()
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			var decoded Message
+			if err := NewDecoderBytes(encoded, f).Decode(&decoded); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
+
+func benchmarkDecodePooled(b *testing.B, f Format, options ...CodecOption) {
+	var (
+		pool    = NewDecoderPool(0, f, options...)
+		encoded = MustEncode(&testPoolMessage, f)
+	)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			var decoded Message
+			if err := pool.DecodeBytes(&decoded, encoded); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
+
+func BenchmarkPools(b *testing.B) {
+	for _, f := range allFormats {
+		b.Run(f.String(), func(b *testing.B) {
+			b.Run("Encode", func(b *testing.B) {
+				b.Run("unpooled", func(b *testing.B) { benchmarkEncodeUnpooled(b, f) })
+				b.Run("pooled", func(b *testing.B) { benchmarkEncodePooled(b, f) })
+				b.Run("pooledUnknownFields", func(b *testing.B) { benchmarkEncodePooled(b, f, WithUnknownFields(true)) })
+			})
+
+			b.Run("Decode", func(b *testing.B) {
+				b.Run("unpooled", func(b *testing.B) { benchmarkDecodeUnpooled(b, f) })
+				b.Run("pooled", func(b *testing.B) { benchmarkDecodePooled(b, f) })
+				b.Run("pooledUnknownFields", func(b *testing.B) { benchmarkDecodePooled(b, f, WithUnknownFields(true)) })
+			})
+		})
+	}
+}
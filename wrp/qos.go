@@ -0,0 +1,69 @@
+package wrp
+
+//go:generate stringer -type=QOSLevel
+
+// QOSValue is the quality of service value of a WRP message.  Valid values range from 0 to 99, inclusive,
+// with higher values indicating more important messages.  The zero value is the lowest quality of service.
+type QOSValue int
+
+// QOSLevel is the priority class of a QOSValue.  Infrastructure such as queues uses the level, rather than
+// the raw value, to decide how messages are treated.
+type QOSLevel int
+
+const (
+	QOSLow QOSLevel = iota
+	QOSMedium
+	QOSHigh
+	QOSCritical
+)
+
+const (
+	// QOSLowValue is the smallest QOSValue in the QOSLow level
+	QOSLowValue QOSValue = 0
+
+	// QOSMediumValue is the smallest QOSValue in the QOSMedium level
+	QOSMediumValue QOSValue = 25
+
+	// QOSHighValue is the smallest QOSValue in the QOSHigh level
+	QOSHighValue QOSValue = 50
+
+	// QOSCriticalValue is the smallest QOSValue in the QOSCritical level
+	QOSCriticalValue QOSValue = 75
+
+	// QOSMaxValue is the largest valid QOSValue
+	QOSMaxValue QOSValue = 99
+)
+
+// IsValid tests if this value is in the range allowed by the WRP specification
+func (qv QOSValue) IsValid() bool {
+	return qv >= QOSLowValue && qv <= QOSMaxValue
+}
+
+// Level returns the priority class for this value.  Values below the valid range are treated as QOSLow,
+// while values above the valid range are treated as QOSCritical.
+func (qv QOSValue) Level() QOSLevel {
+	switch {
+	case qv < QOSMediumValue:
+		return QOSLow
+	case qv < QOSHighValue:
+		return QOSMedium
+	case qv < QOSCriticalValue:
+		return QOSHigh
+	default:
+		return QOSCritical
+	}
+}
+
+// Value returns the smallest QOSValue in this level.  Invalid levels return QOSLowValue.
+func (ql QOSLevel) Value() QOSValue {
+	switch ql {
+	case QOSMedium:
+		return QOSMediumValue
+	case QOSHigh:
+		return QOSHighValue
+	case QOSCritical:
+		return QOSCriticalValue
+	default:
+		return QOSLowValue
+	}
+}
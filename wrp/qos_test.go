@@ -0,0 +1,49 @@
+package wrp
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQOSValue(t *testing.T) {
+	testData := []struct {
+		value    QOSValue
+		valid    bool
+		expected QOSLevel
+	}{
+		{-1, false, QOSLow},
+		{0, true, QOSLow},
+		{24, true, QOSLow},
+		{25, true, QOSMedium},
+		{49, true, QOSMedium},
+		{50, true, QOSHigh},
+		{74, true, QOSHigh},
+		{75, true, QOSCritical},
+		{99, true, QOSCritical},
+		{100, false, QOSCritical},
+	}
+
+	for _, record := range testData {
+		t.Run(strconv.Itoa(int(record.value)), func(t *testing.T) {
+			assert := assert.New(t)
+			assert.Equal(record.valid, record.value.IsValid())
+			assert.Equal(record.expected, record.value.Level())
+		})
+	}
+}
+
+func TestQOSLevel(t *testing.T) {
+	assert := assert.New(t)
+
+	for _, level := range []QOSLevel{QOSLow, QOSMedium, QOSHigh, QOSCritical} {
+		assert.Equal(level, level.Value().Level())
+		assert.NotEmpty(level.String())
+	}
+
+	assert.Equal(QOSLowValue, QOSLevel(-1).Value())
+	assert.Equal(QOSLowValue, QOSLevel(999).Value())
+	assert.Equal("QOSHigh", QOSHigh.String())
+	assert.Equal("QOSLevel(999)", QOSLevel(999).String())
+}
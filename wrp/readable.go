@@ -0,0 +1,358 @@
+package wrp
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"mime"
+	"strings"
+	"unicode/utf8"
+)
+
+// ErrInvalidReadablePayload indicates that the payload of a readable JSON message could not be converted
+// back into bytes, e.g. a JSON payload embedded for a content type that is neither textual nor JSON
+var ErrInvalidReadablePayload = errors.New("invalid readable JSON payload")
+
+// payloadKind describes how a payload is represented in readable JSON
+type payloadKind int
+
+const (
+	binaryPayload payloadKind = iota
+	jsonPayload
+	textPayload
+)
+
+// readablePayloadKind determines the payload representation from a message's content type and content encoding.
+// Compressed payloads are always binary.
+func readablePayloadKind(contentType, contentEncoding string) payloadKind {
+	if len(contentEncoding) > 0 && contentEncoding != IdentityEncoding {
+		return binaryPayload
+	}
+
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return binaryPayload
+	}
+
+	switch {
+	case mediaType == "application/json" || strings.HasSuffix(mediaType, "+json"):
+		return jsonPayload
+	case strings.HasPrefix(mediaType, "text/") || mediaType == "application/xml" || strings.HasSuffix(mediaType, "+xml"):
+		return textPayload
+	default:
+		return binaryPayload
+	}
+}
+
+// jsonField is a single member of a JSON object, with its value left undecoded
+type jsonField struct {
+	name  string
+	value json.RawMessage
+}
+
+// jsonObject is a JSON object whose members are kept in their original order
+type jsonObject []jsonField
+
+// readObject reads the next JSON object from a json.Decoder, preserving the order and raw values of its members
+func readObject(decoder *json.Decoder) (jsonObject, error) {
+	token, err := decoder.Token()
+	if err != nil {
+		return nil, err
+	} else if token != json.Delim('{') {
+		return nil, errors.New("A WRP message must be a JSON object")
+	}
+
+	var object jsonObject
+	for decoder.More() {
+		token, err := decoder.Token()
+		if err != nil {
+			return nil, err
+		}
+
+		field := jsonField{name: token.(string)}
+		if err := decoder.Decode(&field.value); err != nil {
+			return nil, err
+		}
+
+		object = append(object, field)
+	}
+
+	// consume the closing brace
+	if _, err := decoder.Token(); err != nil {
+		return nil, err
+	}
+
+	return object, nil
+}
+
+// stringField returns the value of a member as a string, or the empty string if the member is missing or
+// is not a string
+func (o jsonObject) stringField(name string) (value string) {
+	for _, field := range o {
+		if field.name == name {
+			json.Unmarshal(field.value, &value)
+			return
+		}
+	}
+
+	return
+}
+
+// rewriteField replaces the value of the first member with the given name
+func (o jsonObject) rewriteField(name string, rewrite func(json.RawMessage) (json.RawMessage, error)) error {
+	for i := range o {
+		if o[i].name == name {
+			value, err := rewrite(o[i].value)
+			if err != nil {
+				return err
+			}
+
+			o[i].value = value
+			return nil
+		}
+	}
+
+	return nil
+}
+
+// writeTo writes this object to the given buffer.  Raw values are written verbatim.
+func (o jsonObject) writeTo(output *bytes.Buffer) error {
+	output.WriteByte('{')
+	for i, field := range o {
+		if i > 0 {
+			output.WriteByte(',')
+		}
+
+		name, err := json.Marshal(field.name)
+		if err != nil {
+			return err
+		}
+
+		output.Write(name)
+		output.WriteByte(':')
+		output.Write(field.value)
+	}
+
+	output.WriteByte('}')
+	return nil
+}
+
+// marshalString produces a JSON string without escaping HTML characters, which keeps text payloads readable
+func marshalString(value string) (json.RawMessage, error) {
+	var (
+		output  bytes.Buffer
+		encoder = json.NewEncoder(&output)
+	)
+
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(value); err != nil {
+		return nil, err
+	}
+
+	return bytes.TrimRight(output.Bytes(), "\n"), nil
+}
+
+// embeddable tests if a payload can be written verbatim into a JSON message and read back unchanged.  Top-level
+// JSON strings are excluded, since a string payload in a JSON message is always base64.
+func embeddable(payload []byte) bool {
+	if len(payload) == 0 || payload[0] == '"' || json.Unmarshal(payload, new(json.RawMessage)) != nil {
+		return false
+	}
+
+	return len(bytes.TrimSpace(payload)) == len(payload)
+}
+
+// readablePayload converts a standard, base64 payload into its readable form
+func readablePayload(kind payloadKind) func(json.RawMessage) (json.RawMessage, error) {
+	return func(value json.RawMessage) (json.RawMessage, error) {
+		var payload []byte
+		if err := json.Unmarshal(value, &payload); err != nil {
+			return nil, err
+		}
+
+		switch {
+		case kind == jsonPayload && embeddable(payload):
+			return json.RawMessage(payload), nil
+
+		case kind == textPayload && utf8.Valid(payload):
+			return marshalString(string(payload))
+
+		case kind == textPayload:
+			// text that is not valid UTF-8 is wrapped so that it can be distinguished from inline text
+			return json.Marshal(map[string][]byte{"base64": payload})
+		}
+
+		return value, nil
+	}
+}
+
+// standardPayload converts a readable payload back into its standard, base64 form
+func standardPayload(kind payloadKind) func(json.RawMessage) (json.RawMessage, error) {
+	return func(value json.RawMessage) (json.RawMessage, error) {
+		isString := len(value) > 0 && value[0] == '"'
+		switch {
+		case kind == jsonPayload && !isString:
+			return json.Marshal([]byte(value))
+
+		case kind == textPayload && isString:
+			var text string
+			if err := json.Unmarshal(value, &text); err != nil {
+				return nil, err
+			}
+
+			return json.Marshal([]byte(text))
+
+		case kind == textPayload:
+			var wrapped struct {
+				Base64 *[]byte `json:"base64"`
+			}
+
+			if err := json.Unmarshal(value, &wrapped); err != nil || wrapped.Base64 == nil {
+				return nil, ErrInvalidReadablePayload
+			}
+
+			return json.Marshal(*wrapped.Base64)
+
+		case !isString:
+			return nil, ErrInvalidReadablePayload
+		}
+
+		return value, nil
+	}
+}
+
+// convertPayload reads a single JSON message from the decoder, rewrites its payload based on the message's
+// content type, and writes the result to the output buffer
+func convertPayload(decoder *json.Decoder, output *bytes.Buffer, rewrite func(payloadKind) func(json.RawMessage) (json.RawMessage, error)) error {
+	object, err := readObject(decoder)
+	if err != nil {
+		return err
+	}
+
+	kind := readablePayloadKind(object.stringField("content_type"), object.stringField("content_encoding"))
+	if err := object.rewriteField("payload", rewrite(kind)); err != nil {
+		return err
+	}
+
+	return object.writeTo(output)
+}
+
+// ToReadableJSON converts a message in the standard JSON format into readable JSON.  In readable JSON, a payload
+// whose content type is JSON, e.g. application/json or application/vnd.example+json, is embedded as is, while
+// a payload whose content type is textual, e.g. text/plain or application/xml, is written as a JSON string.
+// All other payloads, and any payload that cannot be represented losslessly, remain base64.
+//
+// Readable JSON is an opt-in representation intended for tooling, logging, and HTTP clients that request it.
+// Use FromReadableJSON to recover the standard JSON format.
+func ToReadableJSON(standard []byte) ([]byte, error) {
+	var output bytes.Buffer
+	if err := convertPayload(json.NewDecoder(bytes.NewReader(standard)), &output, readablePayload); err != nil {
+		return nil, err
+	}
+
+	return output.Bytes(), nil
+}
+
+// FromReadableJSON converts a message in readable JSON, as produced by ToReadableJSON, back into the
+// standard JSON format.  The payload is restored to exactly the bytes that were originally encoded.
+// Since base64 payloads are left as is, a message in the standard JSON format is also accepted provided its
+// content type is not textual.
+func FromReadableJSON(readable []byte) ([]byte, error) {
+	var output bytes.Buffer
+	if err := convertPayload(json.NewDecoder(bytes.NewReader(readable)), &output, standardPayload); err != nil {
+		return nil, err
+	}
+
+	return output.Bytes(), nil
+}
+
+// readableEncoder is an Encoder that writes readable JSON
+type readableEncoder struct {
+	output io.Writer
+	bytes  *[]byte
+}
+
+func (re *readableEncoder) Encode(value interface{}) error {
+	standard, err := DefaultEncoderPool(JSON).EncodeBytes(value)
+	if err != nil {
+		return err
+	}
+
+	readable, err := ToReadableJSON(standard)
+	if err != nil {
+		return err
+	}
+
+	if re.bytes != nil {
+		*re.bytes = append(*re.bytes, readable...)
+		return nil
+	}
+
+	_, err = re.output.Write(readable)
+	return err
+}
+
+func (re *readableEncoder) Reset(output io.Writer) {
+	re.output = output
+	re.bytes = nil
+}
+
+func (re *readableEncoder) ResetBytes(output *[]byte) {
+	re.output = nil
+	re.bytes = output
+}
+
+// NewReadableEncoder produces an Encoder that writes messages as readable JSON.  See ToReadableJSON.
+func NewReadableEncoder(output io.Writer) Encoder {
+	return &readableEncoder{output: output}
+}
+
+// NewReadableEncoderBytes produces an Encoder that appends messages, as readable JSON, to a byte slice
+func NewReadableEncoderBytes(output *[]byte) Encoder {
+	return &readableEncoder{bytes: output}
+}
+
+// readableDecoder is a Decoder that reads readable JSON
+type readableDecoder struct {
+	decoder *json.Decoder
+	buffer  bytes.Buffer
+}
+
+func (rd *readableDecoder) Decode(value interface{}) error {
+	if rd.decoder == nil {
+		return io.EOF
+	}
+
+	rd.buffer.Reset()
+	if err := convertPayload(rd.decoder, &rd.buffer, standardPayload); err != nil {
+		return err
+	}
+
+	return DefaultDecoderPool(JSON).DecodeBytes(value, rd.buffer.Bytes())
+}
+
+func (rd *readableDecoder) Reset(input io.Reader) {
+	rd.decoder = nil
+	if input != nil {
+		rd.decoder = json.NewDecoder(input)
+	}
+}
+
+func (rd *readableDecoder) ResetBytes(input []byte) {
+	rd.decoder = json.NewDecoder(bytes.NewReader(input))
+}
+
+// NewReadableDecoder produces a Decoder that reads messages in readable JSON.  Several messages may be read
+// from the same input.  At the end of the input, Decode returns io.EOF.  As with NewDecoder, the input may be
+// nil, in which case Reset must be called before decoding.
+func NewReadableDecoder(input io.Reader) Decoder {
+	decoder := new(readableDecoder)
+	decoder.Reset(input)
+	return decoder
+}
+
+// NewReadableDecoderBytes produces a Decoder that reads messages in readable JSON from a byte slice
+func NewReadableDecoderBytes(input []byte) Decoder {
+	return NewReadableDecoder(bytes.NewReader(input))
+}
@@ -0,0 +1,144 @@
+package wrp
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testReadableJSONRoundTrip(t *testing.T, original Message, expectedPayload string) {
+	var (
+		assert  = assert.New(t)
+		require = require.New(t)
+
+		output  []byte
+		decoded Message
+	)
+
+	require.NoError(NewReadableEncoderBytes(&output).Encode(&original))
+
+	var fields map[string]json.RawMessage
+	require.NoError(json.Unmarshal(output, &fields))
+	assert.JSONEq(expectedPayload, string(fields["payload"]))
+
+	require.NoError(NewReadableDecoderBytes(output).Decode(&decoded))
+	assert.Equal(original, decoded)
+
+	// the readable form must convert back into the standard form
+	standard, err := FromReadableJSON(output)
+	require.NoError(err)
+	decoded = Message{}
+	require.NoError(NewDecoderBytes(standard, JSON).Decode(&decoded))
+	assert.Equal(original, decoded)
+}
+
+func TestReadableJSON(t *testing.T) {
+	testData := []struct {
+		name            string
+		contentType     string
+		contentEncoding string
+		payload         []byte
+		expectedPayload string
+	}{
+		{"JSON", "application/json", "", []byte(`{"names": ["Device.DeviceInfo.SerialNumber"]}`), `{"names": ["Device.DeviceInfo.SerialNumber"]}`},
+		{"JSONWithParameters", "application/json; charset=utf-8", "", []byte(`[1, 2, 3]`), `[1, 2, 3]`},
+		{"JSONSuffix", "application/vnd.example+json", "", []byte(`{"a":true}`), `{"a":true}`},
+		{"JSONString", "application/json", "", []byte(`"text"`), `"InRleHQi"`},
+		{"JSONWhitespace", "application/json", "", []byte("{}\n"), `"e30K"`},
+		{"InvalidJSON", "application/json", "", []byte(`{"a":`), `"eyJhIjo="`},
+		{"Text", "text/plain", "", []byte("<hello & goodbye>"), `"<hello & goodbye>"`},
+		{"XML", "application/xml", "", []byte("<a/>"), `"<a/>"`},
+		{"InvalidUTF8", "text/plain", "", []byte{0xFF, 0xFE}, `{"base64": "//4="}`},
+		{"Binary", "application/octet-stream", "", []byte{0x00, 0x01}, `"AAE="`},
+		{"NoContentType", "", "", []byte(`{}`), `"e30="`},
+		{"Compressed", "application/json", GzipEncoding, []byte(`{}`), `"e30="`},
+		{"Identity", "application/json", IdentityEncoding, []byte(`{}`), `{}`},
+	}
+
+	for _, record := range testData {
+		t.Run(record.name, func(t *testing.T) {
+			testReadableJSONRoundTrip(
+				t,
+				Message{
+					Type:            SimpleEventMessageType,
+					Source:          "mac:112233445566/service",
+					Destination:     "event:test",
+					ContentType:     record.contentType,
+					ContentEncoding: record.contentEncoding,
+					Payload:         record.payload,
+				},
+				record.expectedPayload,
+			)
+		})
+	}
+}
+
+func TestReadableJSONStream(t *testing.T) {
+	var (
+		assert  = assert.New(t)
+		require = require.New(t)
+
+		messages = []Message{
+			{Type: SimpleEventMessageType, Destination: "event:1", ContentType: "application/json", Payload: []byte(`{"a":1}`)},
+			{Type: SimpleEventMessageType, Destination: "event:2", ContentType: "text/plain", Payload: []byte("two")},
+		}
+
+		output  bytes.Buffer
+		encoder = NewReadableEncoder(&output)
+		decoder = NewReadableDecoder(nil)
+	)
+
+	for i := range messages {
+		require.NoError(encoder.Encode(&messages[i]))
+	}
+
+	decoder.Reset(&output)
+	for _, expected := range messages {
+		var actual Message
+		require.NoError(decoder.Decode(&actual))
+		assert.Equal(expected, actual)
+	}
+
+	assert.Equal(io.EOF, decoder.Decode(new(Message)))
+}
+
+func TestReadableJSONStandardInput(t *testing.T) {
+	var (
+		assert  = assert.New(t)
+		require = require.New(t)
+
+		original = Message{
+			Type:        SimpleEventMessageType,
+			Destination: "event:test",
+			ContentType: "application/json",
+			Payload:     []byte(`{"a":1}`),
+		}
+
+		decoded Message
+	)
+
+	// base64 JSON payloads in the standard format are accepted by readable decoders
+	require.NoError(NewReadableDecoderBytes(MustEncode(&original, JSON)).Decode(&decoded))
+	assert.Equal(original, decoded)
+}
+
+func TestFromReadableJSONInvalid(t *testing.T) {
+	testData := []string{
+		`[]`,
+		`{"content_type": "application/octet-stream", "payload": {"a": 1}}`,
+		`{"content_type": "text/plain", "payload": 123}`,
+		`{"content_type": "text/plain", "payload": {"other": "AAE="}}`,
+		`{"content_type": "application/json", "payload": `,
+	}
+
+	for _, record := range testData {
+		t.Run(record, func(t *testing.T) {
+			_, err := FromReadableJSON([]byte(record))
+			assert.Error(t, err)
+		})
+	}
+}
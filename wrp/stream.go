@@ -0,0 +1,230 @@
+package wrp
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"strings"
+)
+
+// DefaultMaxFrameSize is the largest encoded message a StreamReader accepts when no MaxFrameSize is set
+const DefaultMaxFrameSize = 16 * 1024 * 1024
+
+var (
+	// ErrFrameTooLarge indicates that a message in a stream exceeded the reader's maximum frame size
+	ErrFrameTooLarge = errors.New("stream frame exceeds the maximum frame size")
+
+	// streamContentTypes are the media types of framed streams for each format
+	streamContentTypes = map[Format]string{
+		Msgpack: "application/x-msgpack-stream",
+		JSON:    "application/x-ndjson",
+		CBOR:    "application/x-cbor-stream",
+	}
+)
+
+// StreamContentType returns the MIME type of a stream of messages in this format.  For JSON, this is
+// newline-delimited JSON.  For the binary formats, each message is preceded by its length.
+func (f Format) StreamContentType() string {
+	if contentType, ok := streamContentTypes[f]; ok {
+		return contentType
+	}
+
+	return "application/octet-stream"
+}
+
+// IsStreamContentType tests if the given Content-Type or Accept value denotes a stream of messages.  The format
+// of the stream is determined by FormatFromContentType, as for single messages.
+func IsStreamContentType(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(strings.Split(contentType, ",")[0])
+	if err != nil {
+		return false
+	}
+
+	for _, streamContentType := range streamContentTypes {
+		if mediaType == streamContentType {
+			return true
+		}
+	}
+
+	return false
+}
+
+// FrameError describes a message within a stream that could not be encoded or decoded.  A FrameError does not
+// affect the framing of the stream, so reading or writing may continue with the next message.
+type FrameError struct {
+	// Index is the zero-based position of the message within the stream
+	Index int
+
+	// Err is the reason the message could not be processed
+	Err error
+}
+
+func (fe *FrameError) Error() string {
+	return fmt.Sprintf("message %d: %s", fe.Index, fe.Err)
+}
+
+// StreamWriter writes a sequence of messages as a single framed stream.  Msgpack and CBOR messages are each
+// preceded by their length as a 4-byte, big-endian unsigned integer.  JSON messages are written one per line,
+// i.e. newline-delimited JSON.
+//
+// A StreamWriter is not safe for concurrent use.
+type StreamWriter struct {
+	output io.Writer
+	format Format
+	header [4]byte
+	count  int
+}
+
+// NewStreamWriter creates a StreamWriter that writes messages in the given format to an output
+func NewStreamWriter(output io.Writer, f Format) *StreamWriter {
+	return &StreamWriter{
+		output: output,
+		format: f,
+	}
+}
+
+// Write encodes a message as the next frame in the stream.  Any value accepted by an Encoder may be written.
+// If the value cannot be encoded, a *FrameError is returned and nothing is written to the stream.
+func (sw *StreamWriter) Write(v interface{}) error {
+	index := sw.count
+	sw.count++
+
+	frame, err := DefaultEncoderPool(sw.format).EncodeBytes(v)
+	if err != nil {
+		return &FrameError{Index: index, Err: err}
+	}
+
+	if sw.format == JSON {
+		_, err = sw.output.Write(append(frame, '\n'))
+		return err
+	}
+
+	binary.BigEndian.PutUint32(sw.header[:], uint32(len(frame)))
+	if _, err = sw.output.Write(sw.header[:]); err != nil {
+		return err
+	}
+
+	_, err = sw.output.Write(frame)
+	return err
+}
+
+// Count returns the number of frames this writer has attempted to write
+func (sw *StreamWriter) Count() int {
+	return sw.count
+}
+
+// StreamReader reads a framed stream of messages, as written by a StreamWriter.
+//
+// A StreamReader is not safe for concurrent use.
+type StreamReader struct {
+	// MaxFrameSize is the largest encoded message, in bytes, this reader will accept.  If unset,
+	// DefaultMaxFrameSize is used.
+	MaxFrameSize int
+
+	input  *bufio.Reader
+	format Format
+	count  int
+}
+
+// NewStreamReader creates a StreamReader that reads messages in the given format from an input
+func NewStreamReader(input io.Reader, f Format) *StreamReader {
+	return &StreamReader{
+		input:  bufio.NewReader(input),
+		format: f,
+	}
+}
+
+func (sr *StreamReader) maxFrameSize() int {
+	if sr.MaxFrameSize > 0 {
+		return sr.MaxFrameSize
+	}
+
+	return DefaultMaxFrameSize
+}
+
+// ReadFrame returns the next encoded message in the stream, without decoding it.  At the end of the stream,
+// io.EOF is returned.  A stream that ends partway through a frame results in io.ErrUnexpectedEOF.
+func (sr *StreamReader) ReadFrame() ([]byte, error) {
+	if sr.format == JSON {
+		return sr.readLine()
+	}
+
+	var header [4]byte
+	if _, err := io.ReadFull(sr.input, header[:]); err != nil {
+		return nil, err
+	}
+
+	size := binary.BigEndian.Uint32(header[:])
+	if uint64(size) > uint64(sr.maxFrameSize()) {
+		return nil, ErrFrameTooLarge
+	}
+
+	frame := make([]byte, size)
+	if _, err := io.ReadFull(sr.input, frame); err != nil {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+
+		return nil, err
+	}
+
+	sr.count++
+	return frame, nil
+}
+
+// readLine reads the next non-blank line of newline-delimited JSON.  The final line need not be terminated.
+func (sr *StreamReader) readLine() ([]byte, error) {
+	var line []byte
+	for {
+		fragment, err := sr.input.ReadSlice('\n')
+		if len(line)+len(fragment) > sr.maxFrameSize()+1 {
+			return nil, ErrFrameTooLarge
+		}
+
+		line = append(line, fragment...)
+		switch {
+		case err == bufio.ErrBufferFull:
+			continue
+
+		case err != nil && err != io.EOF:
+			return nil, err
+
+		case len(bytes.TrimSpace(line)) > 0:
+			sr.count++
+			return bytes.TrimSpace(line), nil
+
+		case err == io.EOF:
+			return nil, io.EOF
+		}
+
+		// a blank line, which is skipped
+		line = line[:0]
+	}
+}
+
+// Read decodes the next message in the stream into v.  At the end of the stream, io.EOF is returned.
+//
+// If a frame was read but could not be decoded, a *FrameError is returned.  The stream remains usable, and
+// the next call to Read decodes the next message.  Any other error means that the stream itself is damaged
+// and no further messages can be read.
+func (sr *StreamReader) Read(v interface{}) error {
+	frame, err := sr.ReadFrame()
+	if err != nil {
+		return err
+	}
+
+	if err := DefaultDecoderPool(sr.format).DecodeBytes(v, frame); err != nil {
+		return &FrameError{Index: sr.count - 1, Err: err}
+	}
+
+	return nil
+}
+
+// Count returns the number of frames read so far
+func (sr *StreamReader) Count() int {
+	return sr.count
+}
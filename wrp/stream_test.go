@@ -0,0 +1,204 @@
+package wrp
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var streamMessages = []Message{
+	{Type: SimpleEventMessageType, Source: "mac:112233445566/service", Destination: "event:1", Payload: []byte("line one\nline two")},
+	{Type: SimpleRequestResponseMessageType, Source: "dns:talaria.example.com", Destination: "mac:112233445566/config", TransactionUUID: "1234"},
+	{Type: ServiceAliveMessageType},
+}
+
+func testStreamRoundTrip(t *testing.T, f Format) {
+	var (
+		assert  = assert.New(t)
+		require = require.New(t)
+
+		output bytes.Buffer
+		writer = NewStreamWriter(&output, f)
+	)
+
+	for i := range streamMessages {
+		require.NoError(writer.Write(&streamMessages[i]))
+	}
+
+	assert.Equal(len(streamMessages), writer.Count())
+	if f == JSON {
+		assert.Equal(len(streamMessages), bytes.Count(output.Bytes(), []byte("\n")))
+	}
+
+	reader := NewStreamReader(&output, f)
+	for _, expected := range streamMessages {
+		var actual Message
+		require.NoError(reader.Read(&actual))
+		assert.Equal(expected, actual)
+	}
+
+	assert.Equal(io.EOF, reader.Read(new(Message)))
+	assert.Equal(len(streamMessages), reader.Count())
+}
+
+func testStreamFrameError(t *testing.T, f Format) {
+	var (
+		assert  = assert.New(t)
+		require = require.New(t)
+
+		output bytes.Buffer
+		writer = NewStreamWriter(&output, f)
+		bad    = []byte("not a message")
+	)
+
+	require.NoError(writer.Write(&streamMessages[0]))
+	if f == JSON {
+		output.Write(append(bad, '\n'))
+	} else {
+		var header [4]byte
+		binary.BigEndian.PutUint32(header[:], uint32(len(bad)))
+		output.Write(header[:])
+		output.Write(bad)
+	}
+
+	require.NoError(writer.Write(&streamMessages[1]))
+
+	var (
+		reader = NewStreamReader(&output, f)
+		actual Message
+	)
+
+	require.NoError(reader.Read(&actual))
+	assert.Equal(streamMessages[0], actual)
+
+	err := reader.Read(new(Message))
+	require.IsType(&FrameError{}, err)
+	assert.Equal(1, err.(*FrameError).Index)
+	assert.Contains(err.Error(), "message 1: ")
+
+	// the stream is still usable after a frame error
+	actual = Message{}
+	require.NoError(reader.Read(&actual))
+	assert.Equal(streamMessages[1], actual)
+	assert.Equal(io.EOF, reader.Read(new(Message)))
+}
+
+func testStreamTruncated(t *testing.T, f Format) {
+	var (
+		assert  = assert.New(t)
+		require = require.New(t)
+
+		output bytes.Buffer
+	)
+
+	require.NoError(NewStreamWriter(&output, f).Write(&streamMessages[0]))
+	truncated := output.Bytes()[:output.Len()-3]
+
+	err := NewStreamReader(bytes.NewReader(truncated), f).Read(new(Message))
+	if f == JSON {
+		// the final line of newline-delimited JSON need not be terminated, so the truncation is only detected when decoding
+		assert.IsType(&FrameError{}, err)
+	} else {
+		assert.Equal(io.ErrUnexpectedEOF, err)
+	}
+
+	assert.Equal(io.ErrUnexpectedEOF, NewStreamReader(bytes.NewReader([]byte{0x00, 0x01}), Msgpack).Read(new(Message)))
+}
+
+func testStreamFrameTooLarge(t *testing.T, f Format) {
+	var (
+		assert  = assert.New(t)
+		require = require.New(t)
+
+		output bytes.Buffer
+	)
+
+	require.NoError(NewStreamWriter(&output, f).Write(&Message{Type: SimpleEventMessageType, Payload: make([]byte, 8192)}))
+
+	reader := NewStreamReader(&output, f)
+	reader.MaxFrameSize = 1024
+	assert.Equal(ErrFrameTooLarge, reader.Read(new(Message)))
+}
+
+func TestStream(t *testing.T) {
+	for _, f := range AllFormats() {
+		t.Run(f.String(), func(t *testing.T) {
+			t.Run("RoundTrip", func(t *testing.T) { testStreamRoundTrip(t, f) })
+			t.Run("FrameError", func(t *testing.T) { testStreamFrameError(t, f) })
+			t.Run("Truncated", func(t *testing.T) { testStreamTruncated(t, f) })
+			t.Run("FrameTooLarge", func(t *testing.T) { testStreamFrameTooLarge(t, f) })
+		})
+	}
+}
+
+func TestStreamJSONBlankLines(t *testing.T) {
+	var (
+		assert  = assert.New(t)
+		require = require.New(t)
+
+		input  = bytes.NewBufferString("\n" + string(MustEncode(&streamMessages[0], JSON)) + "\r\n\n  \n" + string(MustEncode(&streamMessages[2], JSON)))
+		reader = NewStreamReader(input, JSON)
+		actual Message
+	)
+
+	require.NoError(reader.Read(&actual))
+	assert.Equal(streamMessages[0], actual)
+
+	actual = Message{}
+	require.NoError(reader.Read(&actual))
+	assert.Equal(streamMessages[2], actual)
+
+	assert.Equal(io.EOF, reader.Read(new(Message)))
+}
+
+func TestStreamWriterFrameError(t *testing.T) {
+	var (
+		assert = assert.New(t)
+
+		expectedErr = errors.New("expected")
+		listener    = new(mockEncodeListener)
+
+		output bytes.Buffer
+		writer = NewStreamWriter(&output, JSON)
+	)
+
+	listener.On("BeforeEncode").Return(expectedErr).Once()
+
+	err := writer.Write(listener)
+	assert.Equal(&FrameError{Index: 0, Err: expectedErr}, err)
+	assert.Zero(output.Len())
+	assert.Equal(1, writer.Count())
+	listener.AssertExpectations(t)
+}
+
+func TestStreamContentType(t *testing.T) {
+	for _, f := range AllFormats() {
+		t.Run(f.String(), func(t *testing.T) {
+			var (
+				assert  = assert.New(t)
+				require = require.New(t)
+			)
+
+			contentType := f.StreamContentType()
+			assert.True(IsStreamContentType(contentType))
+			assert.False(IsStreamContentType(f.ContentType()))
+
+			actual, err := FormatFromContentType(contentType)
+			require.NoError(err)
+			assert.Equal(f, actual)
+		})
+	}
+
+	assert.Equal(t, "application/octet-stream", Format(-1).StreamContentType())
+	for i, invalid := range []string{"", "application/json", "asdf;asdf;asdf"} {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			assert.False(t, IsStreamContentType(invalid))
+		})
+	}
+}
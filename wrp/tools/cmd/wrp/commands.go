@@ -0,0 +1,383 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/hex"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/textproto"
+	"reflect"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/Comcast/webpa-common/wrp"
+	"github.com/Comcast/webpa-common/wrp/wrphttp"
+)
+
+func runPrint(fs *flag.FlagSet, arguments []string, stdin io.Reader, stdout io.Writer) error {
+	in := wrp.Msgpack
+	fs.Var(formatValue{&in}, "in", "the format of the input messages")
+	if err := fs.Parse(arguments); err != nil {
+		return err
+	}
+
+	count := 0
+	return visitInputs(fs.Args(), stdin, func(name string, input io.Reader) error {
+		index := 0
+		return readMessages(input, in, func(m *wrp.Message) error {
+			if count > 0 {
+				fmt.Fprintln(stdout)
+			}
+
+			fmt.Fprintf(stdout, "# %s[%d]\n", name, index)
+			count++
+			index++
+			return printMessage(stdout, m)
+		})
+	})
+}
+
+func runTranscode(fs *flag.FlagSet, arguments []string, stdin io.Reader, stdout io.Writer) error {
+	var (
+		in  = wrp.Msgpack
+		out = wrp.JSON
+	)
+
+	fs.Var(formatValue{&in}, "in", "the format of the input messages")
+	fs.Var(formatValue{&out}, "out", "the format of the output messages")
+	if err := fs.Parse(arguments); err != nil {
+		return err
+	}
+
+	encoder := wrp.NewEncoder(stdout, out, wrp.WithUnknownFields(true))
+	return visitInputs(fs.Args(), stdin, func(_ string, input io.Reader) error {
+		return readMessages(input, in, func(m *wrp.Message) error {
+			if err := encoder.Encode(m); err != nil {
+				return err
+			}
+
+			if out == wrp.JSON {
+				// separate JSON messages so that the output is readable
+				fmt.Fprintln(stdout)
+			}
+
+			return nil
+		})
+	})
+}
+
+func runValidate(fs *flag.FlagSet, arguments []string, stdin io.Reader, stdout io.Writer) error {
+	in := wrp.Msgpack
+	fs.Var(formatValue{&in}, "in", "the format of the input messages")
+	if err := fs.Parse(arguments); err != nil {
+		return err
+	}
+
+	var (
+		validator = wrp.DefaultValidator()
+		invalid   = 0
+	)
+
+	err := visitInputs(fs.Args(), stdin, func(name string, input io.Reader) error {
+		index := 0
+		return readMessages(input, in, func(m *wrp.Message) error {
+			if err := validator.Validate(m); err != nil {
+				fmt.Fprintf(stdout, "%s[%d]: %s\n", name, index, err)
+				invalid++
+			} else {
+				fmt.Fprintf(stdout, "%s[%d]: ok\n", name, index)
+			}
+
+			index++
+			return nil
+		})
+	})
+
+	if err == nil && invalid > 0 {
+		err = ErrorInvalidMessage
+	}
+
+	return err
+}
+
+func runHeaders(fs *flag.FlagSet, arguments []string, stdin io.Reader, stdout io.Writer) error {
+	in := wrp.Msgpack
+	fs.Var(formatValue{&in}, "in", "the format of the input messages")
+	if err := fs.Parse(arguments); err != nil {
+		return err
+	}
+
+	count := 0
+	return visitInputs(fs.Args(), stdin, func(_ string, input io.Reader) error {
+		return readMessages(input, in, func(m *wrp.Message) error {
+			if count > 0 {
+				fmt.Fprintln(stdout)
+			}
+
+			count++
+			return writeHeaders(stdout, m)
+		})
+	})
+}
+
+func runFromHeaders(fs *flag.FlagSet, arguments []string, stdin io.Reader, stdout io.Writer) error {
+	out := wrp.Msgpack
+	fs.Var(formatValue{&out}, "out", "the format of the output messages")
+	if err := fs.Parse(arguments); err != nil {
+		return err
+	}
+
+	encoder := wrp.NewEncoder(stdout, out)
+	return visitInputs(fs.Args(), stdin, func(_ string, input io.Reader) error {
+		m, err := readHeaders(input)
+		if err != nil {
+			return err
+		}
+
+		return encoder.Encode(m)
+	})
+}
+
+// stringsValue is a repeatable flag.Value that accumulates each occurrence
+type stringsValue []string
+
+func (sv *stringsValue) String() string {
+	return strings.Join(*sv, ",")
+}
+
+func (sv *stringsValue) Set(value string) error {
+	*sv = append(*sv, value)
+	return nil
+}
+
+func runBuild(fs *flag.FlagSet, arguments []string, stdin io.Reader, stdout io.Writer) error {
+	var (
+		out         = wrp.Msgpack
+		m           wrp.Message
+		messageType string
+		status      int64
+		rdr         int64
+		qos         int
+		payload     string
+		payloadFile string
+		metadata    stringsValue
+		partnerIDs  stringsValue
+		headers     stringsValue
+	)
+
+	fs.Var(formatValue{&out}, "out", "the format of the output message")
+	fs.StringVar(&messageType, "type", "SimpleEvent", "the message type, as a name or an integer")
+	fs.StringVar(&m.Source, "source", "", "the source locator")
+	fs.StringVar(&m.Destination, "dest", "", "the destination locator")
+	fs.StringVar(&m.TransactionUUID, "transaction-uuid", "", "the transaction identifier")
+	fs.StringVar(&m.ContentType, "content-type", "", "the MIME type of the payload")
+	fs.StringVar(&m.Accept, "accept", "", "the MIME type accepted in a response")
+	fs.StringVar(&m.Path, "path", "", "the path of a CRUD message")
+	fs.StringVar(&m.ServiceName, "service-name", "", "the service name of a ServiceRegistration message")
+	fs.StringVar(&m.URL, "url", "", "the URL of a ServiceRegistration message")
+	fs.StringVar(&m.SessionID, "session-id", "", "the session identifier")
+	fs.StringVar(&m.RequestID, "request-id", "", "the request identifier")
+	fs.Int64Var(&status, "status", 0, "the status (only set if supplied)")
+	fs.Int64Var(&rdr, "rdr", 0, "the request delivery response (only set if supplied)")
+	fs.IntVar(&qos, "qos", 0, "the quality of service value")
+	fs.StringVar(&payload, "payload", "", "the payload text")
+	fs.StringVar(&payloadFile, "payload-file", "", "a file containing the payload, or - for stdin")
+	fs.Var(&metadata, "metadata", "a metadata entry as key=value (repeatable)")
+	fs.Var(&partnerIDs, "partner-id", "a partner identifier (repeatable)")
+	fs.Var(&headers, "header", "a WRP header (repeatable)")
+	if err := fs.Parse(arguments); err != nil {
+		return err
+	}
+
+	var err error
+	if m.Type, err = wrp.StringToMessageType(messageType); err != nil {
+		return err
+	}
+
+	fs.Visit(func(f *flag.Flag) {
+		switch f.Name {
+		case "status":
+			m.SetStatus(status)
+		case "rdr":
+			m.SetRequestDeliveryResponse(rdr)
+		}
+	})
+
+	m.QualityOfService = wrp.QOSValue(qos)
+	m.PartnerIDs = partnerIDs
+	m.Headers = headers
+
+	for _, entry := range metadata {
+		equals := strings.IndexByte(entry, '=')
+		if equals < 1 {
+			return fmt.Errorf("Invalid metadata entry: %s", entry)
+		}
+
+		if m.Metadata == nil {
+			m.Metadata = make(map[string]string, len(metadata))
+		}
+
+		m.Metadata[entry[:equals]] = entry[equals+1:]
+	}
+
+	switch {
+	case len(payloadFile) > 0 && len(payload) > 0:
+		return fmt.Errorf("Only one of -payload and -payload-file may be supplied")
+	case payloadFile == "-":
+		m.Payload, err = ioutil.ReadAll(stdin)
+	case len(payloadFile) > 0:
+		m.Payload, err = ioutil.ReadFile(payloadFile)
+	case len(payload) > 0:
+		m.Payload = []byte(payload)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	return wrp.NewEncoder(stdout, out).Encode(&m)
+}
+
+// printMessage writes a human-readable form of a message, one field per line in the order the fields are
+// declared on wrp.Message.  Unset fields are omitted, unknown fields follow the known fields, and the payload
+// is written last.
+func printMessage(output io.Writer, m *wrp.Message) error {
+	var (
+		tw = tabwriter.NewWriter(output, 0, 8, 2, ' ', 0)
+		v  = reflect.ValueOf(m).Elem()
+		t  = v.Type()
+	)
+
+	for i := 0; i < t.NumField(); i++ {
+		var (
+			name  = strings.Split(t.Field(i).Tag.Get("wrp"), ",")[0]
+			field = v.Field(i)
+		)
+
+		switch {
+		case name == "msg_type":
+			fmt.Fprintf(tw, "%s:\t%s (%d)\n", name, m.Type, m.Type)
+		case name == "-" || name == "payload" || isUnset(field):
+		case field.Kind() == reflect.Map:
+			fmt.Fprintf(tw, "%s:\t%s\n", name, formatMetadata(m.Metadata))
+		case field.Kind() == reflect.Ptr:
+			fmt.Fprintf(tw, "%s:\t%v\n", name, field.Elem().Interface())
+		default:
+			fmt.Fprintf(tw, "%s:\t%v\n", name, field.Interface())
+		}
+	}
+
+	unknownNames := make([]string, 0, len(m.UnknownFields))
+	for name := range m.UnknownFields {
+		unknownNames = append(unknownNames, name)
+	}
+
+	sort.Strings(unknownNames)
+	for _, name := range unknownNames {
+		fmt.Fprintf(tw, "%s:\t%v (unknown)\n", name, m.UnknownFields[name])
+	}
+
+	if len(m.Payload) > 0 {
+		fmt.Fprintf(tw, "payload:\t(%d bytes)\n", len(m.Payload))
+	}
+
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	if len(m.Payload) == 0 {
+		return nil
+	}
+
+	if isText(m.Payload) && len(m.ContentEncoding) == 0 {
+		_, err := fmt.Fprintf(output, "%s\n", bytes.TrimRight(m.Payload, "\r\n"))
+		return err
+	}
+
+	_, err := io.WriteString(output, hex.Dump(m.Payload))
+	return err
+}
+
+// isUnset tests if a field of a wrp.Message would be omitted when encoded
+func isUnset(field reflect.Value) bool {
+	switch field.Kind() {
+	case reflect.Ptr:
+		return field.IsNil()
+	case reflect.Slice, reflect.Map, reflect.String:
+		return field.Len() == 0
+	case reflect.Int, reflect.Int64:
+		return field.Int() == 0
+	default:
+		return false
+	}
+}
+
+// formatMetadata produces a stable, sorted representation of metadata
+func formatMetadata(metadata map[string]string) string {
+	entries := make([]string, 0, len(metadata))
+	for key, value := range metadata {
+		entries = append(entries, key+"="+value)
+	}
+
+	sort.Strings(entries)
+	return strings.Join(entries, ", ")
+}
+
+// isText tests if a payload can be written to a terminal as is
+func isText(payload []byte) bool {
+	if !utf8.Valid(payload) {
+		return false
+	}
+
+	for _, r := range string(payload) {
+		if unicode.IsControl(r) && r != '\n' && r != '\r' && r != '\t' {
+			return false
+		}
+	}
+
+	return true
+}
+
+// writeHeaders writes a message as an HTTP header block followed by a blank line and the payload, which is
+// the form read by readHeaders
+func writeHeaders(output io.Writer, m *wrp.Message) error {
+	var (
+		header = make(http.Header)
+		body   bytes.Buffer
+	)
+
+	wrphttp.AddMessageHeaders(header, m)
+	if _, err := wrphttp.WritePayload(header, &body, m); err != nil {
+		return err
+	}
+
+	if err := header.Write(output); err != nil {
+		return err
+	}
+
+	if _, err := io.WriteString(output, "\r\n"); err != nil {
+		return err
+	}
+
+	_, err := body.WriteTo(output)
+	return err
+}
+
+// readHeaders reads an HTTP header block followed by a blank line from the input.  The remainder of the input,
+// if any, is used as the payload.
+func readHeaders(input io.Reader) (*wrp.Message, error) {
+	reader := textproto.NewReader(bufio.NewReader(input))
+	header, err := reader.ReadMIMEHeader()
+	if err != nil && err != io.EOF {
+		return nil, err
+	}
+
+	return wrphttp.NewMessageFromHeaders(http.Header(header), reader.R)
+}
@@ -0,0 +1,245 @@
+// Command wrp inspects, transcodes, validates, and builds WRP messages.
+//
+// Messages are read from the files named on the command line, or from stdin if no files are named.  The file
+// name "-" also refers to stdin.  Each input may contain any number of concatenated messages.
+//
+// Examples:
+//
+//	wrp print capture.msgpack
+//	wrp transcode -in msgpack -out json < capture.msgpack
+//	wrp validate -in json messages.json
+//	wrp headers capture.msgpack
+//	wrp build -type SimpleEvent -source mac:112233445566 -dest event:device-status -payload '{}' | wrp print
+package main
+
+import (
+	"bufio"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/Comcast/webpa-common/wrp"
+)
+
+var (
+	ErrorUnknownCommand = errors.New("Unknown command")
+	ErrorInvalidFormat  = errors.New("Invalid WRP format")
+	ErrorInvalidMessage = errors.New("One or more messages were not valid")
+)
+
+// command is a single wrp subcommand
+type command struct {
+	usage       string
+	description string
+	run         func(fs *flag.FlagSet, arguments []string, stdin io.Reader, stdout io.Writer) error
+}
+
+var commands = map[string]command{
+	"print": {
+		usage:       "print [-in format] [file...]",
+		description: "pretty-prints each message",
+		run:         runPrint,
+	},
+	"transcode": {
+		usage:       "transcode [-in format] [-out format] [file...]",
+		description: "writes each message to stdout in another format",
+		run:         runTranscode,
+	},
+	"validate": {
+		usage:       "validate [-in format] [file...]",
+		description: "checks each message against the WRP specification",
+		run:         runValidate,
+	},
+	"headers": {
+		usage:       "headers [-in format] [file...]",
+		description: "writes each message in its HTTP header representation, followed by its payload",
+		run:         runHeaders,
+	},
+	"fromheaders": {
+		usage:       "fromheaders [-out format] [file...]",
+		description: "reads an HTTP header block and payload from each input and writes the WRP message",
+		run:         runFromHeaders,
+	},
+	"build": {
+		usage:       "build [-out format] [field flags...]",
+		description: "builds a single message from command line flags",
+		run:         runBuild,
+	},
+}
+
+// formatValue is a flag.Value that parses a wrp.Format by name, e.g. "json", or by MIME type
+type formatValue struct {
+	format *wrp.Format
+}
+
+func (fv formatValue) String() string {
+	if fv.format == nil {
+		return ""
+	}
+
+	return strings.ToLower(fv.format.String())
+}
+
+func (fv formatValue) Set(value string) error {
+	f, err := parseFormat(value)
+	if err != nil {
+		return err
+	}
+
+	*fv.format = f
+	return nil
+}
+
+// parseFormat converts a case-insensitive format name or a MIME type into a wrp.Format
+func parseFormat(value string) (wrp.Format, error) {
+	for _, f := range wrp.AllFormats() {
+		if strings.EqualFold(value, f.String()) {
+			return f, nil
+		}
+	}
+
+	if f, err := wrp.FormatFromContentType(value); err == nil {
+		return f, nil
+	}
+
+	return wrp.Format(-1), ErrorInvalidFormat
+}
+
+// newFlagSet creates the flag.FlagSet for a command, with errors reported to the caller rather than
+// causing the process to exit
+func newFlagSet(name string, c command) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(os.Stderr)
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: wrp %s\n", c.usage)
+		fs.PrintDefaults()
+	}
+
+	return fs
+}
+
+// visitInputs invokes the visitor for each named input.  If no names are supplied, stdin is visited.
+func visitInputs(names []string, stdin io.Reader, visitor func(name string, input io.Reader) error) error {
+	if len(names) == 0 {
+		return visitor("-", stdin)
+	}
+
+	for _, name := range names {
+		if name == "-" {
+			if err := visitor(name, stdin); err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		file, err := os.Open(name)
+		if err != nil {
+			return err
+		}
+
+		err = visitor(name, file)
+		file.Close()
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// readMessages decodes each message in the input, stopping at the end of the input.  Unknown fields are retained,
+// so that they can be printed and transcoded.
+func readMessages(input io.Reader, f wrp.Format, visitor func(*wrp.Message) error) error {
+	var (
+		reader  = bufio.NewReader(input)
+		decoder = wrp.NewDecoder(reader, f, wrp.WithUnknownFields(true))
+	)
+
+	for {
+		if f == wrp.JSON {
+			// JSON messages are typically separated by whitespace, which would otherwise look like another message
+			if err := skipSpace(reader); err != nil && err != io.EOF {
+				return err
+			}
+		}
+
+		if _, err := reader.Peek(1); err == io.EOF {
+			return nil
+		} else if err != nil {
+			return err
+		}
+
+		message := new(wrp.Message)
+		if err := decoder.Decode(message); err != nil {
+			return err
+		}
+
+		if err := visitor(message); err != nil {
+			return err
+		}
+	}
+}
+
+// skipSpace discards any whitespace at the front of the reader
+func skipSpace(reader *bufio.Reader) error {
+	for {
+		r, _, err := reader.ReadRune()
+		if err != nil {
+			return err
+		}
+
+		if !unicode.IsSpace(r) {
+			return reader.UnreadRune()
+		}
+	}
+}
+
+func usage() {
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+	fmt.Fprintln(os.Stderr, "Usage: wrp <command> [arguments]")
+	fmt.Fprintln(os.Stderr)
+	fmt.Fprintln(os.Stderr, "Commands:")
+	for _, name := range names {
+		fmt.Fprintf(os.Stderr, "  %-12s %s\n", name, commands[name].description)
+	}
+
+	fmt.Fprintln(os.Stderr)
+	fmt.Fprintln(os.Stderr, "Formats may be given by name (msgpack, json, cbor) or by MIME type.")
+	fmt.Fprintln(os.Stderr, "Use \"wrp <command> -h\" for the arguments of a command.")
+}
+
+func run(arguments []string, stdin io.Reader, stdout io.Writer) error {
+	if len(arguments) == 0 {
+		usage()
+		return ErrorUnknownCommand
+	}
+
+	c, ok := commands[arguments[0]]
+	if !ok {
+		usage()
+		return ErrorUnknownCommand
+	}
+
+	return c.run(newFlagSet(arguments[0], c), arguments[1:], stdin, stdout)
+}
+
+func main() {
+	err := run(os.Args[1:], os.Stdin, os.Stdout)
+	if err == flag.ErrHelp {
+		return
+	} else if err != nil {
+		fmt.Fprintf(os.Stderr, "%s\n", err)
+		os.Exit(1)
+	}
+}
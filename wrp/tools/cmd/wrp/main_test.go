@@ -0,0 +1,194 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/Comcast/webpa-common/wrp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testMessage = wrp.Message{
+	Type:            wrp.SimpleRequestResponseMessageType,
+	Source:          "dns:talaria.example.com",
+	Destination:     "mac:112233445566/config",
+	TransactionUUID: "1234",
+	ContentType:     "application/json",
+	Metadata:        map[string]string{"/boot-time": "1234", "/trust": "1000"},
+	PartnerIDs:      []string{"comcast"},
+	Payload:         []byte(`{"names": ["Device.DeviceInfo.SerialNumber"]}`),
+}
+
+func testParseFormat(t *testing.T) {
+	assert := assert.New(t)
+
+	for _, f := range wrp.AllFormats() {
+		actual, err := parseFormat(f.String())
+		assert.Equal(f, actual)
+		assert.NoError(err)
+
+		actual, err = parseFormat(f.ContentType())
+		assert.Equal(f, actual)
+		assert.NoError(err)
+	}
+
+	actual, err := parseFormat("JSON")
+	assert.Equal(wrp.JSON, actual)
+	assert.NoError(err)
+
+	_, err = parseFormat("nosuch")
+	assert.Equal(ErrorInvalidFormat, err)
+}
+
+func testRunUnknownCommand(t *testing.T) {
+	assert := assert.New(t)
+	assert.Equal(ErrorUnknownCommand, run(nil, new(bytes.Buffer), new(bytes.Buffer)))
+	assert.Equal(ErrorUnknownCommand, run([]string{"nosuch"}, new(bytes.Buffer), new(bytes.Buffer)))
+}
+
+func testRunPrint(t *testing.T) {
+	var (
+		assert  = assert.New(t)
+		require = require.New(t)
+
+		stdin  = bytes.NewBuffer(wrp.MustEncode(&testMessage, wrp.Msgpack))
+		stdout bytes.Buffer
+	)
+
+	require.NoError(wrp.NewEncoder(stdin, wrp.Msgpack, wrp.WithUnknownFields(true)).Encode(
+		&wrp.Message{
+			Type:          wrp.SimpleEventMessageType,
+			Payload:       []byte{0x00, 0xFF},
+			UnknownFields: map[string]interface{}{"future_field": "value"},
+		},
+	))
+
+	require.NoError(run([]string{"print"}, stdin, &stdout))
+
+	output := stdout.String()
+	assert.Contains(output, "# -[0]\n")
+	assert.Contains(output, "# -[1]\n")
+	assert.Regexp(`msg_type:\s+SimpleRequestResponseMessageType \(3\)`, output)
+	assert.Regexp(`dest:\s+mac:112233445566/config`, output)
+	assert.Regexp(`metadata:\s+/boot-time=1234, /trust=1000`, output)
+	assert.Contains(output, `{"names": ["Device.DeviceInfo.SerialNumber"]}`)
+	assert.NotContains(output, "session_id")
+	assert.Regexp(`future_field:\s+value \(unknown\)`, output)
+
+	// binary payloads are hex dumped
+	assert.Contains(output, "00000000  00 ff")
+}
+
+func testRunTranscode(t *testing.T) {
+	for _, in := range wrp.AllFormats() {
+		for _, out := range wrp.AllFormats() {
+			t.Run(in.String()+"/"+out.String(), func(t *testing.T) {
+				var (
+					assert  = assert.New(t)
+					require = require.New(t)
+
+					stdin  = bytes.NewBuffer(wrp.MustEncode(&testMessage, in))
+					stdout bytes.Buffer
+				)
+
+				stdin.Write(wrp.MustEncode(&testMessage, in))
+				require.NoError(run([]string{"transcode", "-in", in.String(), "-out", out.ContentType()}, stdin, &stdout))
+
+				var messages []wrp.Message
+				require.NoError(readMessages(&stdout, out, func(m *wrp.Message) error {
+					messages = append(messages, *m)
+					return nil
+				}))
+
+				assert.Equal([]wrp.Message{testMessage, testMessage}, messages)
+			})
+		}
+	}
+}
+
+func testRunValidate(t *testing.T) {
+	var (
+		assert = assert.New(t)
+
+		stdin  = bytes.NewBuffer(wrp.MustEncode(&testMessage, wrp.JSON))
+		stdout bytes.Buffer
+	)
+
+	stdin.Write(wrp.MustEncode(&wrp.Message{Type: wrp.SimpleEventMessageType}, wrp.JSON))
+	assert.Equal(ErrorInvalidMessage, run([]string{"validate", "-in", "json"}, stdin, &stdout))
+	assert.Contains(stdout.String(), "-[0]: ok\n")
+	assert.Contains(stdout.String(), "-[1]: ")
+	assert.NotContains(stdout.String(), "-[1]: ok")
+}
+
+func testRunHeaders(t *testing.T) {
+	var (
+		assert  = assert.New(t)
+		require = require.New(t)
+
+		headers bytes.Buffer
+		stdout  bytes.Buffer
+	)
+
+	require.NoError(run([]string{"headers"}, bytes.NewBuffer(wrp.MustEncode(&testMessage, wrp.Msgpack)), &headers))
+	assert.Contains(headers.String(), "X-Xmidt-Message-Type: SimpleRequestResponse\r\n")
+	assert.Contains(headers.String(), "\r\n\r\n"+string(testMessage.Payload))
+
+	require.NoError(run([]string{"fromheaders", "-out", "cbor"}, &headers, &stdout))
+
+	var actual wrp.Message
+	require.NoError(wrp.NewDecoder(&stdout, wrp.CBOR).Decode(&actual))
+	assert.Equal(testMessage, actual)
+}
+
+func testRunBuild(t *testing.T) {
+	var (
+		assert  = assert.New(t)
+		require = require.New(t)
+
+		stdout bytes.Buffer
+	)
+
+	require.NoError(run(
+		[]string{
+			"build",
+			"-out", "json",
+			"-type", "SimpleRequestResponse",
+			"-source", testMessage.Source,
+			"-dest", testMessage.Destination,
+			"-transaction-uuid", testMessage.TransactionUUID,
+			"-content-type", testMessage.ContentType,
+			"-metadata", "/boot-time=1234",
+			"-metadata", "/trust=1000",
+			"-partner-id", "comcast",
+			"-payload-file", "-",
+		},
+		bytes.NewBuffer(testMessage.Payload),
+		&stdout,
+	))
+
+	var actual wrp.Message
+	require.NoError(wrp.NewDecoder(&stdout, wrp.JSON).Decode(&actual))
+	assert.Equal(testMessage, actual)
+
+	stdout.Reset()
+	require.NoError(run([]string{"build", "-type", "2", "-status", "0"}, new(bytes.Buffer), &stdout))
+	actual = wrp.Message{}
+	require.NoError(wrp.NewDecoder(&stdout, wrp.Msgpack).Decode(&actual))
+	assert.Equal(*(&wrp.Message{Type: wrp.AuthMessageType}).SetStatus(0), actual)
+
+	assert.Error(run([]string{"build", "-type", "nosuch"}, new(bytes.Buffer), new(bytes.Buffer)))
+	assert.Error(run([]string{"build", "-metadata", "novalue"}, new(bytes.Buffer), new(bytes.Buffer)))
+	assert.Error(run([]string{"build", "-payload", "a", "-payload-file", "-"}, new(bytes.Buffer), new(bytes.Buffer)))
+}
+
+func TestRun(t *testing.T) {
+	t.Run("ParseFormat", testParseFormat)
+	t.Run("UnknownCommand", testRunUnknownCommand)
+	t.Run("Print", testRunPrint)
+	t.Run("Transcode", testRunTranscode)
+	t.Run("Validate", testRunValidate)
+	t.Run("Headers", testRunHeaders)
+	t.Run("Build", testRunBuild)
+}
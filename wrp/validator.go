@@ -0,0 +1,360 @@
+package wrp
+
+import (
+	"errors"
+	"fmt"
+	"mime"
+	"strings"
+	"unicode/utf8"
+)
+
+var (
+	// ErrMissingField indicates that a required field was not set
+	ErrMissingField = errors.New("required field is missing")
+
+	// ErrInvalidLocator indicates that a field did not have the syntax of a WRP locator, e.g. mac:112233445566/service
+	ErrInvalidLocator = errors.New("invalid locator")
+
+	// ErrInvalidUTF8 indicates that a field contained text that was not valid UTF-8
+	ErrInvalidUTF8 = errors.New("invalid UTF-8")
+
+	// ErrTooLarge indicates that a field exceeded its configured size limit
+	ErrTooLarge = errors.New("size limit exceeded")
+
+	// ErrContentTypeNotAllowed indicates that a message's content type was not one of the allowed types
+	ErrContentTypeNotAllowed = errors.New("content type not allowed")
+
+	// ErrInvalidMessageType indicates that a message's type was not recognized
+	ErrInvalidMessageType = errors.New("invalid message type")
+)
+
+// FieldError describes a validation failure for a single field of a message
+type FieldError struct {
+	// Field is the WRP name of the field, e.g. "dest" or "transaction_uuid"
+	Field string
+
+	// Err is the reason for the failure.  For the built-in rules, this will be one of the Err* values in this package.
+	Err error
+}
+
+func (fe *FieldError) Error() string {
+	return fmt.Sprintf("%s: %s", fe.Field, fe.Err)
+}
+
+// ValidationError is returned when a message fails validation.  It contains every failure that was detected,
+// so that clients can report all the problems with a message at once.
+type ValidationError struct {
+	// Type is the type of the message that failed validation
+	Type MessageType
+
+	// Errors holds each validation failure.  Failures detected by the built-in rules are *FieldError instances.
+	Errors []error
+}
+
+func (ve *ValidationError) Error() string {
+	messages := make([]string, len(ve.Errors))
+	for i, err := range ve.Errors {
+		messages[i] = err.Error()
+	}
+
+	return fmt.Sprintf("Invalid %s message: %s", ve.Type, strings.Join(messages, "; "))
+}
+
+// Validator checks that a Message is well-formed
+type Validator interface {
+	// Validate returns a non-nil error if the message is not well-formed
+	Validate(*Message) error
+}
+
+// ValidatorFunc is a function type that implements Validator.  Custom rules are most easily written as ValidatorFuncs.
+type ValidatorFunc func(*Message) error
+
+func (vf ValidatorFunc) Validate(m *Message) error {
+	return vf(m)
+}
+
+// Validators is a composite Validator that applies each of its rules in order.  Every rule is applied, and
+// all failures are returned as a single *ValidationError.  Failures from nested ValidationErrors are flattened.
+type Validators []Validator
+
+func (vs Validators) Validate(m *Message) error {
+	var errs []error
+	for _, v := range vs {
+		err := v.Validate(m)
+		if err == nil {
+			continue
+		}
+
+		if ve, ok := err.(*ValidationError); ok {
+			errs = append(errs, ve.Errors...)
+		} else {
+			errs = append(errs, err)
+		}
+	}
+
+	if len(errs) > 0 {
+		return &ValidationError{Type: m.Type, Errors: errs}
+	}
+
+	return nil
+}
+
+// TypeValidators is a Validator that applies rules based on the type of message.  Messages whose type does not
+// appear in this map are rejected with ErrInvalidMessageType.
+type TypeValidators map[MessageType]Validator
+
+func (tv TypeValidators) Validate(m *Message) error {
+	v, ok := tv[m.Type]
+	if !ok {
+		return &ValidationError{
+			Type:   m.Type,
+			Errors: []error{&FieldError{Field: "msg_type", Err: ErrInvalidMessageType}},
+		}
+	}
+
+	if v == nil {
+		return nil
+	}
+
+	return v.Validate(m)
+}
+
+// isSet maps each WRP field name to a predicate that tests whether that field has a value
+var isSet = map[string]func(*Message) bool{
+	"source":           func(m *Message) bool { return len(m.Source) > 0 },
+	"dest":             func(m *Message) bool { return len(m.Destination) > 0 },
+	"transaction_uuid": func(m *Message) bool { return len(m.TransactionUUID) > 0 },
+	"content_type":     func(m *Message) bool { return len(m.ContentType) > 0 },
+	"accept":           func(m *Message) bool { return len(m.Accept) > 0 },
+	"status":           func(m *Message) bool { return m.Status != nil },
+	"rdr":              func(m *Message) bool { return m.RequestDeliveryResponse != nil },
+	"headers":          func(m *Message) bool { return len(m.Headers) > 0 },
+	"metadata":         func(m *Message) bool { return len(m.Metadata) > 0 },
+	"spans":            func(m *Message) bool { return len(m.Spans) > 0 },
+	"include_spans":    func(m *Message) bool { return m.IncludeSpans != nil },
+	"path":             func(m *Message) bool { return len(m.Path) > 0 },
+	"payload":          func(m *Message) bool { return len(m.Payload) > 0 },
+	"service_name":     func(m *Message) bool { return len(m.ServiceName) > 0 },
+	"url":              func(m *Message) bool { return len(m.URL) > 0 },
+	"partner_ids":      func(m *Message) bool { return len(m.PartnerIDs) > 0 },
+	"content_encoding": func(m *Message) bool { return len(m.ContentEncoding) > 0 },
+	"session_id":       func(m *Message) bool { return len(m.SessionID) > 0 },
+	"qos":              func(m *Message) bool { return m.QualityOfService != 0 },
+	"request_id":       func(m *Message) bool { return len(m.RequestID) > 0 },
+}
+
+// stringFields maps the WRP name of each simple string field to an accessor for that field
+var stringFields = map[string]func(*Message) string{
+	"source":           func(m *Message) string { return m.Source },
+	"dest":             func(m *Message) string { return m.Destination },
+	"transaction_uuid": func(m *Message) string { return m.TransactionUUID },
+	"content_type":     func(m *Message) string { return m.ContentType },
+	"accept":           func(m *Message) string { return m.Accept },
+	"path":             func(m *Message) string { return m.Path },
+	"service_name":     func(m *Message) string { return m.ServiceName },
+	"url":              func(m *Message) string { return m.URL },
+	"content_encoding": func(m *Message) string { return m.ContentEncoding },
+	"session_id":       func(m *Message) string { return m.SessionID },
+	"request_id":       func(m *Message) string { return m.RequestID },
+}
+
+func mustStringField(field string) func(*Message) string {
+	f, ok := stringFields[field]
+	if !ok {
+		panic(fmt.Errorf("Not a WRP string field: %s", field))
+	}
+
+	return f
+}
+
+// Required produces a rule that requires each of the given fields to be set.  Fields are named
+// using their WRP names, e.g. "dest".  This function panics if any field name is not recognized.
+func Required(fields ...string) Validator {
+	predicates := make([]func(*Message) bool, len(fields))
+	for i, field := range fields {
+		p, ok := isSet[field]
+		if !ok {
+			panic(fmt.Errorf("Not a WRP field: %s", field))
+		}
+
+		predicates[i] = p
+	}
+
+	return ValidatorFunc(func(m *Message) error {
+		var errs []error
+		for i, p := range predicates {
+			if !p(m) {
+				errs = append(errs, &FieldError{Field: fields[i], Err: ErrMissingField})
+			}
+		}
+
+		if len(errs) > 0 {
+			return &ValidationError{Type: m.Type, Errors: errs}
+		}
+
+		return nil
+	})
+}
+
+// Locators produces a rule that requires each of the given string fields, if set, to be parseable by ParseLocator.
+// Use Required to reject unset fields.  This function panics if any field name is not a WRP string field.
+func Locators(fields ...string) Validator {
+	return stringRule(fields, func(value string) error {
+		if len(value) > 0 {
+			_, err := ParseLocator(value)
+			return err
+		}
+
+		return nil
+	})
+}
+
+// MaxLength produces a rule that limits the length, in bytes, of each of the given string fields.
+// This function panics if any field name is not a WRP string field.
+func MaxLength(n int, fields ...string) Validator {
+	return stringRule(fields, func(value string) error {
+		if len(value) > n {
+			return ErrTooLarge
+		}
+
+		return nil
+	})
+}
+
+// stringRule produces a Validator that applies a check to each of a set of string fields
+func stringRule(fields []string, check func(string) error) Validator {
+	accessors := make([]func(*Message) string, len(fields))
+	for i, field := range fields {
+		accessors[i] = mustStringField(field)
+	}
+
+	return ValidatorFunc(func(m *Message) error {
+		var errs []error
+		for i, a := range accessors {
+			if err := check(a(m)); err != nil {
+				errs = append(errs, &FieldError{Field: fields[i], Err: err})
+			}
+		}
+
+		if len(errs) > 0 {
+			return &ValidationError{Type: m.Type, Errors: errs}
+		}
+
+		return nil
+	})
+}
+
+// UTF8 produces a rule that requires every textual field of a message, including headers, metadata,
+// and partner ids, to be valid UTF-8.  The payload is not examined.
+func UTF8() Validator {
+	return ValidatorFunc(func(m *Message) error {
+		var errs []error
+		for _, field := range []string{"source", "dest", "transaction_uuid", "content_type", "accept", "path", "service_name", "url", "content_encoding", "session_id", "request_id"} {
+			if !utf8.ValidString(stringFields[field](m)) {
+				errs = append(errs, &FieldError{Field: field, Err: ErrInvalidUTF8})
+			}
+		}
+
+		if !validStrings(m.Headers) {
+			errs = append(errs, &FieldError{Field: "headers", Err: ErrInvalidUTF8})
+		}
+
+		for k, v := range m.Metadata {
+			if !utf8.ValidString(k) || !utf8.ValidString(v) {
+				errs = append(errs, &FieldError{Field: "metadata", Err: ErrInvalidUTF8})
+				break
+			}
+		}
+
+		for _, span := range m.Spans {
+			if !validStrings(span) {
+				errs = append(errs, &FieldError{Field: "spans", Err: ErrInvalidUTF8})
+				break
+			}
+		}
+
+		if !validStrings(m.PartnerIDs) {
+			errs = append(errs, &FieldError{Field: "partner_ids", Err: ErrInvalidUTF8})
+		}
+
+		if len(errs) > 0 {
+			return &ValidationError{Type: m.Type, Errors: errs}
+		}
+
+		return nil
+	})
+}
+
+func validStrings(values []string) bool {
+	for _, v := range values {
+		if !utf8.ValidString(v) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// MaxPayloadSize produces a rule that limits the size of a message's payload, in bytes
+func MaxPayloadSize(n int) Validator {
+	return ValidatorFunc(func(m *Message) error {
+		if len(m.Payload) > n {
+			return &FieldError{Field: "payload", Err: ErrTooLarge}
+		}
+
+		return nil
+	})
+}
+
+// ContentTypes produces a rule that restricts a message's content type to one of the given media types.
+// Media type parameters, such as charset, are ignored when comparing.  Messages without a content type are allowed.
+func ContentTypes(allowed ...string) Validator {
+	mediaTypes := make(map[string]bool, len(allowed))
+	for _, a := range allowed {
+		mediaTypes[baseMediaType(a)] = true
+	}
+
+	return ValidatorFunc(func(m *Message) error {
+		if len(m.ContentType) > 0 && !mediaTypes[baseMediaType(m.ContentType)] {
+			return &FieldError{Field: "content_type", Err: ErrContentTypeNotAllowed}
+		}
+
+		return nil
+	})
+}
+
+// baseMediaType strips any parameters from a content type and lowercases the result
+func baseMediaType(contentType string) string {
+	if mediaType, _, err := mime.ParseMediaType(contentType); err == nil {
+		return mediaType
+	}
+
+	return strings.ToLower(strings.TrimSpace(contentType))
+}
+
+// DefaultValidator returns a Validator that enforces the WRP specification for each message type.
+// SimpleRequestResponse and CRUD messages require a source, dest, and transaction_uuid.  SimpleEvent
+// messages require a source and dest.  ServiceRegistration messages require a service_name and url.  Authorization
+// messages require a status.
+//
+// For all routable types, source and dest must be locators.  For all types, textual fields must be valid UTF-8.
+// Messages of an unrecognized type are rejected.
+func DefaultValidator() Validator {
+	var (
+		utf8Rule    = UTF8()
+		locatorRule = Locators("source", "dest")
+		transaction = Validators{Required("source", "dest", "transaction_uuid"), locatorRule, utf8Rule}
+	)
+
+	return TypeValidators{
+		AuthMessageType:                  Required("status"),
+		SimpleRequestResponseMessageType: transaction,
+		SimpleEventMessageType:           Validators{Required("source", "dest"), locatorRule, utf8Rule},
+		CreateMessageType:                transaction,
+		RetrieveMessageType:              transaction,
+		UpdateMessageType:                transaction,
+		DeleteMessageType:                transaction,
+		ServiceRegistrationMessageType:   Validators{Required("service_name", "url"), utf8Rule},
+		ServiceAliveMessageType:          utf8Rule,
+	}
+}
@@ -0,0 +1,362 @@
+package wrp
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fieldErrors extracts the field names and reasons from a *ValidationError
+func fieldErrors(t *testing.T, err error) map[string]error {
+	require := require.New(t)
+	require.Error(err)
+
+	ve, ok := err.(*ValidationError)
+	require.True(ok, "expected a *ValidationError, got %T", err)
+
+	failures := make(map[string]error, len(ve.Errors))
+	for _, e := range ve.Errors {
+		fe, ok := e.(*FieldError)
+		require.True(ok, "expected a *FieldError, got %T", e)
+		failures[fe.Field] = fe.Err
+	}
+
+	return failures
+}
+
+func testValidatorFunc(t *testing.T) {
+	var (
+		assert        = assert.New(t)
+		expectedError = errors.New("expected")
+		called        = false
+		message       = new(Message)
+
+		v = ValidatorFunc(func(m *Message) error {
+			called = true
+			assert.True(message == m)
+			return expectedError
+		})
+	)
+
+	assert.Equal(expectedError, v.Validate(message))
+	assert.True(called)
+}
+
+func testValidatorsEmpty(t *testing.T) {
+	assert := assert.New(t)
+	assert.NoError(Validators{}.Validate(new(Message)))
+	assert.NoError(Validators(nil).Validate(new(Message)))
+}
+
+func testValidatorsAggregate(t *testing.T) {
+	var (
+		assert      = assert.New(t)
+		customError = errors.New("custom")
+		v           = Validators{
+			Required("source", "dest"),
+			ValidatorFunc(func(*Message) error { return nil }),
+			ValidatorFunc(func(*Message) error { return customError }),
+		}
+	)
+
+	err := v.Validate(&Message{Type: SimpleEventMessageType})
+	ve, ok := err.(*ValidationError)
+	assert.True(ok)
+	assert.Equal(SimpleEventMessageType, ve.Type)
+	assert.Len(ve.Errors, 3)
+	assert.Equal(&FieldError{Field: "source", Err: ErrMissingField}, ve.Errors[0])
+	assert.Equal(&FieldError{Field: "dest", Err: ErrMissingField}, ve.Errors[1])
+	assert.Equal(customError, ve.Errors[2])
+
+	assert.Contains(err.Error(), SimpleEventMessageType.String())
+	assert.Contains(err.Error(), "source: required field is missing")
+	assert.Contains(err.Error(), "custom")
+}
+
+func TestValidators(t *testing.T) {
+	t.Run("Func", testValidatorFunc)
+	t.Run("Empty", testValidatorsEmpty)
+	t.Run("Aggregate", testValidatorsAggregate)
+}
+
+func testRequiredAllFields(t *testing.T) {
+	var (
+		assert = assert.New(t)
+		v      = Required(
+			"source", "dest", "transaction_uuid", "content_type", "accept", "status", "rdr", "headers",
+			"metadata", "spans", "include_spans", "path", "payload", "service_name", "url", "partner_ids",
+			"session_id", "qos", "request_id",
+		)
+
+		message = new(Message)
+	)
+
+	assert.Len(fieldErrors(t, v.Validate(message)), 19)
+
+	message.Source = "dns:foo.com"
+	message.Destination = "mac:112233445566"
+	message.TransactionUUID = "1234"
+	message.ContentType = "text/plain"
+	message.Accept = "text/plain"
+	message.SetStatus(200)
+	message.SetRequestDeliveryResponse(0)
+	message.Headers = []string{"X-Header"}
+	message.Metadata = map[string]string{"key": "value"}
+	message.Spans = [][]string{{"span"}}
+	message.SetIncludeSpans(true)
+	message.Path = "/foo"
+	message.Payload = []byte("payload")
+	message.ServiceName = "service"
+	message.URL = "http://foo.com"
+	message.PartnerIDs = []string{"comcast"}
+	message.SessionID = "session"
+	message.QualityOfService = 50
+	message.RequestID = "request"
+
+	assert.NoError(v.Validate(message))
+}
+
+func testRequiredUnknownField(t *testing.T) {
+	assert := assert.New(t)
+	assert.Panics(func() { Required("nosuchfield") })
+}
+
+func TestRequired(t *testing.T) {
+	t.Run("AllFields", testRequiredAllFields)
+	t.Run("UnknownField", testRequiredUnknownField)
+}
+
+func TestLocators(t *testing.T) {
+	testData := []struct {
+		value string
+		valid bool
+	}{
+		{"", true},
+		{"mac:112233445566", true},
+		{"mac:11:22:33:44:55:66/service", true},
+		{"dns:webpa.comcast.com/v2-device-config", true},
+		{"serial:1234/config/foo/bar", true},
+		{"event:device-status/mac:112233445566/online", true},
+		{"foobar.com", false},
+		{"mac:", false},
+		{":112233445566", false},
+		{"mac:1122 33445566", false},
+		{"1mac:112233445566", false},
+		{"mac:invalid", false},
+	}
+
+	assert.Panics(t, func() { Locators("status") })
+	v := Locators("source", "dest")
+
+	for _, record := range testData {
+		t.Run(record.value, func(t *testing.T) {
+			assert := assert.New(t)
+			err := v.Validate(&Message{Source: record.value, Destination: "mac:112233445566"})
+			if record.valid {
+				assert.NoError(err)
+			} else {
+				assert.Equal(map[string]error{"source": ErrInvalidLocator}, fieldErrors(t, err))
+			}
+		})
+	}
+}
+
+func TestMaxLength(t *testing.T) {
+	var (
+		assert = assert.New(t)
+		v      = MaxLength(5, "path", "transaction_uuid", "session_id", "request_id")
+	)
+
+	assert.Panics(func() { MaxLength(1, "payload") })
+	assert.Panics(func() { MaxLength(1, "qos") })
+	assert.NoError(v.Validate(&Message{Path: "/1234", TransactionUUID: "12345", SessionID: "12345", RequestID: "12345"}))
+	assert.Equal(
+		map[string]error{"path": ErrTooLarge},
+		fieldErrors(t, v.Validate(&Message{Path: "/12345", TransactionUUID: "12345"})),
+	)
+	assert.Equal(
+		map[string]error{"session_id": ErrTooLarge, "request_id": ErrTooLarge},
+		fieldErrors(t, v.Validate(&Message{SessionID: "123456", RequestID: "123456"})),
+	)
+}
+
+func TestUTF8(t *testing.T) {
+	var (
+		assert  = assert.New(t)
+		invalid = string([]byte{0xFF, 0xFE})
+		v       = UTF8()
+	)
+
+	assert.NoError(v.Validate(&Message{
+		Source:     "dns:foo.com",
+		Headers:    []string{"X-Header: 日本語"},
+		Metadata:   map[string]string{"key": "value"},
+		Spans:      [][]string{{"span", "1", "2"}},
+		PartnerIDs: []string{"comcast"},
+		Payload:    []byte{0xFF, 0xFE},
+	}))
+
+	assert.Equal(
+		map[string]error{
+			"source":       ErrInvalidUTF8,
+			"dest":         ErrInvalidUTF8,
+			"service_name": ErrInvalidUTF8,
+			"headers":      ErrInvalidUTF8,
+			"metadata":     ErrInvalidUTF8,
+			"spans":        ErrInvalidUTF8,
+			"partner_ids":  ErrInvalidUTF8,
+			"session_id":   ErrInvalidUTF8,
+			"request_id":   ErrInvalidUTF8,
+		},
+		fieldErrors(t, v.Validate(&Message{
+			Source:      invalid,
+			Destination: invalid,
+			ServiceName: invalid,
+			Headers:     []string{"valid", invalid},
+			Metadata:    map[string]string{invalid: "value"},
+			Spans:       [][]string{{"valid"}, {invalid}},
+			PartnerIDs:  []string{invalid},
+			SessionID:   invalid,
+			RequestID:   invalid,
+		})),
+	)
+}
+
+func TestMaxPayloadSize(t *testing.T) {
+	var (
+		assert = assert.New(t)
+		v      = MaxPayloadSize(4)
+	)
+
+	assert.NoError(v.Validate(new(Message)))
+	assert.NoError(v.Validate(&Message{Payload: []byte("1234")}))
+	assert.Equal(&FieldError{Field: "payload", Err: ErrTooLarge}, v.Validate(&Message{Payload: []byte("12345")}))
+}
+
+func TestContentTypes(t *testing.T) {
+	testData := []struct {
+		contentType string
+		valid       bool
+	}{
+		{"", true},
+		{"application/json", true},
+		{"Application/JSON; charset=utf-8", true},
+		{"text/plain", true},
+		{"application/msgpack", false},
+		{"invalid", false},
+	}
+
+	v := ContentTypes("application/json", "text/plain; charset=utf-8")
+	for _, record := range testData {
+		t.Run(record.contentType, func(t *testing.T) {
+			assert := assert.New(t)
+			err := v.Validate(&Message{ContentType: record.contentType})
+			if record.valid {
+				assert.NoError(err)
+			} else {
+				assert.Equal(&FieldError{Field: "content_type", Err: ErrContentTypeNotAllowed}, err)
+			}
+		})
+	}
+}
+
+func testTypeValidatorsUnknownType(t *testing.T) {
+	assert := assert.New(t)
+	v := TypeValidators{SimpleEventMessageType: nil}
+
+	assert.NoError(v.Validate(&Message{Type: SimpleEventMessageType}))
+	assert.Equal(
+		map[string]error{"msg_type": ErrInvalidMessageType},
+		fieldErrors(t, v.Validate(&Message{Type: SimpleRequestResponseMessageType})),
+	)
+}
+
+func TestTypeValidators(t *testing.T) {
+	t.Run("UnknownType", testTypeValidatorsUnknownType)
+}
+
+func TestDefaultValidator(t *testing.T) {
+	testData := []struct {
+		name     string
+		message  Message
+		expected map[string]error
+	}{
+		{
+			"ValidSimpleRequestResponse",
+			Message{Type: SimpleRequestResponseMessageType, Source: "dns:foo.com", Destination: "mac:112233445566/config", TransactionUUID: "1234"},
+			nil,
+		},
+		{
+			"ValidAuthorization",
+			*(&Message{Type: AuthMessageType}).SetStatus(200),
+			nil,
+		},
+		{
+			"InvalidAuthorization",
+			Message{Type: AuthMessageType},
+			map[string]error{"status": ErrMissingField},
+		},
+		{
+			"InvalidSimpleRequestResponse",
+			Message{Type: SimpleRequestResponseMessageType, Source: "foo.com"},
+			map[string]error{"source": ErrInvalidLocator, "dest": ErrMissingField, "transaction_uuid": ErrMissingField},
+		},
+		{
+			"ValidSimpleEvent",
+			Message{Type: SimpleEventMessageType, Source: "mac:112233445566", Destination: "event:device-status"},
+			nil,
+		},
+		{
+			"InvalidSimpleEvent",
+			Message{Type: SimpleEventMessageType, Source: "mac:112233445566", Headers: []string{string([]byte{0xFF})}},
+			map[string]error{"dest": ErrMissingField, "headers": ErrInvalidUTF8},
+		},
+		{
+			"ValidCreate",
+			Message{Type: CreateMessageType, Source: "dns:foo.com", Destination: "mac:112233445566", TransactionUUID: "1234", Path: "/foo"},
+			nil,
+		},
+		{
+			"InvalidDelete",
+			Message{Type: DeleteMessageType, Source: "dns:foo.com", Destination: "mac:112233445566"},
+			map[string]error{"transaction_uuid": ErrMissingField},
+		},
+		{
+			"ValidServiceRegistration",
+			Message{Type: ServiceRegistrationMessageType, ServiceName: "config", URL: "local:/config"},
+			nil,
+		},
+		{
+			"InvalidServiceRegistration",
+			Message{Type: ServiceRegistrationMessageType},
+			map[string]error{"service_name": ErrMissingField, "url": ErrMissingField},
+		},
+		{
+			"ValidServiceAlive",
+			Message{Type: ServiceAliveMessageType},
+			nil,
+		},
+		{
+			"InvalidMessageType",
+			Message{Type: MessageType(999)},
+			map[string]error{"msg_type": ErrInvalidMessageType},
+		},
+	}
+
+	v := DefaultValidator()
+	for _, record := range testData {
+		t.Run(record.name, func(t *testing.T) {
+			assert := assert.New(t)
+			err := v.Validate(&record.message)
+			if record.expected == nil {
+				assert.NoError(err)
+			} else {
+				assert.Equal(record.expected, fieldErrors(t, err))
+				assert.True(strings.HasPrefix(err.Error(), "Invalid "))
+			}
+		})
+	}
+}
@@ -0,0 +1,192 @@
+package wrpendpoint
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"path"
+	"strings"
+	"sync"
+
+	"github.com/Comcast/webpa-common/wrp"
+)
+
+// matchAll is the pattern segment that matches zero or more trailing segments of a destination
+const matchAll = "**"
+
+// NotFound is the default Service used by a Mux when no route matches a request.  The response is addressed
+// back to the request's source and carries a 404 status, so that callers waiting on a transaction are answered.
+func NotFound(_ context.Context, request Request) (Response, error) {
+	var (
+		m = request.Message()
+
+		status int64 = http.StatusNotFound
+		reply        = &wrp.Message{Type: wrp.SimpleRequestResponseMessageType, Status: &status, ContentType: "text/plain"}
+	)
+
+	if m != nil {
+		reply.Type = m.Type
+		reply.Source = m.Destination
+		reply.Destination = m.Source
+		reply.TransactionUUID = m.TransactionUUID
+		reply.Payload = []byte(fmt.Sprintf("No service found for destination %s", m.Destination))
+	}
+
+	return WrapAsResponse(reply), nil
+}
+
+// route is a single registration with a Mux
+type route struct {
+	segments []string
+	types    map[wrp.MessageType]bool
+	service  Service
+}
+
+// matches tests if this route applies to the given message type and destination locator segments
+func (r *route) matches(t wrp.MessageType, segments []string) bool {
+	if len(r.types) > 0 && !r.types[t] {
+		return false
+	}
+
+	for i, p := range r.segments {
+		if p == matchAll {
+			return true
+		}
+
+		if i >= len(segments) {
+			return false
+		}
+
+		if ok, _ := path.Match(p, segments[i]); !ok {
+			return false
+		}
+	}
+
+	return len(r.segments) == len(segments)
+}
+
+// Mux is a Service that dispatches requests to other Services based on message type and destination.
+// Routes are matched against the service and path of the destination locator, e.g. "config/wifi" for the
+// destination mac:112233445566/config/wifi.
+//
+// A pattern is a slash-delimited sequence of segments.  The empty pattern matches only destinations with no
+// service.  Each segment is matched against the corresponding segment of the destination using path.Match, so a
+// segment of "*" matches any single segment.  A final segment of "**" matches zero or more remaining segments, so
+// that "config/**" matches both "config" and "config/wifi/ssid", and "**" by itself matches every destination.
+//
+// Routes are tried in the order they were registered, and the first match wins.  Requests that match no route
+// are handed to the NotFound service, which can be replaced via SetNotFound.
+//
+// A Mux is safe for concurrent use.  Since it is a Service, it can be passed to New to create a go-kit endpoint.
+type Mux struct {
+	lock     sync.RWMutex
+	routes   []*route
+	notFound Service
+}
+
+// NewMux creates an empty Mux
+func NewMux() *Mux {
+	return &Mux{
+		notFound: ServiceFunc(NotFound),
+	}
+}
+
+// Handle registers a Service for the given destination pattern.  If any message types are supplied, the route
+// only applies to messages of those types.  Otherwise, the route applies to all message types.
+//
+// This method panics if the pattern is malformed or the Service is nil.
+func (m *Mux) Handle(pattern string, s Service, types ...wrp.MessageType) {
+	if s == nil {
+		panic("A WRP Service is required")
+	}
+
+	var segments []string
+	if trimmed := strings.Trim(pattern, "/"); len(trimmed) > 0 {
+		segments = strings.Split(trimmed, "/")
+	}
+
+	for i, p := range segments {
+		if p == matchAll {
+			if i != len(segments)-1 {
+				panic(fmt.Errorf("%s must be the last segment of a pattern: %s", matchAll, pattern))
+			}
+
+			continue
+		}
+
+		if _, err := path.Match(p, ""); err != nil {
+			panic(fmt.Errorf("Invalid pattern %s: %s", pattern, err))
+		}
+	}
+
+	r := &route{segments: segments, service: s}
+	if len(types) > 0 {
+		r.types = make(map[wrp.MessageType]bool, len(types))
+		for _, t := range types {
+			r.types[t] = true
+		}
+	}
+
+	defer m.lock.Unlock()
+	m.lock.Lock()
+	m.routes = append(m.routes, r)
+}
+
+// HandleFunc is like Handle, but accepts a function instead of a Service
+func (m *Mux) HandleFunc(pattern string, f func(context.Context, Request) (Response, error), types ...wrp.MessageType) {
+	m.Handle(pattern, ServiceFunc(f), types...)
+}
+
+// SetNotFound changes the Service that handles requests which match no route.  If s is nil,
+// the NotFound function is used.
+func (m *Mux) SetNotFound(s Service) {
+	if s == nil {
+		s = ServiceFunc(NotFound)
+	}
+
+	defer m.lock.Unlock()
+	m.lock.Lock()
+	m.notFound = s
+}
+
+// Route returns the Service that will handle the given request.  If no route matches, the not found Service
+// is returned.  Requests whose destination is not a valid locator never match a route.
+func (m *Mux) Route(request Request) Service {
+	var (
+		t        wrp.MessageType
+		segments []string
+	)
+
+	if message := request.Message(); message != nil {
+		t = message.Type
+	}
+
+	locator, err := wrp.ParseLocator(request.Destination())
+
+	defer m.lock.RUnlock()
+	m.lock.RLock()
+
+	if err != nil {
+		return m.notFound
+	}
+
+	if len(locator.Service) > 0 {
+		segments = append([]string{locator.Service}, strings.Split(strings.Trim(locator.Path, "/"), "/")...)
+		if len(segments[len(segments)-1]) == 0 {
+			segments = segments[:len(segments)-1]
+		}
+	}
+
+	for _, r := range m.routes {
+		if r.matches(t, segments) {
+			return r.service
+		}
+	}
+
+	return m.notFound
+}
+
+// ServeWRP dispatches the request to the Service returned by Route
+func (m *Mux) ServeWRP(ctx context.Context, request Request) (Response, error) {
+	return m.Route(request).ServeWRP(ctx, request)
+}
@@ -0,0 +1,160 @@
+package wrpendpoint
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/Comcast/webpa-common/logging"
+	"github.com/Comcast/webpa-common/wrp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// namedService produces a Service that responds with a message whose source is the given name
+func namedService(name string) Service {
+	return ServiceFunc(func(context.Context, Request) (Response, error) {
+		return WrapAsResponse(&wrp.Message{Source: name}), nil
+	})
+}
+
+func testNotFound(t *testing.T) {
+	var (
+		assert  = assert.New(t)
+		require = require.New(t)
+
+		request = WrapAsRequest(logging.NewTestLogger(nil, t), &wrp.Message{
+			Type:            wrp.SimpleRequestResponseMessageType,
+			Source:          "dns:talaria.example.com",
+			Destination:     "mac:112233445566/nosuch",
+			TransactionUUID: "1234",
+		})
+	)
+
+	response, err := NotFound(context.Background(), request)
+	require.NoError(err)
+	require.NotNil(response)
+
+	m := response.Message()
+	require.NotNil(m)
+	assert.Equal(wrp.SimpleRequestResponseMessageType, m.Type)
+	assert.Equal("mac:112233445566/nosuch", m.Source)
+	assert.Equal("dns:talaria.example.com", m.Destination)
+	assert.Equal("1234", m.TransactionUUID)
+	require.NotNil(m.Status)
+	assert.Equal(int64(http.StatusNotFound), *m.Status)
+	assert.NotEmpty(m.Payload)
+}
+
+func testMuxRoute(t *testing.T) {
+	mux := NewMux()
+	mux.Handle("config", namedService("config"), wrp.RetrieveMessageType, wrp.UpdateMessageType)
+	mux.Handle("config/wifi/*", namedService("wifi"))
+	mux.Handle("config/**", namedService("configTree"))
+	mux.Handle("iot/*/status", namedService("iotStatus"), wrp.SimpleEventMessageType)
+	mux.Handle("", namedService("root"))
+	mux.HandleFunc("/fallback/**", namedService("fallback").ServeWRP)
+
+	testData := []struct {
+		messageType wrp.MessageType
+		destination string
+		expected    string
+	}{
+		{wrp.RetrieveMessageType, "mac:112233445566/config", "config"},
+		{wrp.UpdateMessageType, "MAC:11:22:33:44:55:66/config/", "config"},
+		{wrp.CreateMessageType, "mac:112233445566/config", "configTree"},
+		{wrp.CreateMessageType, "mac:112233445566/config/wifi/ssid", "wifi"},
+		{wrp.CreateMessageType, "mac:112233445566/config/wifi", "configTree"},
+		{wrp.CreateMessageType, "mac:112233445566/config/wifi/ssid/extra", "configTree"},
+		{wrp.SimpleEventMessageType, "dns:example.com/iot/sensor1/status", "iotStatus"},
+		{wrp.SimpleRequestResponseMessageType, "dns:example.com/iot/sensor1/status", ""},
+		{wrp.SimpleEventMessageType, "dns:example.com/iot/status", ""},
+		{wrp.SimpleEventMessageType, "dns:example.com", "root"},
+		{wrp.SimpleEventMessageType, "dns:example.com/fallback", "fallback"},
+		{wrp.SimpleEventMessageType, "dns:example.com/fallback/a/b/c", "fallback"},
+		{wrp.SimpleEventMessageType, "dns:example.com/nosuch", ""},
+		{wrp.SimpleEventMessageType, "this is not a locator", ""},
+	}
+
+	for _, record := range testData {
+		t.Run(record.destination, func(t *testing.T) {
+			var (
+				assert  = assert.New(t)
+				require = require.New(t)
+
+				request = WrapAsRequest(logging.NewTestLogger(nil, t), &wrp.Message{
+					Type:        record.messageType,
+					Source:      "dns:talaria.example.com",
+					Destination: record.destination,
+				})
+			)
+
+			response, err := mux.ServeWRP(context.Background(), request)
+			require.NoError(err)
+			require.NotNil(response)
+
+			if len(record.expected) > 0 {
+				assert.Equal(record.expected, response.Message().Source)
+			} else {
+				require.NotNil(response.Message().Status)
+				assert.Equal(int64(http.StatusNotFound), *response.Message().Status)
+			}
+		})
+	}
+}
+
+func testMuxSetNotFound(t *testing.T) {
+	var (
+		assert  = assert.New(t)
+		require = require.New(t)
+
+		mux     = NewMux()
+		request = WrapAsRequest(logging.NewTestLogger(nil, t), &wrp.Message{Destination: "mac:112233445566/nosuch"})
+	)
+
+	mux.SetNotFound(namedService("custom"))
+	response, err := mux.ServeWRP(context.Background(), request)
+	require.NoError(err)
+	assert.Equal("custom", response.Message().Source)
+
+	mux.SetNotFound(nil)
+	response, err = mux.ServeWRP(context.Background(), request)
+	require.NoError(err)
+	require.NotNil(response.Message().Status)
+	assert.Equal(int64(http.StatusNotFound), *response.Message().Status)
+}
+
+func testMuxHandlePanics(t *testing.T) {
+	var (
+		assert = assert.New(t)
+		mux    = NewMux()
+	)
+
+	assert.Panics(func() { mux.Handle("config", nil) })
+	assert.Panics(func() { mux.Handle("config/**/wifi", namedService("invalid")) })
+	assert.Panics(func() { mux.Handle("config/[", namedService("invalid")) })
+}
+
+func testMuxEndpoint(t *testing.T) {
+	var (
+		assert  = assert.New(t)
+		require = require.New(t)
+
+		mux     = NewMux()
+		request = WrapAsRequest(logging.NewTestLogger(nil, t), &wrp.Message{Destination: "mac:112233445566/config"})
+	)
+
+	mux.Handle("config", namedService("config"))
+	response, err := New(mux)(context.Background(), request)
+	require.NoError(err)
+	require.NotNil(response)
+	assert.Equal("config", response.(Response).Message().Source)
+}
+
+func TestMux(t *testing.T) {
+	t.Run("NotFound", testNotFound)
+	t.Run("Route", testMuxRoute)
+	t.Run("SetNotFound", testMuxSetNotFound)
+	t.Run("HandlePanics", testMuxHandlePanics)
+	t.Run("Endpoint", testMuxEndpoint)
+}
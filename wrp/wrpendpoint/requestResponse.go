@@ -55,7 +55,7 @@ func (n *note) Encode(output io.Writer, format wrp.Format) error {
 		return err
 	}
 
-	return wrp.NewEncoder(output, format).Encode(n.message)
+	return wrp.DefaultEncoderPool(format).Encode(output, n.message)
 }
 
 func (n *note) EncodeBytes(format wrp.Format) ([]byte, error) {
@@ -65,9 +65,7 @@ func (n *note) EncodeBytes(format wrp.Format) ([]byte, error) {
 		return copyOf, nil
 	}
 
-	var output []byte
-	err := wrp.NewEncoderBytes(&output, format).Encode(n.message)
-	return output, err
+	return wrp.DefaultEncoderPool(format).EncodeBytes(n.message)
 }
 
 // Request is a WRP request.  In addition to implementing Note, this type also provides contextual logging.
@@ -143,7 +141,7 @@ func DecodeRequest(logger log.Logger, source io.Reader, format wrp.Format) (Requ
 // logger that is passed to this function should never be nil and should never have a Caller or DefaultCaller set.
 func DecodeRequestBytes(logger log.Logger, contents []byte, format wrp.Format) (Request, error) {
 	m := new(wrp.Message)
-	if err := wrp.NewDecoderBytes(contents, format).Decode(m); err != nil {
+	if err := wrp.DefaultDecoderPool(format).DecodeBytes(m, contents); err != nil {
 		return nil, err
 	}
 
@@ -213,7 +211,7 @@ func DecodeResponse(source io.Reader, format wrp.Format) (Response, error) {
 // DecodeResponseBytes returns a Response taken from the contents.  The given pool is used to decode the WRP message.
 func DecodeResponseBytes(contents []byte, format wrp.Format) (Response, error) {
 	m := new(wrp.Message)
-	if err := wrp.NewDecoderBytes(contents, format).Decode(m); err != nil {
+	if err := wrp.DefaultDecoderPool(format).DecodeBytes(m, contents); err != nil {
 		return nil, err
 	}
 
@@ -0,0 +1,132 @@
+package wrpendpoint
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/Comcast/webpa-common/tracing"
+)
+
+// SpanTimeLayout is the layout of the start time of spans carried in WRP messages
+const SpanTimeLayout = time.RFC3339Nano
+
+// EncodeSpans converts spans into the form carried by the spans field of a WRP message.  Each span is encoded
+// as its name, its start time in UTC using SpanTimeLayout, and its duration as produced by time.Duration.String.
+// This is the same form used by the X-Xmidt-Span header.  Span errors are not encoded.
+func EncodeSpans(spans ...tracing.Span) [][]string {
+	if len(spans) == 0 {
+		return nil
+	}
+
+	encoded := make([][]string, len(spans))
+	for i, s := range spans {
+		encoded[i] = []string{s.Name(), s.Start().UTC().Format(SpanTimeLayout), s.Duration().String()}
+	}
+
+	return encoded
+}
+
+// decodedSpan is a tracing.Span recreated from a WRP message
+type decodedSpan struct {
+	name     string
+	start    time.Time
+	duration time.Duration
+}
+
+func (ds decodedSpan) Name() string {
+	return ds.name
+}
+
+func (ds decodedSpan) Start() time.Time {
+	return ds.start
+}
+
+func (ds decodedSpan) Duration() time.Duration {
+	return ds.duration
+}
+
+func (ds decodedSpan) Error() error {
+	return nil
+}
+
+// DecodeSpans is the inverse of EncodeSpans.  Since errors are not carried in WRP messages, the decoded
+// spans never have an error.  Any span that is not in the form produced by EncodeSpans results in an error.
+func DecodeSpans(encoded [][]string) ([]tracing.Span, error) {
+	if len(encoded) == 0 {
+		return nil, nil
+	}
+
+	spans := make([]tracing.Span, len(encoded))
+	for i, fields := range encoded {
+		if len(fields) != 3 {
+			return nil, fmt.Errorf("Invalid span %q: expected 3 fields", fields)
+		}
+
+		start, err := time.Parse(SpanTimeLayout, fields[1])
+		if err != nil {
+			return nil, fmt.Errorf("Invalid span %q: %s", fields, err)
+		}
+
+		duration, err := time.ParseDuration(fields[2])
+		if err != nil {
+			return nil, fmt.Errorf("Invalid span %q: %s", fields, err)
+		}
+
+		spans[i] = decodedSpan{name: fields[0], start: start, duration: duration}
+	}
+
+	return spans, nil
+}
+
+// includeSpans tests if a request asked for spans to be returned in the response message
+func includeSpans(request Request) bool {
+	m := request.Message()
+	return m != nil && m.IncludeSpans != nil && *m.IncludeSpans
+}
+
+// withEncodedSpans produces a copy of a response whose message carries the response's spans
+func withEncodedSpans(response Response) Response {
+	m := response.Message()
+	if m == nil {
+		return response
+	}
+
+	copyOf := *m
+	copyOf.Spans = EncodeSpans(response.Spans()...)
+	if merged, ok := tracing.MergeSpans(WrapAsResponse(&copyOf), response.Spans()); ok {
+		return merged.(Response)
+	}
+
+	return WrapAsResponse(&copyOf)
+}
+
+// Tracing produces a decorator that times each request to a Service with a span named for the request's
+// destination.  The span is merged into the Response after any spans already present, such as those returned
+// by downstream services.  If the request's message sets include_spans to true, all of the response's spans
+// are also encoded into the spans field of the response message, replacing any spans already there.
+//
+// Responses are immutable, so the decorated Service returns a copy of the downstream Response when spans are
+// added.  When the downstream Service returns an error or a nil Response, the span is discarded.
+func Tracing(spanner tracing.Spanner) func(Service) Service {
+	return func(next Service) Service {
+		return ServiceFunc(func(ctx context.Context, request Request) (Response, error) {
+			finish := spanner.Start(request.Destination())
+			response, err := next.ServeWRP(ctx, request)
+			span := finish(err)
+			if err != nil || response == nil {
+				return response, err
+			}
+
+			if merged, ok := tracing.MergeSpans(response, span); ok {
+				response = merged.(Response)
+			}
+
+			if includeSpans(request) {
+				response = withEncodedSpans(response)
+			}
+
+			return response, err
+		})
+	}
+}
@@ -0,0 +1,175 @@
+package wrpendpoint
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Comcast/webpa-common/logging"
+	"github.com/Comcast/webpa-common/tracing"
+	"github.com/Comcast/webpa-common/wrp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	testSpanStart    = time.Date(2018, time.March, 1, 12, 30, 15, 123456789, time.UTC)
+	testSpanDuration = 1500 * time.Millisecond
+
+	testSpanner = tracing.NewSpanner(
+		tracing.Now(func() time.Time { return testSpanStart }),
+		tracing.Since(func(time.Time) time.Duration { return testSpanDuration }),
+	)
+)
+
+func TestEncodeSpans(t *testing.T) {
+	var (
+		assert  = assert.New(t)
+		require = require.New(t)
+
+		spans = []tracing.Span{
+			testSpanner.Start("first")(nil),
+			testSpanner.Start("second")(errors.New("expected")),
+		}
+	)
+
+	assert.Nil(EncodeSpans())
+
+	encoded := EncodeSpans(spans...)
+	assert.Equal(
+		[][]string{
+			{"first", "2018-03-01T12:30:15.123456789Z", "1.5s"},
+			{"second", "2018-03-01T12:30:15.123456789Z", "1.5s"},
+		},
+		encoded,
+	)
+
+	decoded, err := DecodeSpans(encoded)
+	require.NoError(err)
+	require.Len(decoded, len(spans))
+	for i, s := range decoded {
+		assert.Equal(spans[i].Name(), s.Name())
+		assert.True(spans[i].Start().Equal(s.Start()))
+		assert.Equal(spans[i].Duration(), s.Duration())
+
+		// errors are not carried in WRP messages
+		assert.NoError(s.Error())
+	}
+}
+
+func TestDecodeSpans(t *testing.T) {
+	assert := assert.New(t)
+
+	spans, err := DecodeSpans(nil)
+	assert.Empty(spans)
+	assert.NoError(err)
+
+	for _, invalid := range [][]string{
+		{"name"},
+		{"name", "not a time", "1s"},
+		{"name", "2018-03-01T12:30:15Z", "not a duration"},
+		{"name", "2018-03-01T12:30:15Z", "1s", "extra"},
+	} {
+		spans, err := DecodeSpans([][]string{invalid})
+		assert.Empty(spans)
+		assert.Error(err)
+	}
+}
+
+func testTracingSuccess(t *testing.T, include *bool) {
+	var (
+		assert  = assert.New(t)
+		require = require.New(t)
+
+		downstreamSpan = testSpanner.Start("downstream")(nil)
+		next           = new(mockService)
+		service        = Tracing(testSpanner)(next)
+
+		requestMessage  = &wrp.Message{Destination: "mac:112233445566/config", IncludeSpans: include}
+		responseMessage = &wrp.Message{Source: "mac:112233445566/config"}
+		request         = WrapAsRequest(logging.NewTestLogger(nil, t), requestMessage)
+	)
+
+	next.On("ServeWRP", mock.Anything, request).
+		Return(WrapAsResponse(responseMessage).WithSpans(downstreamSpan), error(nil)).
+		Once()
+
+	response, err := service.ServeWRP(context.Background(), request)
+	require.NoError(err)
+	require.NotNil(response)
+
+	spans := response.Spans()
+	require.Len(spans, 2)
+	assert.Equal(downstreamSpan, spans[0])
+	assert.Equal("mac:112233445566/config", spans[1].Name())
+	assert.Equal(testSpanDuration, spans[1].Duration())
+
+	if include != nil && *include {
+		require.NotNil(response.Message())
+		assert.Equal(EncodeSpans(spans...), response.Message().Spans)
+		assert.Equal("mac:112233445566/config", response.Message().Source)
+	} else {
+		assert.Empty(response.Message().Spans)
+	}
+
+	// the downstream response is never modified
+	assert.Empty(responseMessage.Spans)
+	next.AssertExpectations(t)
+}
+
+func testTracingError(t *testing.T) {
+	var (
+		assert = assert.New(t)
+
+		expectedErr = errors.New("expected")
+		next        = new(mockService)
+		service     = Tracing(testSpanner)(next)
+		request     = WrapAsRequest(logging.NewTestLogger(nil, t), new(wrp.Message).SetIncludeSpans(true))
+	)
+
+	next.On("ServeWRP", mock.Anything, request).Return(nil, expectedErr).Once()
+
+	response, err := service.ServeWRP(context.Background(), request)
+	assert.Nil(response)
+	assert.Equal(expectedErr, err)
+	next.AssertExpectations(t)
+}
+
+func testTracingErrorWithResponse(t *testing.T) {
+	var (
+		assert = assert.New(t)
+
+		expectedErr      = errors.New("expected")
+		next             = new(mockService)
+		service          = Tracing(testSpanner)(next)
+		request          = WrapAsRequest(logging.NewTestLogger(nil, t), new(wrp.Message).SetIncludeSpans(true))
+		expectedResponse = WrapAsResponse(&wrp.Message{Source: "mac:112233445566/config"})
+	)
+
+	next.On("ServeWRP", mock.Anything, request).Return(expectedResponse, expectedErr).Once()
+
+	response, err := service.ServeWRP(context.Background(), request)
+	assert.Equal(expectedResponse, response)
+	assert.Equal(expectedErr, err)
+	assert.Empty(response.Spans())
+	assert.Empty(response.Message().Spans)
+	next.AssertExpectations(t)
+}
+
+func TestTracing(t *testing.T) {
+	var (
+		yes = true
+		no  = false
+	)
+
+	t.Run("Success", func(t *testing.T) {
+		t.Run("IncludeSpansUnset", func(t *testing.T) { testTracingSuccess(t, nil) })
+		t.Run("IncludeSpansFalse", func(t *testing.T) { testTracingSuccess(t, &no) })
+		t.Run("IncludeSpansTrue", func(t *testing.T) { testTracingSuccess(t, &yes) })
+	})
+
+	t.Run("Error", testTracingError)
+	t.Run("ErrorWithResponse", testTracingErrorWithResponse)
+}
@@ -0,0 +1,175 @@
+package wrphttp
+
+import (
+	"context"
+	"io"
+	"net/http"
+
+	"github.com/Comcast/webpa-common/logging"
+	"github.com/Comcast/webpa-common/wrp"
+	"github.com/Comcast/webpa-common/wrp/wrpendpoint"
+	"github.com/Comcast/webpa-common/xhttp"
+	"github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/log/level"
+	gokithttp "github.com/go-kit/kit/transport/http"
+)
+
+// BatchResult reports the outcome of a single message within a batch.  A batch response is a stream, as written
+// by wrp.StreamWriter, of one BatchResult for each message in the batch request, in the same order.  This allows
+// some messages in a batch to fail while others succeed.
+type BatchResult struct {
+	// Index is the zero-based position of the message within the batch request
+	Index int `wrp:"index"`
+
+	// Status is the HTTP status code that would have resulted had the message been sent by itself, e.g.
+	// http.StatusOK for a message with a response or http.StatusBadRequest for a message that could not be decoded
+	Status int `wrp:"status"`
+
+	// Error describes the failure, if any
+	Error string `wrp:"error,omitempty"`
+
+	// Message is the WRP response to the message, if any
+	Message *wrp.Message `wrp:"message,omitempty"`
+}
+
+// BatchOption is a configurable option for a batch handler
+type BatchOption func(*batchHandler)
+
+// WithBatchFormat sets the format used for batch requests that have no Content-Type.  By default, wrp.Msgpack is used.
+func WithBatchFormat(f wrp.Format) BatchOption {
+	return func(bh *batchHandler) {
+		bh.format = f
+	}
+}
+
+// WithBatchValidator sets a Validator that each message in a batch must pass.  Messages that fail validation
+// are reported with http.StatusBadRequest and are not passed to the service.  By default, messages are not validated.
+func WithBatchValidator(v wrp.Validator) BatchOption {
+	return func(bh *batchHandler) {
+		bh.validator = v
+	}
+}
+
+// NewBatchHandler creates an http.Handler that accepts a stream of WRP messages, as written by wrp.StreamWriter,
+// and passes each message in turn to the supplied service.  The Content-Type of the request must be one of the
+// stream content types, e.g. application/x-ndjson.  The response is a stream of BatchResult values in the format
+// requested by the Accept header, falling back to the request format.
+//
+// Once the response has begun, the status code is always http.StatusOK.  Failures of individual messages are
+// reported in their BatchResult.  If the request stream itself is damaged, e.g. truncated, a final BatchResult
+// with http.StatusBadRequest is written for the message that could not be read and processing stops.
+func NewBatchHandler(s wrpendpoint.Service, options ...BatchOption) http.Handler {
+	if s == nil {
+		panic("A WRP Service is required")
+	}
+
+	bh := &batchHandler{
+		service: s,
+		format:  wrp.Msgpack,
+	}
+
+	for _, o := range options {
+		o(bh)
+	}
+
+	return bh
+}
+
+type batchHandler struct {
+	service   wrpendpoint.Service
+	format    wrp.Format
+	validator wrp.Validator
+}
+
+func (bh *batchHandler) ServeHTTP(httpResponse http.ResponseWriter, httpRequest *http.Request) {
+	contentType := httpRequest.Header.Get("Content-Type")
+	if len(contentType) > 0 && !wrp.IsStreamContentType(contentType) {
+		xhttp.WriteErrorf(httpResponse, http.StatusUnsupportedMediaType, "Batch requests require a stream content type, not %s", contentType)
+		return
+	}
+
+	requestFormat, err := DetermineFormat(bh.format, httpRequest.Header, "Content-Type")
+	if err != nil {
+		xhttp.WriteError(httpResponse, http.StatusUnsupportedMediaType, err)
+		return
+	}
+
+	responseFormat, err := DetermineFormat(requestFormat, httpRequest.Header, "Accept")
+	if err != nil {
+		xhttp.WriteError(httpResponse, http.StatusNotAcceptable, err)
+		return
+	}
+
+	var (
+		ctx    = httpRequest.Context()
+		logger = logging.GetLogger(ctx)
+		reader = wrp.NewStreamReader(httpRequest.Body, requestFormat)
+		writer = wrp.NewStreamWriter(httpResponse, responseFormat)
+	)
+
+	httpResponse.Header().Set("Content-Type", responseFormat.StreamContentType())
+	for index := 0; ; index++ {
+		var (
+			m       = new(wrp.Message)
+			result  = BatchResult{Index: index}
+			damaged = false
+		)
+
+		switch err := reader.Read(m).(type) {
+		case nil:
+			bh.serve(ctx, logger, m, &result)
+
+		case *wrp.FrameError:
+			result.Status = http.StatusBadRequest
+			result.Error = err.Err.Error()
+
+		default:
+			if err == io.EOF {
+				return
+			}
+
+			// the stream is damaged, so no further messages can be read
+			result.Status = http.StatusBadRequest
+			result.Error = err.Error()
+			damaged = true
+		}
+
+		if err := writer.Write(&result); err != nil {
+			logger.Log(level.Key(), level.ErrorValue(), logging.MessageKey(), "unable to write batch result", logging.ErrorKey(), err)
+			return
+		}
+
+		if damaged {
+			return
+		}
+	}
+}
+
+// serve passes a single message from a batch to the service, recording the outcome in the result
+func (bh *batchHandler) serve(ctx context.Context, logger log.Logger, m *wrp.Message, result *BatchResult) {
+	if bh.validator != nil {
+		if err := bh.validator.Validate(m); err != nil {
+			result.Status = http.StatusBadRequest
+			result.Error = err.Error()
+			return
+		}
+	}
+
+	response, err := bh.service.ServeWRP(ctx, wrpendpoint.WrapAsRequest(logger, m))
+	switch {
+	case err != nil:
+		result.Status = http.StatusInternalServerError
+		if sc, ok := err.(gokithttp.StatusCoder); ok {
+			result.Status = sc.StatusCode()
+		}
+
+		result.Error = err.Error()
+
+	case response == nil || response.Message() == nil:
+		result.Status = http.StatusNoContent
+
+	default:
+		result.Status = http.StatusOK
+		result.Message = response.Message()
+	}
+}
@@ -0,0 +1,228 @@
+package wrphttp
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Comcast/webpa-common/wrp"
+	"github.com/Comcast/webpa-common/wrp/wrpendpoint"
+	"github.com/Comcast/webpa-common/xhttp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newBatchTestService produces a service that responds to config requests, accepts events with no response,
+// and fails requests to fail
+func newBatchTestService() wrpendpoint.Service {
+	mux := wrpendpoint.NewMux()
+	mux.HandleFunc("config/**", func(_ context.Context, r wrpendpoint.Request) (wrpendpoint.Response, error) {
+		return wrpendpoint.WrapAsResponse(&wrp.Message{
+			Type:            wrp.SimpleRequestResponseMessageType,
+			Source:          r.Destination(),
+			TransactionUUID: r.TransactionID(),
+			ContentType:     "text/plain",
+			Payload:         []byte("config"),
+		}), nil
+	})
+
+	mux.HandleFunc("event/**", func(context.Context, wrpendpoint.Request) (wrpendpoint.Response, error) {
+		return nil, nil
+	})
+
+	mux.HandleFunc("fail/**", func(context.Context, wrpendpoint.Request) (wrpendpoint.Response, error) {
+		return nil, errors.New("expected")
+	})
+
+	return mux
+}
+
+var batchTestMessages = []*wrp.Message{
+	{Type: wrp.SimpleRequestResponseMessageType, Source: "dns:talaria.example.com", Destination: "mac:112233445566/config", TransactionUUID: "1"},
+	{Type: wrp.SimpleEventMessageType, Source: "dns:talaria.example.com", Destination: "mac:112233445566/event"},
+	{Type: wrp.SimpleRequestResponseMessageType, Source: "dns:talaria.example.com", Destination: "mac:112233445566/fail", TransactionUUID: "2"},
+	{Type: wrp.SimpleRequestResponseMessageType, Source: "dns:talaria.example.com", Destination: "mac:112233445566/nosuch", TransactionUUID: "3"},
+}
+
+func assertBatchTestResults(t *testing.T, results []BatchResult) {
+	var (
+		assert  = assert.New(t)
+		require = require.New(t)
+	)
+
+	require.Len(results, len(batchTestMessages))
+	for i, result := range results {
+		assert.Equal(i, result.Index)
+	}
+
+	assert.Equal(http.StatusOK, results[0].Status)
+	assert.Empty(results[0].Error)
+	require.NotNil(results[0].Message)
+	assert.Equal("mac:112233445566/config", results[0].Message.Source)
+	assert.Equal("1", results[0].Message.TransactionUUID)
+	assert.Equal([]byte("config"), results[0].Message.Payload)
+
+	assert.Equal(http.StatusNoContent, results[1].Status)
+	assert.Nil(results[1].Message)
+
+	assert.Equal(http.StatusInternalServerError, results[2].Status)
+	assert.Equal("expected", results[2].Error)
+
+	// the service answers unroutable messages with a WRP status, rather than failing
+	assert.Equal(http.StatusOK, results[3].Status)
+	require.NotNil(results[3].Message)
+	require.NotNil(results[3].Message.Status)
+	assert.Equal(int64(http.StatusNotFound), *results[3].Message.Status)
+}
+
+func testBatchHandlerNil(t *testing.T) {
+	assert.Panics(t, func() {
+		NewBatchHandler(nil)
+	})
+}
+
+func testBatchHandlerClient(t *testing.T, requestFormat, responseFormat wrp.Format) {
+	var (
+		require = require.New(t)
+
+		server = httptest.NewServer(NewBatchHandler(newBatchTestService()))
+		client = NewClient(server.URL, WithClientFormat(requestFormat), WithAccept(responseFormat))
+	)
+
+	defer server.Close()
+
+	results, err := client.SendBatch(context.Background(), batchTestMessages)
+	require.NoError(err)
+	assertBatchTestResults(t, results)
+}
+
+func testBatchHandlerPartialFailure(t *testing.T, f wrp.Format) {
+	var (
+		assert  = assert.New(t)
+		require = require.New(t)
+
+		handler  = NewBatchHandler(newBatchTestService(), WithBatchFormat(f), WithBatchValidator(wrp.DefaultValidator()))
+		body     bytes.Buffer
+		writer   = wrp.NewStreamWriter(&body, f)
+		response = httptest.NewRecorder()
+	)
+
+	require.NoError(writer.Write(batchTestMessages[0]))
+
+	// a message that does not pass validation
+	require.NoError(writer.Write(&wrp.Message{Type: wrp.SimpleRequestResponseMessageType}))
+
+	// a frame that cannot be decoded, followed by a final message.  For the binary formats, the final frame
+	// is truncated, which damages the stream.
+	if f == wrp.JSON {
+		body.WriteString("not a message\n")
+		body.WriteString(`{"msg_type": 3, "source": "dns:talaria.example.com", "dest": "mac:112233445566/config", "transaction_uuid": "4"}`)
+	} else {
+		body.Write([]byte{0x00, 0x00, 0x00, 0x01, 0xc1})
+		body.Write([]byte{0x00, 0x00, 0x10, 0x00, 0x80})
+	}
+
+	// no Content-Type, so the configured format is used
+	handler.ServeHTTP(response, httptest.NewRequest("POST", "/", &body))
+	assert.Equal(http.StatusOK, response.Code)
+	assert.Equal(f.StreamContentType(), response.Header().Get("Content-Type"))
+
+	var (
+		reader  = wrp.NewStreamReader(response.Body, f)
+		results []BatchResult
+	)
+
+	for {
+		var result BatchResult
+		if err := reader.Read(&result); err != nil {
+			break
+		}
+
+		results = append(results, result)
+	}
+
+	require.Len(results, 4)
+	if f == wrp.JSON {
+		// the final line of newline-delimited JSON need not be terminated, so the last message is processed
+		assert.Equal(http.StatusOK, results[3].Status)
+	} else {
+		assert.Equal(http.StatusBadRequest, results[3].Status)
+		assert.NotEmpty(results[3].Error)
+	}
+
+	assert.Equal(http.StatusOK, results[0].Status)
+	assert.Equal(http.StatusBadRequest, results[1].Status)
+	assert.Contains(results[1].Error, "source")
+	assert.Equal(http.StatusBadRequest, results[2].Status)
+	assert.NotEmpty(results[2].Error)
+}
+
+func testBatchHandlerBadContentType(t *testing.T) {
+	var (
+		assert = assert.New(t)
+
+		handler  = NewBatchHandler(newBatchTestService())
+		response = httptest.NewRecorder()
+		request  = httptest.NewRequest("POST", "/", bytes.NewReader(wrp.MustEncode(batchTestMessages[0], wrp.Msgpack)))
+	)
+
+	request.Header.Set("Content-Type", wrp.Msgpack.ContentType())
+	handler.ServeHTTP(response, request)
+	assert.Equal(http.StatusUnsupportedMediaType, response.Code)
+}
+
+func testBatchHandlerBadAccept(t *testing.T) {
+	var (
+		assert = assert.New(t)
+
+		handler  = NewBatchHandler(newBatchTestService())
+		response = httptest.NewRecorder()
+		request  = httptest.NewRequest("POST", "/", new(bytes.Buffer))
+	)
+
+	request.Header.Set("Content-Type", wrp.JSON.StreamContentType())
+	request.Header.Set("Accept", "text/plain")
+	handler.ServeHTTP(response, request)
+	assert.Equal(http.StatusNotAcceptable, response.Code)
+}
+
+func TestBatchHandler(t *testing.T) {
+	t.Run("Nil", testBatchHandlerNil)
+	t.Run("BadContentType", testBatchHandlerBadContentType)
+	t.Run("BadAccept", testBatchHandlerBadAccept)
+
+	for _, requestFormat := range wrp.AllFormats() {
+		t.Run(requestFormat.String(), func(t *testing.T) {
+			t.Run("PartialFailure", func(t *testing.T) { testBatchHandlerPartialFailure(t, requestFormat) })
+
+			for _, responseFormat := range wrp.AllFormats() {
+				t.Run(responseFormat.String(), func(t *testing.T) {
+					testBatchHandlerClient(t, requestFormat, responseFormat)
+				})
+			}
+		})
+	}
+}
+
+func TestClientSendBatchStatusError(t *testing.T) {
+	var (
+		assert = assert.New(t)
+
+		server = httptest.NewServer(http.HandlerFunc(func(response http.ResponseWriter, request *http.Request) {
+			xhttp.WriteErrorf(response, http.StatusServiceUnavailable, "unavailable")
+		}))
+
+		client = NewClient(server.URL)
+	)
+
+	defer server.Close()
+
+	results, err := client.SendBatch(context.Background(), batchTestMessages)
+	assert.Nil(results)
+	require.IsType(t, &StatusError{}, err)
+	assert.Equal(ErrUnavailable, err.(*StatusError).Err)
+	assert.Contains(string(err.(*StatusError).Body), "unavailable")
+}
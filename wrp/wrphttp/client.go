@@ -0,0 +1,426 @@
+package wrphttp
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/Comcast/webpa-common/logging"
+	"github.com/Comcast/webpa-common/tracing"
+	"github.com/Comcast/webpa-common/wrp"
+	"github.com/Comcast/webpa-common/wrp/wrpendpoint"
+	"github.com/Comcast/webpa-common/xhttp"
+	"github.com/go-kit/kit/log/level"
+)
+
+const (
+	// StatusDeviceDisconnected is the nonstandard status returned by talaria when a device disconnects during a request
+	StatusDeviceDisconnected = 523
+
+	// StatusDeviceTimeout is the nonstandard status returned by talaria when a device does not respond in time
+	StatusDeviceTimeout = 524
+)
+
+var (
+	// ErrBadRequest indicates that the server rejected the WRP message as invalid
+	ErrBadRequest = errors.New("The WRP request was rejected as invalid")
+
+	// ErrUnauthorized indicates that the client was not authorized to send the WRP message
+	ErrUnauthorized = errors.New("The WRP request was not authorized")
+
+	// ErrNotFound indicates that the destination of the WRP message was not found, e.g. the device is not connected
+	ErrNotFound = errors.New("The WRP destination was not found")
+
+	// ErrTimeout indicates that the server or device did not respond in time
+	ErrTimeout = errors.New("The WRP request timed out")
+
+	// ErrUnavailable indicates that the server or device was unavailable
+	ErrUnavailable = errors.New("The WRP destination is unavailable")
+
+	// ErrServerError indicates that the server failed to process the WRP message
+	ErrServerError = errors.New("The server failed to process the WRP request")
+
+	// ErrUnexpectedStatus indicates a response status code that has no more specific error
+	ErrUnexpectedStatus = errors.New("Unexpected response status code")
+
+	// statusErrors maps response status codes onto the errors returned by Client
+	statusErrors = map[int]error{
+		http.StatusBadRequest:          ErrBadRequest,
+		http.StatusUnauthorized:        ErrUnauthorized,
+		http.StatusForbidden:           ErrUnauthorized,
+		http.StatusNotFound:            ErrNotFound,
+		http.StatusRequestTimeout:      ErrTimeout,
+		http.StatusGatewayTimeout:      ErrTimeout,
+		StatusDeviceTimeout:            ErrTimeout,
+		http.StatusServiceUnavailable:  ErrUnavailable,
+		StatusDeviceDisconnected:       ErrUnavailable,
+		http.StatusInternalServerError: ErrServerError,
+	}
+)
+
+// StatusError is returned by a Client when the server responds with a status code outside the 2xx range
+type StatusError struct {
+	// Code is the HTTP status code of the response
+	Code int
+
+	// Err is the error corresponding to the status code, such as ErrNotFound.  Status codes without
+	// a more specific error map to ErrServerError for 5xx codes and ErrUnexpectedStatus for everything else.
+	Err error
+
+	// Body is the entity returned by the server, which usually describes the problem
+	Body []byte
+}
+
+// newStatusError produces the StatusError for a given status code and body
+func newStatusError(code int, body []byte) *StatusError {
+	err, ok := statusErrors[code]
+	if !ok {
+		if code >= 500 {
+			err = ErrServerError
+		} else {
+			err = ErrUnexpectedStatus
+		}
+	}
+
+	return &StatusError{Code: code, Err: err, Body: body}
+}
+
+func (se *StatusError) Error() string {
+	return fmt.Sprintf("%s [status=%d]", se.Err, se.Code)
+}
+
+// StatusCode returns the HTTP status code of the response.  This method allows go-kit error encoders
+// to pass the status through.
+func (se *StatusError) StatusCode() int {
+	return se.Code
+}
+
+// ClientOption is a configurable option for a Client
+type ClientOption func(*Client)
+
+// WithClientFormat sets the format used to encode request entities.  By default, wrp.Msgpack is used.
+func WithClientFormat(f wrp.Format) ClientOption {
+	return func(c *Client) {
+		c.format = f
+	}
+}
+
+// WithAccept sets the format requested for responses via the Accept header.  By default, the request format is used.
+func WithAccept(f wrp.Format) ClientOption {
+	return func(c *Client) {
+		c.accept = &f
+	}
+}
+
+// WithHeaderMode configures the client to send WRP fields as HTTP headers, with the payload as the entity,
+// rather than encoding the entire message as the entity.
+func WithHeaderMode() ClientOption {
+	return func(c *Client) {
+		c.headerMode = true
+	}
+}
+
+// WithHTTPClient sets the xhttp.Client used to execute requests.  By default, http.DefaultClient is used.
+// If the supplied client is nil, it reverts to the default.
+func WithHTTPClient(hc xhttp.Client) ClientOption {
+	return func(c *Client) {
+		if hc != nil {
+			c.httpClient = hc
+		} else {
+			c.httpClient = http.DefaultClient
+		}
+	}
+}
+
+// WithRetry decorates the HTTP client with xhttp.RetryTransactor using the given options.  By default,
+// requests are not retried.
+func WithRetry(o xhttp.RetryOptions) ClientOption {
+	return func(c *Client) {
+		c.retry = o
+	}
+}
+
+// WithRequestHeaders sets headers, such as Authorization, that are added to every request
+func WithRequestHeaders(h http.Header) ClientOption {
+	return func(c *Client) {
+		c.requestHeaders = h
+	}
+}
+
+// WithCompression enables payload compression.  Request payloads larger than threshold bytes are compressed
+// with the given content encoding, e.g. wrp.GzipEncoding, and compressed response payloads are transparently
+// decompressed.  By default, payloads are sent and returned as is.
+func WithCompression(encoding string, threshold int) ClientOption {
+	return func(c *Client) {
+		c.compression = encoding
+		c.compressionThreshold = threshold
+	}
+}
+
+// Client sends WRP messages over HTTP to a server such as talaria or scytale
+type Client struct {
+	url            string
+	format         wrp.Format
+	accept         *wrp.Format
+	headerMode     bool
+	httpClient     xhttp.Client
+	retry          xhttp.RetryOptions
+	requestHeaders http.Header
+
+	compression          string
+	compressionThreshold int
+
+	transactor func(*http.Request) (*http.Response, error)
+}
+
+// NewClient creates a Client that POSTs WRP messages to the given URL
+func NewClient(url string, options ...ClientOption) *Client {
+	c := &Client{
+		url:        url,
+		format:     wrp.Msgpack,
+		httpClient: http.DefaultClient,
+	}
+
+	for _, o := range options {
+		o(c)
+	}
+
+	c.transactor = xhttp.RetryTransactor(c.retry, c.httpClient.Do)
+	return c
+}
+
+// acceptFormat returns the format requested for responses
+func (c *Client) acceptFormat() wrp.Format {
+	if c.accept != nil {
+		return *c.accept
+	}
+
+	return c.format
+}
+
+// compress returns a message whose payload is compressed as configured for this client.  A copy is compressed,
+// so that the caller's message is not modified.
+func (c *Client) compress(m *wrp.Message) (*wrp.Message, error) {
+	if len(c.compression) > 0 && len(m.Payload) > c.compressionThreshold && len(m.ContentEncoding) == 0 {
+		compressed := *m
+		if err := wrp.CompressPayload(&compressed, c.compression); err != nil {
+			return nil, err
+		}
+
+		return &compressed, nil
+	}
+
+	return m, nil
+}
+
+// newRequest creates the HTTP request that carries a WRP message
+func (c *Client) newRequest(ctx context.Context, m *wrp.Message) (*http.Request, error) {
+	var (
+		body   bytes.Buffer
+		header = make(http.Header)
+	)
+
+	m, err := c.compress(m)
+	if err != nil {
+		return nil, err
+	}
+
+	if c.headerMode {
+		AddMessageHeaders(header, m)
+		if _, err := WritePayload(header, &body, m); err != nil {
+			return nil, err
+		}
+	} else {
+		if err := wrp.DefaultEncoderPool(c.format).Encode(&body, m); err != nil {
+			return nil, err
+		}
+
+		header.Set("Content-Type", c.format.ContentType())
+	}
+
+	request, err := c.newHTTPRequest(ctx, body.Bytes())
+	if err != nil {
+		return nil, err
+	}
+
+	for name, values := range header {
+		request.Header[name] = values
+	}
+
+	request.Header.Set("Accept", c.acceptFormat().ContentType())
+	return request, nil
+}
+
+// newHTTPRequest creates a POST to this client's URL with the configured request headers
+func (c *Client) newHTTPRequest(ctx context.Context, body []byte) (*http.Request, error) {
+	request, err := http.NewRequest(http.MethodPost, c.url, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+
+	for name, values := range c.requestHeaders {
+		for _, v := range values {
+			request.Header.Add(name, v)
+		}
+	}
+
+	return request.WithContext(ctx), nil
+}
+
+// decodeResponse produces the WRP message carried by a successful response.  Responses with no entity
+// and no WRP headers, such as the response to an event, produce a nil message.
+func (c *Client) decodeResponse(response *http.Response, body []byte) (*wrp.Message, error) {
+	var (
+		m   *wrp.Message
+		err error
+	)
+
+	if len(response.Header.Get(MessageTypeHeader)) > 0 {
+		if m, err = NewMessageFromHeaders(response.Header, bytes.NewReader(body)); err != nil {
+			return nil, err
+		}
+	} else if len(body) == 0 {
+		return nil, nil
+	} else {
+		format, err := DetermineFormat(c.acceptFormat(), response.Header, "Content-Type")
+		if err != nil {
+			return nil, err
+		}
+
+		m = new(wrp.Message)
+		if err := decodeBytes(format, response.Header.Get("Content-Type"), m, body); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(c.compression) > 0 {
+		if err := wrp.DecompressPayload(m); err != nil {
+			return nil, err
+		}
+	}
+
+	return m, nil
+}
+
+// Send transmits a WRP message and returns the WRP response, if any.  A response with a status code outside
+// the 2xx range results in a *StatusError.
+func (c *Client) Send(ctx context.Context, m *wrp.Message) (*wrp.Message, error) {
+	request, err := c.newRequest(ctx, m)
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := c.transactor(request)
+	if err != nil {
+		return nil, err
+	}
+
+	defer response.Body.Close()
+	body, err := ioutil.ReadAll(response.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if response.StatusCode < 200 || response.StatusCode > 299 {
+		return nil, newStatusError(response.StatusCode, body)
+	}
+
+	return c.decodeResponse(response, body)
+}
+
+// SendBatch transmits several WRP messages in a single request to a batch endpoint, such as one created with
+// NewBatchHandler.  The messages are sent as a stream in this client's format, and the returned results hold
+// the outcome of each message in order.  Individual messages may fail without an error being returned from
+// this method, so callers should examine the Status of each result.  Header mode does not apply to batches.
+//
+// A response with a status code outside the 2xx range results in a *StatusError.
+func (c *Client) SendBatch(ctx context.Context, messages []*wrp.Message) ([]BatchResult, error) {
+	var (
+		body   bytes.Buffer
+		writer = wrp.NewStreamWriter(&body, c.format)
+	)
+
+	for _, m := range messages {
+		m, err := c.compress(m)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := writer.Write(m); err != nil {
+			return nil, err
+		}
+	}
+
+	request, err := c.newHTTPRequest(ctx, body.Bytes())
+	if err != nil {
+		return nil, err
+	}
+
+	request.Header.Set("Content-Type", c.format.StreamContentType())
+	request.Header.Set("Accept", c.acceptFormat().StreamContentType())
+	response, err := c.transactor(request)
+	if err != nil {
+		return nil, err
+	}
+
+	defer response.Body.Close()
+	if response.StatusCode < 200 || response.StatusCode > 299 {
+		responseBody, _ := ioutil.ReadAll(response.Body)
+		return nil, newStatusError(response.StatusCode, responseBody)
+	}
+
+	format, err := DetermineFormat(c.acceptFormat(), response.Header, "Content-Type")
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		results []BatchResult
+		reader  = wrp.NewStreamReader(response.Body, format)
+	)
+
+	for {
+		var result BatchResult
+		err := reader.Read(&result)
+		if err == io.EOF {
+			return results, nil
+		} else if err != nil {
+			return results, err
+		}
+
+		if result.Message != nil && len(c.compression) > 0 {
+			if err := wrp.DecompressPayload(result.Message); err != nil {
+				return results, err
+			}
+		}
+
+		results = append(results, result)
+	}
+}
+
+// Service adapts this client into a wrpendpoint.Service, so that a remote WRP server can be used as a downstream
+// service.  Spans carried in a response message, e.g. because the request set include_spans, are decoded into
+// the spans of the returned Response so that they can be merged by wrpendpoint.Tracing.  Spans that cannot be
+// decoded are logged and dropped.  A response with no WRP message results in a nil Response.
+func (c *Client) Service() wrpendpoint.Service {
+	return wrpendpoint.ServiceFunc(func(ctx context.Context, request wrpendpoint.Request) (wrpendpoint.Response, error) {
+		m, err := c.Send(ctx, request.Message())
+		if err != nil || m == nil {
+			return nil, err
+		}
+
+		response := wrpendpoint.WrapAsResponse(m)
+		spans, err := wrpendpoint.DecodeSpans(m.Spans)
+		if err != nil {
+			request.Logger().Log(level.Key(), level.ErrorValue(), logging.MessageKey(), "unable to decode response spans", logging.ErrorKey(), err)
+		} else if merged, ok := tracing.MergeSpans(response, spans); ok {
+			response = merged.(wrpendpoint.Response)
+		}
+
+		return response, nil
+	})
+}
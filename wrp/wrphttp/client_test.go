@@ -0,0 +1,461 @@
+package wrphttp
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/Comcast/webpa-common/logging"
+	"github.com/Comcast/webpa-common/tracing"
+	"github.com/Comcast/webpa-common/wrp"
+	"github.com/Comcast/webpa-common/wrp/wrpendpoint"
+	"github.com/Comcast/webpa-common/xhttp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// clientFunc is a function type that implements xhttp.Client
+type clientFunc func(*http.Request) (*http.Response, error)
+
+func (cf clientFunc) Do(r *http.Request) (*http.Response, error) {
+	return cf(r)
+}
+
+func testClientSendEntity(t *testing.T, requestFormat, responseFormat wrp.Format) {
+	var (
+		assert  = assert.New(t)
+		require = require.New(t)
+
+		expectedRequest = wrp.Message{
+			Type:            wrp.SimpleRequestResponseMessageType,
+			Source:          "dns:talaria.example.com",
+			Destination:     "mac:112233445566/config",
+			TransactionUUID: "1234",
+			ContentType:     "text/plain",
+			Payload:         []byte("request"),
+		}
+
+		expectedResponse = wrp.Message{
+			Type:            wrp.SimpleRequestResponseMessageType,
+			Source:          "mac:112233445566/config",
+			Destination:     "dns:talaria.example.com",
+			TransactionUUID: "1234",
+			ContentType:     "text/plain",
+			Payload:         []byte("response"),
+		}
+
+		server = httptest.NewServer(http.HandlerFunc(func(response http.ResponseWriter, request *http.Request) {
+			assert.Equal(http.MethodPost, request.Method)
+			assert.Equal(requestFormat.ContentType(), request.Header.Get("Content-Type"))
+			assert.Equal(responseFormat.ContentType(), request.Header.Get("Accept"))
+			assert.Equal("Basic dGVzdDp0ZXN0", request.Header.Get("Authorization"))
+
+			var actualRequest wrp.Message
+			assert.NoError(wrp.NewDecoder(request.Body, requestFormat).Decode(&actualRequest))
+			assert.Equal(expectedRequest, actualRequest)
+
+			response.Header().Set("Content-Type", responseFormat.ContentType())
+			wrp.NewEncoder(response, responseFormat).Encode(&expectedResponse)
+		}))
+	)
+
+	defer server.Close()
+
+	client := NewClient(
+		server.URL,
+		WithClientFormat(requestFormat),
+		WithAccept(responseFormat),
+		WithHTTPClient(nil),
+		WithRequestHeaders(http.Header{"Authorization": []string{"Basic dGVzdDp0ZXN0"}}),
+	)
+
+	require.NotNil(client)
+	actualResponse, err := client.Send(context.Background(), &expectedRequest)
+	require.NoError(err)
+	require.NotNil(actualResponse)
+	assert.Equal(expectedResponse, *actualResponse)
+}
+
+func testClientSendHeaders(t *testing.T) {
+	var (
+		assert  = assert.New(t)
+		require = require.New(t)
+
+		expectedRequest = wrp.Message{
+			Type:            wrp.SimpleRequestResponseMessageType,
+			Source:          "dns:talaria.example.com",
+			Destination:     "mac:112233445566/config",
+			TransactionUUID: "1234",
+			ContentType:     "text/plain",
+			Payload:         []byte("request"),
+		}
+
+		expectedResponse = wrp.Message{
+			Type:            wrp.SimpleRequestResponseMessageType,
+			Source:          "mac:112233445566/config",
+			Destination:     "dns:talaria.example.com",
+			TransactionUUID: "1234",
+			ContentType:     "text/plain",
+			Payload:         []byte("response"),
+		}
+
+		server = httptest.NewServer(http.HandlerFunc(func(response http.ResponseWriter, request *http.Request) {
+			assert.Equal(wrp.Msgpack.ContentType(), request.Header.Get("Accept"))
+
+			actualRequest, err := NewMessageFromHeaders(request.Header, request.Body)
+			assert.NoError(err)
+			if assert.NotNil(actualRequest) {
+				assert.Equal(expectedRequest, *actualRequest)
+			}
+
+			AddMessageHeaders(response.Header(), &expectedResponse)
+			var body bytes.Buffer
+			WritePayload(response.Header(), &body, &expectedResponse)
+			response.Write(body.Bytes())
+		}))
+	)
+
+	defer server.Close()
+
+	client := NewClient(server.URL, WithHeaderMode())
+	actualResponse, err := client.Send(context.Background(), &expectedRequest)
+	require.NoError(err)
+	require.NotNil(actualResponse)
+	assert.Equal(expectedResponse, *actualResponse)
+}
+
+func testClientSendNoContent(t *testing.T) {
+	var (
+		assert = assert.New(t)
+		server = httptest.NewServer(http.HandlerFunc(func(response http.ResponseWriter, request *http.Request) {
+			response.WriteHeader(http.StatusAccepted)
+		}))
+	)
+
+	defer server.Close()
+
+	client := NewClient(server.URL, WithClientFormat(wrp.JSON))
+	actualResponse, err := client.Send(
+		context.Background(),
+		&wrp.Message{Type: wrp.SimpleEventMessageType, Source: "dns:talaria.example.com", Destination: "event:test"},
+	)
+
+	assert.Nil(actualResponse)
+	assert.NoError(err)
+}
+
+func testClientSendStatusError(t *testing.T) {
+	testData := []struct {
+		statusCode  int
+		expectedErr error
+	}{
+		{http.StatusBadRequest, ErrBadRequest},
+		{http.StatusUnauthorized, ErrUnauthorized},
+		{http.StatusForbidden, ErrUnauthorized},
+		{http.StatusNotFound, ErrNotFound},
+		{http.StatusRequestTimeout, ErrTimeout},
+		{http.StatusGatewayTimeout, ErrTimeout},
+		{StatusDeviceTimeout, ErrTimeout},
+		{http.StatusServiceUnavailable, ErrUnavailable},
+		{StatusDeviceDisconnected, ErrUnavailable},
+		{http.StatusInternalServerError, ErrServerError},
+		{http.StatusBadGateway, ErrServerError},
+		{http.StatusConflict, ErrUnexpectedStatus},
+		{http.StatusMovedPermanently, ErrUnexpectedStatus},
+	}
+
+	for _, record := range testData {
+		t.Run(strconv.Itoa(record.statusCode), func(t *testing.T) {
+			var (
+				assert  = assert.New(t)
+				require = require.New(t)
+
+				client = NewClient(
+					"http://localhost/api",
+					WithHTTPClient(clientFunc(func(*http.Request) (*http.Response, error) {
+						return &http.Response{
+							StatusCode: record.statusCode,
+							Header:     http.Header{},
+							Body:       ioutil.NopCloser(bytes.NewBufferString("problem")),
+						}, nil
+					})),
+				)
+			)
+
+			actualResponse, err := client.Send(context.Background(), &wrp.Message{Type: wrp.SimpleEventMessageType})
+			assert.Nil(actualResponse)
+			require.Error(err)
+
+			statusError, ok := err.(*StatusError)
+			require.True(ok)
+			assert.Equal(record.statusCode, statusError.StatusCode())
+			assert.Equal(record.expectedErr, statusError.Err)
+			assert.Equal([]byte("problem"), statusError.Body)
+			assert.Contains(statusError.Error(), strconv.Itoa(record.statusCode))
+		})
+	}
+}
+
+func testClientSendRetry(t *testing.T) {
+	var (
+		assert  = assert.New(t)
+		require = require.New(t)
+
+		temporaryError = &net.DNSError{IsTemporary: true}
+		attempts       = 0
+		bodies         []string
+
+		client = NewClient(
+			"http://localhost/api",
+			WithClientFormat(wrp.JSON),
+			WithHTTPClient(clientFunc(func(request *http.Request) (*http.Response, error) {
+				attempts++
+				body, err := ioutil.ReadAll(request.Body)
+				assert.NoError(err)
+				bodies = append(bodies, string(body))
+
+				if attempts < 3 {
+					return nil, temporaryError
+				}
+
+				return &http.Response{
+					StatusCode: http.StatusOK,
+					Header:     http.Header{},
+					Body:       ioutil.NopCloser(new(bytes.Buffer)),
+				}, nil
+			})),
+			WithRetry(xhttp.RetryOptions{
+				Logger:  logging.NewTestLogger(nil, t),
+				Retries: 2,
+				Sleep:   func(time.Duration) {},
+			}),
+		)
+	)
+
+	actualResponse, err := client.Send(context.Background(), &wrp.Message{Type: wrp.SimpleEventMessageType, Source: "dns:test"})
+	assert.Nil(actualResponse)
+	require.NoError(err)
+	assert.Equal(3, attempts)
+	require.Len(bodies, 3)
+	assert.Equal(bodies[0], bodies[1])
+	assert.Equal(bodies[0], bodies[2])
+}
+
+func testClientSendTransportError(t *testing.T) {
+	var (
+		assert        = assert.New(t)
+		expectedError = errors.New("expected")
+
+		client = NewClient(
+			"http://localhost/api",
+			WithHTTPClient(clientFunc(func(*http.Request) (*http.Response, error) {
+				return nil, expectedError
+			})),
+		)
+	)
+
+	actualResponse, err := client.Send(context.Background(), &wrp.Message{Type: wrp.SimpleEventMessageType})
+	assert.Nil(actualResponse)
+	assert.Equal(expectedError, err)
+}
+
+func testClientSendBadResponse(t *testing.T) {
+	var (
+		assert = assert.New(t)
+
+		client = NewClient(
+			"http://localhost/api",
+			WithHTTPClient(clientFunc(func(*http.Request) (*http.Response, error) {
+				return &http.Response{
+					StatusCode: http.StatusOK,
+					Header:     http.Header{"Content-Type": []string{"text/plain"}},
+					Body:       ioutil.NopCloser(bytes.NewBufferString("this is not WRP")),
+				}, nil
+			})),
+		)
+	)
+
+	actualResponse, err := client.Send(context.Background(), &wrp.Message{Type: wrp.SimpleEventMessageType})
+	assert.Nil(actualResponse)
+	assert.Error(err)
+}
+
+func testClientSendCompression(t *testing.T) {
+	var (
+		assert  = assert.New(t)
+		require = require.New(t)
+
+		largePayload = bytes.Repeat([]byte("a large payload "), 100)
+		request      = wrp.Message{
+			Type:            wrp.SimpleRequestResponseMessageType,
+			Source:          "dns:talaria.example.com",
+			Destination:     "mac:112233445566/config",
+			TransactionUUID: "1234",
+			Payload:         largePayload,
+		}
+
+		server = httptest.NewServer(http.HandlerFunc(func(response http.ResponseWriter, r *http.Request) {
+			var actual wrp.Message
+			assert.NoError(wrp.NewDecoder(r.Body, wrp.Msgpack).Decode(&actual))
+			assert.Equal(wrp.GzipEncoding, actual.ContentEncoding)
+			assert.NotEqual(largePayload, actual.Payload)
+
+			// echo the compressed payload back
+			reply := actual.Response("mac:112233445566/config", 1).(*wrp.Message)
+			reply.Payload = actual.Payload
+			reply.ContentEncoding = actual.ContentEncoding
+			response.Header().Set("Content-Type", wrp.Msgpack.ContentType())
+			wrp.NewEncoder(response, wrp.Msgpack).Encode(reply)
+		}))
+	)
+
+	defer server.Close()
+
+	client := NewClient(server.URL, WithCompression(wrp.GzipEncoding, 512))
+	response, err := client.Send(context.Background(), &request)
+	require.NoError(err)
+	require.NotNil(response)
+	assert.Equal(largePayload, response.Payload)
+	assert.Empty(response.ContentEncoding)
+
+	// the caller's message must not be modified
+	assert.Equal(largePayload, request.Payload)
+	assert.Empty(request.ContentEncoding)
+}
+
+func testClientServiceSpans(t *testing.T) {
+	var (
+		assert  = assert.New(t)
+		require = require.New(t)
+
+		start   = time.Date(2018, time.March, 1, 12, 30, 15, 0, time.UTC)
+		spanner = tracing.NewSpanner(
+			tracing.Now(func() time.Time { return start }),
+			tracing.Since(func(time.Time) time.Duration { return time.Second }),
+		)
+
+		remote = wrpendpoint.ServiceFunc(func(_ context.Context, r wrpendpoint.Request) (wrpendpoint.Response, error) {
+			return wrpendpoint.WrapAsResponse(&wrp.Message{
+				Type:            wrp.SimpleRequestResponseMessageType,
+				Source:          r.Destination(),
+				TransactionUUID: r.TransactionID(),
+			}), nil
+		})
+
+		// the remote server traces its service, and the local service traces the call to the remote server
+		server = httptest.NewServer(NewHTTPHandler(ServiceHandler(wrpendpoint.Tracing(spanner)(remote))))
+		client = NewClient(server.URL)
+		local  = wrpendpoint.Tracing(spanner)(client.Service())
+
+		request = wrpendpoint.WrapAsRequest(
+			logging.NewTestLogger(nil, t),
+			(&wrp.Message{
+				Type:            wrp.SimpleRequestResponseMessageType,
+				Source:          "dns:talaria.example.com",
+				Destination:     "mac:112233445566/config",
+				TransactionUUID: "1234",
+			}).SetIncludeSpans(true),
+		)
+	)
+
+	defer server.Close()
+
+	response, err := local.ServeWRP(context.Background(), request)
+	require.NoError(err)
+	require.NotNil(response)
+
+	spans := response.Spans()
+	require.Len(spans, 2)
+	for _, s := range spans {
+		assert.Equal("mac:112233445566/config", s.Name())
+		assert.True(start.Equal(s.Start()))
+		assert.Equal(time.Second, s.Duration())
+	}
+
+	assert.Equal(wrpendpoint.EncodeSpans(spans...), response.Message().Spans)
+}
+
+func testClientServiceBadSpans(t *testing.T) {
+	var (
+		assert  = assert.New(t)
+		require = require.New(t)
+
+		server = httptest.NewServer(http.HandlerFunc(func(response http.ResponseWriter, request *http.Request) {
+			response.Header().Set("Content-Type", wrp.Msgpack.ContentType())
+			wrp.NewEncoder(response, wrp.Msgpack).Encode(&wrp.Message{
+				Type:  wrp.SimpleRequestResponseMessageType,
+				Spans: [][]string{{"not a span"}},
+			})
+		}))
+
+		client = NewClient(server.URL)
+	)
+
+	defer server.Close()
+
+	response, err := client.Service().ServeWRP(
+		context.Background(),
+		wrpendpoint.WrapAsRequest(logging.NewTestLogger(nil, t), &wrp.Message{Type: wrp.SimpleRequestResponseMessageType}),
+	)
+
+	require.NoError(err)
+	require.NotNil(response)
+	assert.Empty(response.Spans())
+}
+
+func testClientServiceError(t *testing.T) {
+	var (
+		assert = assert.New(t)
+
+		server = httptest.NewServer(http.HandlerFunc(func(response http.ResponseWriter, request *http.Request) {
+			response.WriteHeader(http.StatusNotFound)
+		}))
+
+		client = NewClient(server.URL)
+	)
+
+	defer server.Close()
+
+	response, err := client.Service().ServeWRP(
+		context.Background(),
+		wrpendpoint.WrapAsRequest(logging.NewTestLogger(nil, t), &wrp.Message{Type: wrp.SimpleRequestResponseMessageType}),
+	)
+
+	assert.Nil(response)
+	assert.IsType(&StatusError{}, err)
+}
+
+func TestClient(t *testing.T) {
+	t.Run("Send", func(t *testing.T) {
+		t.Run("Entity", func(t *testing.T) {
+			for _, requestFormat := range wrp.AllFormats() {
+				for _, responseFormat := range wrp.AllFormats() {
+					t.Run(requestFormat.String()+"/"+responseFormat.String(), func(t *testing.T) {
+						testClientSendEntity(t, requestFormat, responseFormat)
+					})
+				}
+			}
+		})
+
+		t.Run("Headers", testClientSendHeaders)
+		t.Run("NoContent", testClientSendNoContent)
+		t.Run("StatusError", testClientSendStatusError)
+		t.Run("Retry", testClientSendRetry)
+		t.Run("TransportError", testClientSendTransportError)
+		t.Run("BadResponse", testClientSendBadResponse)
+		t.Run("Compression", testClientSendCompression)
+	})
+
+	t.Run("Service", func(t *testing.T) {
+		t.Run("Spans", testClientServiceSpans)
+		t.Run("BadSpans", testClientServiceBadSpans)
+		t.Run("Error", testClientServiceError)
+	})
+}
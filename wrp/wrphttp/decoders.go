@@ -27,6 +27,18 @@ func DefaultDecoder() Decoder {
 	return defaultDecoder
 }
 
+// decodeBytes decodes a WRP entity in the given format.  Readable JSON is used when the content type requests it.
+func decodeBytes(format wrp.Format, contentType string, v interface{}, contents []byte) error {
+	if format == wrp.JSON && IsReadableJSON(contentType) {
+		return wrp.NewReadableDecoderBytes(contents).Decode(v)
+	}
+
+	return wrp.DefaultDecoderPool(format).DecodeBytes(v, contents)
+}
+
+// DecodeEntity produces a Decoder that decodes the request entity using the format indicated by the Content-Type
+// header, falling back to the given default format.  An entity whose Content-Type is ReadableJSONContentType is
+// decoded as readable JSON.
 func DecodeEntity(defaultFormat wrp.Format) Decoder {
 	return func(ctx context.Context, original *http.Request) (*Entity, error) {
 		format, err := DetermineFormat(defaultFormat, original.Header, "Content-Type")
@@ -43,7 +55,7 @@ func DecodeEntity(defaultFormat wrp.Format) Decoder {
 			Format: format,
 		}
 
-		err = wrp.NewDecoderBytes(contents, format).Decode(&entity.Message)
+		err = decodeBytes(format, original.Header.Get("Content-Type"), &entity.Message, contents)
 		return entity, err
 	}
 }
@@ -64,6 +76,77 @@ func DecodeRequestHeaders(ctx context.Context, original *http.Request) (*Entity,
 	return entity, err
 }
 
+// invalidEntity wraps a validation failure so that go-kit error encoders respond with http.StatusBadRequest
+type invalidEntity struct {
+	error
+}
+
+func (ie invalidEntity) StatusCode() int {
+	return http.StatusBadRequest
+}
+
+// ValidateEntity decorates a Decoder so that each decoded message must pass the given Validator.  A message
+// that fails validation results in an error whose StatusCode is http.StatusBadRequest.  If v is nil, the
+// decoder is returned undecorated.
+func ValidateEntity(d Decoder, v wrp.Validator) Decoder {
+	if v == nil {
+		return d
+	}
+
+	return func(ctx context.Context, original *http.Request) (*Entity, error) {
+		entity, err := d(ctx, original)
+		if err != nil {
+			return entity, err
+		}
+
+		if err := v.Validate(&entity.Message); err != nil {
+			return nil, invalidEntity{err}
+		}
+
+		return entity, nil
+	}
+}
+
+// entityTooLarge wraps a decompression failure due to size so that go-kit error encoders respond with
+// http.StatusRequestEntityTooLarge
+type entityTooLarge struct {
+	error
+}
+
+func (etl entityTooLarge) StatusCode() int {
+	return http.StatusRequestEntityTooLarge
+}
+
+// DecompressEntity decorates a Decoder so that compressed payloads are restored to their original form, as
+// with wrp.DecompressPayload.  A payload that cannot be decompressed results in an error whose StatusCode is
+// http.StatusBadRequest.  When combined with ValidateEntity, validation should be applied first, so that
+// validators such as signature verifiers see the message as it was sent.
+func DecompressEntity(d Decoder) Decoder {
+	return DecompressEntityLimit(d, wrp.DefaultMaxDecompressedSize)
+}
+
+// DecompressEntityLimit is like DecompressEntity, but permits at most maxSize bytes of decompressed payload.
+// A payload that would exceed maxSize results in an error whose StatusCode is http.StatusRequestEntityTooLarge.
+// If maxSize is nonpositive, wrp.DefaultMaxDecompressedSize is used.
+func DecompressEntityLimit(d Decoder, maxSize int) Decoder {
+	return func(ctx context.Context, original *http.Request) (*Entity, error) {
+		entity, err := d(ctx, original)
+		if err != nil {
+			return entity, err
+		}
+
+		if err := wrp.DecompressPayloadLimit(&entity.Message, maxSize); err != nil {
+			if _, ok := err.(*wrp.PayloadTooLargeError); ok {
+				return nil, entityTooLarge{err}
+			}
+
+			return nil, invalidEntity{err}
+		}
+
+		return entity, nil
+	}
+}
+
 // MessageFunc is a strategy for post-processing a WRP message, adding things to the
 // context or performing other processing on the message itself.
 type MessageFunc func(context.Context, *wrp.Message) context.Context
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"context"
 	"errors"
+	"net/http"
 	"net/http/httptest"
 	"strconv"
 	"testing"
@@ -98,8 +99,41 @@ func testDecodeEntityBodyError(t *testing.T) {
 	body.AssertExpectations(t)
 }
 
+func testDecodeEntityReadable(t *testing.T) {
+	var (
+		assert  = assert.New(t)
+		require = require.New(t)
+
+		request = httptest.NewRequest(
+			"POST",
+			"/",
+			bytes.NewBufferString(`{"msg_type": 4, "source": "foo", "dest": "bar", "content_type": "application/json", "payload": {"a": 1}}`),
+		)
+
+		decoder = DecodeEntity(wrp.Msgpack)
+	)
+
+	request.Header.Set("Content-Type", ReadableJSONContentType)
+	entity, err := decoder(context.Background(), request)
+	require.NoError(err)
+	require.NotNil(entity)
+
+	assert.Equal(wrp.JSON, entity.Format)
+	assert.Equal(
+		wrp.Message{
+			Type:        wrp.SimpleEventMessageType,
+			Source:      "foo",
+			Destination: "bar",
+			ContentType: "application/json",
+			Payload:     []byte(`{"a": 1}`),
+		},
+		entity.Message,
+	)
+}
+
 func TestDecodeEntity(t *testing.T) {
 	t.Run("Success", testDecodeEntitySuccess)
+	t.Run("Readable", testDecodeEntityReadable)
 	t.Run("InvalidContentType", testDecodeEntityInvalidContentType)
 	t.Run("BodyError", testDecodeEntityBodyError)
 }
@@ -150,3 +184,195 @@ func TestDecodeRequestHeaders(t *testing.T) {
 	t.Run("Success", testDecodeRequestHeadersSuccess)
 	t.Run("Invalid", testDecodeRequestHeadersInvalid)
 }
+
+func testValidateEntityNilValidator(t *testing.T) {
+	var (
+		assert  = assert.New(t)
+		decoder = DecodeEntity(wrp.Msgpack)
+	)
+
+	assert.NotNil(ValidateEntity(decoder, nil))
+}
+
+func testValidateEntityValid(t *testing.T) {
+	var (
+		assert  = assert.New(t)
+		require = require.New(t)
+
+		expected = wrp.Message{
+			Type:        wrp.SimpleEventMessageType,
+			Source:      "dns:foo.com",
+			Destination: "event:bar",
+		}
+
+		body    bytes.Buffer
+		request = httptest.NewRequest("POST", "/", &body)
+		decoder = ValidateEntity(DecodeEntity(wrp.Msgpack), wrp.DefaultValidator())
+	)
+
+	require.NoError(wrp.NewEncoder(&body, wrp.Msgpack).Encode(&expected))
+	entity, err := decoder(context.Background(), request)
+	assert.NoError(err)
+	require.NotNil(entity)
+	assert.Equal(expected, entity.Message)
+}
+
+func testValidateEntityInvalid(t *testing.T) {
+	var (
+		assert  = assert.New(t)
+		require = require.New(t)
+
+		invalid = wrp.Message{
+			Type:   wrp.SimpleEventMessageType,
+			Source: "dns:foo.com",
+		}
+
+		body    bytes.Buffer
+		request = httptest.NewRequest("POST", "/", &body)
+		decoder = ValidateEntity(DecodeEntity(wrp.Msgpack), wrp.DefaultValidator())
+	)
+
+	require.NoError(wrp.NewEncoder(&body, wrp.Msgpack).Encode(&invalid))
+	entity, err := decoder(context.Background(), request)
+	assert.Nil(entity)
+	require.Error(err)
+
+	statusCoder, ok := err.(interface {
+		StatusCode() int
+	})
+
+	require.True(ok)
+	assert.Equal(http.StatusBadRequest, statusCoder.StatusCode())
+}
+
+func testValidateEntityDecodeError(t *testing.T) {
+	var (
+		assert  = assert.New(t)
+		require = require.New(t)
+
+		expectedError = errors.New("expected")
+		body          = new(xhttptest.MockBody)
+		request       = httptest.NewRequest("GET", "/", body)
+		decoder       = ValidateEntity(DecodeEntity(wrp.Msgpack), wrp.DefaultValidator())
+	)
+
+	require.NotNil(decoder)
+	body.OnReadError(expectedError).Once()
+	entity, err := decoder(context.Background(), request)
+	assert.Nil(entity)
+	assert.Equal(expectedError, err)
+
+	body.AssertExpectations(t)
+}
+
+func TestValidateEntity(t *testing.T) {
+	t.Run("NilValidator", testValidateEntityNilValidator)
+	t.Run("Valid", testValidateEntityValid)
+	t.Run("Invalid", testValidateEntityInvalid)
+	t.Run("DecodeError", testValidateEntityDecodeError)
+}
+
+func testDecompressEntitySuccess(t *testing.T) {
+	var (
+		assert  = assert.New(t)
+		require = require.New(t)
+
+		expectedPayload = bytes.Repeat([]byte("compressible "), 100)
+		message         = wrp.Message{
+			Type:        wrp.SimpleEventMessageType,
+			Source:      "dns:foo.com",
+			Destination: "event:bar",
+			Payload:     expectedPayload,
+		}
+
+		body    bytes.Buffer
+		request = httptest.NewRequest("POST", "/", &body)
+		decoder = DecompressEntity(DecodeEntity(wrp.Msgpack))
+	)
+
+	require.NoError(wrp.CompressPayload(&message, wrp.GzipEncoding))
+	require.NoError(wrp.NewEncoder(&body, wrp.Msgpack).Encode(&message))
+	entity, err := decoder(context.Background(), request)
+	assert.NoError(err)
+	require.NotNil(entity)
+	assert.Equal(expectedPayload, entity.Message.Payload)
+	assert.Empty(entity.Message.ContentEncoding)
+}
+
+func testDecompressEntityCorrupt(t *testing.T) {
+	var (
+		assert  = assert.New(t)
+		require = require.New(t)
+
+		message = wrp.Message{
+			Type:            wrp.SimpleEventMessageType,
+			Payload:         []byte("this is not gzip"),
+			ContentEncoding: wrp.GzipEncoding,
+		}
+
+		body    bytes.Buffer
+		request = httptest.NewRequest("POST", "/", &body)
+		decoder = DecompressEntity(DecodeEntity(wrp.Msgpack))
+	)
+
+	require.NoError(wrp.NewEncoder(&body, wrp.Msgpack).Encode(&message))
+	entity, err := decoder(context.Background(), request)
+	assert.Nil(entity)
+	require.Error(err)
+
+	statusCoder, ok := err.(interface {
+		StatusCode() int
+	})
+
+	require.True(ok)
+	assert.Equal(http.StatusBadRequest, statusCoder.StatusCode())
+}
+
+func testDecompressEntityDecodeError(t *testing.T) {
+	var (
+		assert  = assert.New(t)
+		request = httptest.NewRequest("POST", "/", nil)
+		decoder = DecompressEntity(DecodeEntity(wrp.Msgpack))
+	)
+
+	request.Header.Set("Content-Type", "text/plain")
+	_, err := decoder(context.Background(), request)
+	assert.Error(err)
+}
+
+func testDecompressEntityTooLarge(t *testing.T) {
+	var (
+		assert  = assert.New(t)
+		require = require.New(t)
+
+		payload = bytes.Repeat([]byte("compressible "), 100)
+		message = wrp.Message{
+			Type:    wrp.SimpleEventMessageType,
+			Payload: payload,
+		}
+
+		body    bytes.Buffer
+		request = httptest.NewRequest("POST", "/", &body)
+		decoder = DecompressEntityLimit(DecodeEntity(wrp.Msgpack), len(payload)-1)
+	)
+
+	require.NoError(wrp.CompressPayload(&message, wrp.GzipEncoding))
+	require.NoError(wrp.NewEncoder(&body, wrp.Msgpack).Encode(&message))
+	entity, err := decoder(context.Background(), request)
+	assert.Nil(entity)
+	require.Error(err)
+
+	statusCoder, ok := err.(interface {
+		StatusCode() int
+	})
+
+	require.True(ok)
+	assert.Equal(http.StatusRequestEntityTooLarge, statusCoder.StatusCode())
+}
+
+func TestDecompressEntity(t *testing.T) {
+	t.Run("Success", testDecompressEntitySuccess)
+	t.Run("Corrupt", testDecompressEntityCorrupt)
+	t.Run("TooLarge", testDecompressEntityTooLarge)
+	t.Run("DecodeError", testDecompressEntityDecodeError)
+}
@@ -5,12 +5,16 @@ import (
 	"io"
 	"io/ioutil"
 	"net/http"
+	"sort"
 	"strconv"
 	"strings"
 
 	"github.com/Comcast/webpa-common/wrp"
 )
 
+// The HTTP headers that carry WRP message fields.  The payload is carried in the HTTP entity, and
+// the content type in the standard Content-Type header.  Headers, Metadata, Spans, and PartnerIDs
+// may appear multiple times.
 const (
 	MessageTypeHeader             = "X-Xmidt-Message-Type"
 	TransactionUuidHeader         = "X-Xmidt-Transaction-Uuid"
@@ -22,17 +26,67 @@ const (
 	SourceHeader                  = "X-Xmidt-Source"
 	DestinationHeader             = "X-Webpa-Device-Name"
 	AcceptHeader                  = "X-Xmidt-Accept"
+	SessionIdHeader               = "X-Xmidt-Session-Id"
+	QualityOfServiceHeader        = "X-Xmidt-Qos"
+	RequestIdHeader               = "X-Xmidt-Request-Id"
+	HeadersHeader                 = "X-Xmidt-Headers"
+	MetadataHeader                = "X-Xmidt-Metadata"
+	PartnerIdHeader               = "X-Xmidt-Partner-Id"
+	ServiceNameHeader             = "X-Xmidt-Service-Name"
+	URLHeader                     = "X-Xmidt-Url"
+	ContentEncodingHeader         = "X-Xmidt-Content-Encoding"
 )
 
 var (
 	errMissingMessageTypeHeader = fmt.Errorf("Missing %s header", MessageTypeHeader)
+
+	// legacyHeaders maps each header onto the older X-Midt-* name for the same field.  Legacy names
+	// are accepted when reading messages but are never written.
+	legacyHeaders = map[string]string{
+		MessageTypeHeader:             "X-Midt-Msg-Type",
+		TransactionUuidHeader:         "X-Midt-Transaction-Uuid",
+		StatusHeader:                  "X-Midt-Status",
+		RequestDeliveryResponseHeader: "X-Midt-Request-Delivery-Response",
+		IncludeSpansHeader:            "X-Midt-Include-Spans",
+		PathHeader:                    "X-Midt-Path",
+		SourceHeader:                  "X-Midt-Source",
+		HeadersHeader:                 "X-Midt-Headers",
+		SpanHeader:                    "X-Midt-Spans",
+	}
 )
 
+// getHeader returns the first value of the named header, falling back to its legacy name if necessary
+func getHeader(h http.Header, n string) string {
+	if value := h.Get(n); len(value) > 0 {
+		return value
+	}
+
+	if legacy, ok := legacyHeaders[n]; ok {
+		return h.Get(legacy)
+	}
+
+	return ""
+}
+
+// getHeaderValues returns all the values of the named header, falling back to its legacy name if necessary
+func getHeaderValues(h http.Header, n string) []string {
+	values := h[n]
+	if legacy, ok := legacyHeaders[n]; ok && len(values) == 0 {
+		values = h[legacy]
+	}
+
+	if len(values) > 0 {
+		return append([]string(nil), values...)
+	}
+
+	return nil
+}
+
 // getMessageType extracts the wrp.MessageType from header.  This is a required field.
 //
 // This function panics if the message type header is missing or invalid.
 func getMessageType(h http.Header) wrp.MessageType {
-	value := h.Get(MessageTypeHeader)
+	value := getHeader(h, MessageTypeHeader)
 	if len(value) == 0 {
 		panic(errMissingMessageTypeHeader)
 	}
@@ -48,7 +102,7 @@ func getMessageType(h http.Header) wrp.MessageType {
 // getIntHeader returns the header as a int64, or returns nil if the header is absent.
 // This function panics if the header is present but not a valid integer.
 func getIntHeader(h http.Header, n string) *int64 {
-	value := h.Get(n)
+	value := getHeader(h, n)
 	if len(value) == 0 {
 		return nil
 	}
@@ -62,7 +116,7 @@ func getIntHeader(h http.Header, n string) *int64 {
 }
 
 func getBoolHeader(h http.Header, n string) *bool {
-	value := h.Get(n)
+	value := getHeader(h, n)
 	if len(value) == 0 {
 		return nil
 	}
@@ -75,10 +129,70 @@ func getBoolHeader(h http.Header, n string) *bool {
 	return &b
 }
 
+// getQOSHeader returns the quality of service header as a wrp.QOSValue, or zero if the header is absent.
+// This function panics if the header is present but not a valid integer.
+func getQOSHeader(h http.Header) wrp.QOSValue {
+	value := h.Get(QualityOfServiceHeader)
+	if len(value) == 0 {
+		return 0
+	}
+
+	i, err := strconv.Atoi(value)
+	if err != nil {
+		panic(err)
+	}
+
+	return wrp.QOSValue(i)
+}
+
+// getMetadata parses the repeated key=value metadata headers.  As with partner ids, each header may also be a
+// comma-separated list of pairs, as produced when repeated headers are joined.  A segment that is not itself of
+// the form key=value continues the value of the preceding pair, so values containing commas are preserved unless
+// a comma is followed by an equals sign.  This function panics if a header does not begin with a key=value pair.
+func getMetadata(h http.Header) map[string]string {
+	values := h[MetadataHeader]
+	if len(values) == 0 {
+		return nil
+	}
+
+	metadata := make(map[string]string, len(values))
+	for _, header := range values {
+		var key, value string
+		for _, segment := range strings.Split(header, ",") {
+			if i := strings.IndexByte(segment, '='); i > 0 && len(strings.TrimSpace(segment[:i])) > 0 {
+				key, value = strings.TrimSpace(segment[:i]), segment[i+1:]
+			} else if len(key) > 0 {
+				value += "," + segment
+			} else {
+				panic(fmt.Errorf("Invalid %s header: %s", MetadataHeader, header))
+			}
+
+			metadata[key] = strings.TrimSpace(value)
+		}
+	}
+
+	return metadata
+}
+
+// getPartnerIDs returns the partner ids from the repeated partner id headers.  Each header
+// may also be a comma-separated list.
+func getPartnerIDs(h http.Header) []string {
+	var partnerIDs []string
+	for _, value := range h[PartnerIdHeader] {
+		for _, id := range strings.Split(value, ",") {
+			if id = strings.TrimSpace(id); len(id) > 0 {
+				partnerIDs = append(partnerIDs, id)
+			}
+		}
+	}
+
+	return partnerIDs
+}
+
 func getSpans(h http.Header) [][]string {
 	var spans [][]string
 
-	for _, value := range h[SpanHeader] {
+	for _, value := range getHeaderValues(h, SpanHeader) {
 		fields := strings.Split(value, ",")
 		if len(fields) != 3 {
 			panic(fmt.Errorf("Invalid %s header: %s", SpanHeader, value))
@@ -158,16 +272,25 @@ func SetMessageFromHeaders(h http.Header, m *wrp.Message) (err error) {
 	}()
 
 	m.Type = getMessageType(h)
-	m.Source = h.Get(SourceHeader)
+	m.Source = getHeader(h, SourceHeader)
 	m.Destination = h.Get(DestinationHeader)
-	m.TransactionUUID = h.Get(TransactionUuidHeader)
+	m.TransactionUUID = getHeader(h, TransactionUuidHeader)
 	m.Status = getIntHeader(h, StatusHeader)
 	m.RequestDeliveryResponse = getIntHeader(h, RequestDeliveryResponseHeader)
 	m.IncludeSpans = getBoolHeader(h, IncludeSpansHeader)
 	m.Spans = getSpans(h)
 	m.ContentType = h.Get("Content-Type")
 	m.Accept = h.Get(AcceptHeader)
-	m.Path = h.Get(PathHeader)
+	m.Path = getHeader(h, PathHeader)
+	m.Headers = getHeaderValues(h, HeadersHeader)
+	m.Metadata = getMetadata(h)
+	m.PartnerIDs = getPartnerIDs(h)
+	m.ServiceName = h.Get(ServiceNameHeader)
+	m.URL = h.Get(URLHeader)
+	m.SessionID = h.Get(SessionIdHeader)
+	m.QualityOfService = getQOSHeader(h)
+	m.RequestID = h.Get(RequestIdHeader)
+	m.ContentEncoding = h.Get(ContentEncodingHeader)
 
 	return
 }
@@ -206,6 +329,10 @@ func AddMessageHeaders(h http.Header, m *wrp.Message) {
 		h.Add(SpanHeader, strings.Join(s, ","))
 	}
 
+	if len(m.ContentType) > 0 {
+		h.Set("Content-Type", m.ContentType)
+	}
+
 	if len(m.Accept) > 0 {
 		h.Set(AcceptHeader, m.Accept)
 	}
@@ -213,6 +340,49 @@ func AddMessageHeaders(h http.Header, m *wrp.Message) {
 	if len(m.Path) > 0 {
 		h.Set(PathHeader, m.Path)
 	}
+
+	if len(m.SessionID) > 0 {
+		h.Set(SessionIdHeader, m.SessionID)
+	}
+
+	if m.QualityOfService != 0 {
+		h.Set(QualityOfServiceHeader, strconv.Itoa(int(m.QualityOfService)))
+	}
+
+	if len(m.RequestID) > 0 {
+		h.Set(RequestIdHeader, m.RequestID)
+	}
+
+	if len(m.ContentEncoding) > 0 {
+		h.Set(ContentEncodingHeader, m.ContentEncoding)
+	}
+
+	for _, v := range m.Headers {
+		h.Add(HeadersHeader, v)
+	}
+
+	// sort the metadata keys so that the headers are written in a consistent order
+	keys := make([]string, 0, len(m.Metadata))
+	for k := range m.Metadata {
+		keys = append(keys, k)
+	}
+
+	sort.Strings(keys)
+	for _, k := range keys {
+		h.Add(MetadataHeader, k+"="+m.Metadata[k])
+	}
+
+	for _, id := range m.PartnerIDs {
+		h.Add(PartnerIdHeader, id)
+	}
+
+	if len(m.ServiceName) > 0 {
+		h.Set(ServiceNameHeader, m.ServiceName)
+	}
+
+	if len(m.URL) > 0 {
+		h.Set(URLHeader, m.URL)
+	}
 }
 
 // ReadPayload extracts the payload from a reader, setting the appropriate
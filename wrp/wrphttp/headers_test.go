@@ -60,8 +60,17 @@ func testNewMessageFromHeadersSuccess(t *testing.T) {
 						"foo, bar, moo",
 						"goo, gar, hoo",
 					},
-					AcceptHeader: []string{"application/json"},
-					PathHeader:   []string{"/foo/bar"},
+					AcceptHeader:           []string{"application/json"},
+					PathHeader:             []string{"/foo/bar"},
+					SessionIdHeader:        []string{"session-1"},
+					QualityOfServiceHeader: []string{"42"},
+					RequestIdHeader:        []string{"request-1"},
+					ContentEncodingHeader:  []string{"gzip"},
+					HeadersHeader:          []string{"Header1", "Header2"},
+					MetadataHeader:         []string{"key1=value1", " key2 = value=2 "},
+					PartnerIdHeader:        []string{"comcast", "foo, bar"},
+					ServiceNameHeader:      []string{"config"},
+					URLHeader:              []string{"http://foo.com/config"},
 				},
 				payload: nil,
 				expected: wrp.Message{
@@ -76,8 +85,17 @@ func testNewMessageFromHeadersSuccess(t *testing.T) {
 						{"foo", "bar", "moo"},
 						{"goo", "gar", "hoo"},
 					},
-					Accept: "application/json",
-					Path:   "/foo/bar",
+					Accept:           "application/json",
+					Path:             "/foo/bar",
+					SessionID:        "session-1",
+					QualityOfService: 42,
+					RequestID:        "request-1",
+					ContentEncoding:  "gzip",
+					Headers:          []string{"Header1", "Header2"},
+					Metadata:         map[string]string{"key1": "value1", "key2": "value=2"},
+					PartnerIDs:       []string{"comcast", "foo", "bar"},
+					ServiceName:      "config",
+					URL:              "http://foo.com/config",
 				},
 			},
 			{
@@ -180,6 +198,101 @@ func testNewMessageFromHeadersBadSpanHeader(t *testing.T) {
 	assert.Error(err)
 }
 
+func testNewMessageFromHeadersBadMetadataHeader(t *testing.T) {
+	assert := assert.New(t)
+
+	message, err := NewMessageFromHeaders(
+		http.Header{
+			MessageTypeHeader: []string{wrp.SimpleEventMessageType.FriendlyName()},
+			MetadataHeader:    []string{"this is not a key/value pair"},
+		},
+		nil,
+	)
+
+	assert.Nil(message)
+	assert.Error(err)
+}
+
+func testNewMessageFromHeadersCommaSeparatedMetadata(t *testing.T) {
+	var (
+		assert  = assert.New(t)
+		require = require.New(t)
+	)
+
+	message, err := NewMessageFromHeaders(
+		http.Header{
+			MessageTypeHeader: []string{wrp.SimpleEventMessageType.FriendlyName()},
+			MetadataHeader:    []string{"key1=value1, key2 = value=2", "key3=a,b, c", "key4=value4"},
+			PartnerIdHeader:   []string{"comcast, foo", "bar"},
+		},
+		nil,
+	)
+
+	require.NoError(err)
+	require.NotNil(message)
+	assert.Equal(
+		map[string]string{"key1": "value1", "key2": "value=2", "key3": "a,b, c", "key4": "value4"},
+		message.Metadata,
+	)
+
+	assert.Equal([]string{"comcast", "foo", "bar"}, message.PartnerIDs)
+
+	message, err = NewMessageFromHeaders(
+		http.Header{
+			MessageTypeHeader: []string{wrp.SimpleEventMessageType.FriendlyName()},
+			MetadataHeader:    []string{"not a pair, key=value"},
+		},
+		nil,
+	)
+
+	assert.Nil(message)
+	assert.Error(err)
+}
+
+func testNewMessageFromHeadersLegacy(t *testing.T) {
+	var (
+		assert  = assert.New(t)
+		require = require.New(t)
+
+		expectedStatus                  int64 = 200
+		expectedRequestDeliveryResponse int64 = 0
+		expectedIncludeSpans                  = false
+	)
+
+	message, err := NewMessageFromHeaders(
+		http.Header{
+			"X-Midt-Msg-Type":                  []string{"SimpleRequestResponse"},
+			"X-Midt-Transaction-Uuid":          []string{"1234"},
+			"X-Midt-Status":                    []string{"200"},
+			"X-Midt-Request-Delivery-Response": []string{"0"},
+			"X-Midt-Include-Spans":             []string{"false"},
+			"X-Midt-Path":                      []string{"/foo"},
+			"X-Midt-Source":                    []string{"dns:foo.com"},
+			"X-Midt-Headers":                   []string{"Header1", "Header2"},
+			"X-Midt-Spans":                     []string{"foo, bar, moo", "goo,gar,hoo"},
+			TransactionUuidHeader:              []string{"5678"},
+		},
+		nil,
+	)
+
+	require.NoError(err)
+	require.NotNil(message)
+	assert.Equal(
+		wrp.Message{
+			Type:                    wrp.SimpleRequestResponseMessageType,
+			TransactionUUID:         "5678",
+			Status:                  &expectedStatus,
+			RequestDeliveryResponse: &expectedRequestDeliveryResponse,
+			IncludeSpans:            &expectedIncludeSpans,
+			Path:                    "/foo",
+			Source:                  "dns:foo.com",
+			Headers:                 []string{"Header1", "Header2"},
+			Spans:                   [][]string{{"foo", "bar", "moo"}, {"goo", "gar", "hoo"}},
+		},
+		*message,
+	)
+}
+
 func testNewMessageFromHeadersBadPayload(t *testing.T) {
 	var (
 		assert = assert.New(t)
@@ -208,6 +321,7 @@ func TestNewMessageFromHeaders(t *testing.T) {
 	t.Run("BadIntHeader", func(t *testing.T) {
 		testNewMessageFromHeadersBadIntHeader(t, StatusHeader)
 		testNewMessageFromHeadersBadIntHeader(t, RequestDeliveryResponseHeader)
+		testNewMessageFromHeadersBadIntHeader(t, QualityOfServiceHeader)
 	})
 
 	t.Run("BadBoolHeader", func(t *testing.T) {
@@ -215,7 +329,10 @@ func TestNewMessageFromHeaders(t *testing.T) {
 	})
 
 	t.Run("BadSpanHeader", testNewMessageFromHeadersBadSpanHeader)
+	t.Run("BadMetadataHeader", testNewMessageFromHeadersBadMetadataHeader)
+	t.Run("CommaSeparatedMetadata", testNewMessageFromHeadersCommaSeparatedMetadata)
 	t.Run("BadPayload", testNewMessageFromHeadersBadPayload)
+	t.Run("Legacy", testNewMessageFromHeadersLegacy)
 }
 
 func TestAddMessageHeaders(t *testing.T) {
@@ -250,6 +367,16 @@ func TestAddMessageHeaders(t *testing.T) {
 					Spans:                   [][]string{{"foo", "bar", "graar"}},
 					Accept:                  "application/json",
 					Path:                    "/foo/bar",
+					SessionID:               "session-1",
+					QualityOfService:        wrp.QOSCriticalValue,
+					RequestID:               "request-1",
+					ContentEncoding:         "gzip",
+					ContentType:             "text/plain",
+					Headers:                 []string{"Header1", "Header2"},
+					Metadata:                map[string]string{"b": "2", "a": "1"},
+					PartnerIDs:              []string{"comcast", "foo"},
+					ServiceName:             "config",
+					URL:                     "http://foo.com/config",
 				},
 				expected: http.Header{
 					MessageTypeHeader:             []string{wrp.SimpleRequestResponseMessageType.FriendlyName()},
@@ -262,6 +389,16 @@ func TestAddMessageHeaders(t *testing.T) {
 					SpanHeader:                    []string{"foo,bar,graar"},
 					AcceptHeader:                  []string{"application/json"},
 					PathHeader:                    []string{"/foo/bar"},
+					SessionIdHeader:               []string{"session-1"},
+					QualityOfServiceHeader:        []string{"75"},
+					RequestIdHeader:               []string{"request-1"},
+					ContentEncodingHeader:         []string{"gzip"},
+					"Content-Type":                []string{"text/plain"},
+					HeadersHeader:                 []string{"Header1", "Header2"},
+					MetadataHeader:                []string{"a=1", "b=2"},
+					PartnerIdHeader:               []string{"comcast", "foo"},
+					ServiceNameHeader:             []string{"config"},
+					URLHeader:                     []string{"http://foo.com/config"},
 				},
 			},
 		}
@@ -275,6 +412,74 @@ func TestAddMessageHeaders(t *testing.T) {
 	}
 }
 
+func TestMessageHeadersRoundTrip(t *testing.T) {
+	var (
+		expectedStatus                  int64 = 200
+		expectedRequestDeliveryResponse int64 = 1
+		expectedIncludeSpans                  = true
+
+		messages = []wrp.Message{
+			{
+				Type: wrp.ServiceAliveMessageType,
+			},
+			{
+				Type:        wrp.SimpleEventMessageType,
+				Source:      "mac:112233445566",
+				Destination: "event:device-status",
+				ContentType: "application/json",
+				Payload:     []byte(`{"hello": "world"}`),
+			},
+			{
+				Type:                    wrp.SimpleRequestResponseMessageType,
+				Source:                  "dns:talaria.comcast.com",
+				Destination:             "mac:112233445566/config",
+				TransactionUUID:         "1-2-3-4",
+				ContentType:             "text/plain",
+				Accept:                  "application/json",
+				Status:                  &expectedStatus,
+				RequestDeliveryResponse: &expectedRequestDeliveryResponse,
+				Headers:                 []string{"Header1", "Header2"},
+				Metadata:                map[string]string{"/boot-time": "1234", "/hw-model": "foo", "/tags": "a,b"},
+				Spans:                   [][]string{{"span1", "1", "2"}, {"span2", "3", "4"}},
+				IncludeSpans:            &expectedIncludeSpans,
+				Path:                    "/foo/bar",
+				Payload:                 []byte("payload"),
+				PartnerIDs:              []string{"comcast", "foo"},
+				SessionID:               "session-1",
+				QualityOfService:        wrp.QOSHighValue,
+				RequestID:               "request-1",
+				ContentEncoding:         "gzip",
+			},
+			{
+				Type:        wrp.ServiceRegistrationMessageType,
+				ServiceName: "config",
+				URL:         "tcp://127.0.0.1:6666",
+			},
+		}
+	)
+
+	for _, original := range messages {
+		t.Run(original.Type.FriendlyName(), func(t *testing.T) {
+			var (
+				assert  = assert.New(t)
+				require = require.New(t)
+
+				header = make(http.Header)
+				body   bytes.Buffer
+			)
+
+			AddMessageHeaders(header, &original)
+			_, err := WritePayload(header, &body, &original)
+			require.NoError(err)
+
+			actual, err := NewMessageFromHeaders(header, &body)
+			require.NoError(err)
+			require.NotNil(actual)
+			assert.Equal(original, *actual)
+		})
+	}
+}
+
 func testWritePayloadEmptyPayload(t *testing.T) {
 	assert := assert.New(t)
 
@@ -2,11 +2,41 @@ package wrphttp
 
 import (
 	"context"
+	"mime"
 	"net/http"
+	"strings"
 
 	"github.com/Comcast/webpa-common/wrp"
 )
 
+const (
+	// ReadablePayloadParameter is the media type parameter a client uses to request readable JSON, where textual
+	// and JSON payloads are embedded in the message rather than base64 encoded.  See wrp.ToReadableJSON.
+	ReadablePayloadParameter = "payload"
+
+	// ReadablePayload is the value of ReadablePayloadParameter that requests readable JSON
+	ReadablePayload = "readable"
+
+	// ReadableJSONContentType is the media type of WRP messages encoded as readable JSON.  Clients send this
+	// as the Accept header to request readable responses, and as the Content-Type of readable requests.
+	ReadableJSONContentType = "application/json; " + ReadablePayloadParameter + "=" + ReadablePayload
+)
+
+// IsReadableJSON tests if a Content-Type or Accept value requests readable JSON, e.g. application/json; payload=readable.
+// For an Accept header with several media ranges, only the first range is examined.
+func IsReadableJSON(value string) bool {
+	if comma := strings.IndexByte(value, ','); comma >= 0 {
+		value = value[:comma]
+	}
+
+	mediaType, params, err := mime.ParseMediaType(value)
+	if err != nil || !strings.Contains(mediaType, "json") {
+		return false
+	}
+
+	return params[ReadablePayloadParameter] == ReadablePayload
+}
+
 // DetermineFormat examines zero or more headers to determine which WRP format is to be used, either
 // for decoding or encoding.  The headers are tried in order, and the first non-empty value that maps
 // to a WRP format is returned.  Any non-empty header that is invalid results in an error.  If none of
@@ -84,7 +114,7 @@ func DefaultResponseWriterFunc() ResponseWriterFunc {
 
 // NewEntityResponseWriter creates a ResponseWriterFunc that returns an entity-based ResponseWriter.  The returned
 // ResponseWriter writes WRP messages to the response body, using content negotation with a fallback to the supplied
-// default format.
+// default format.  A client that requests ReadableJSONContentType receives readable JSON.
 func NewEntityResponseWriter(defaultFormat wrp.Format) ResponseWriterFunc {
 	return func(httpResponse http.ResponseWriter, wrpRequest *Request) (ResponseWriter, error) {
 		format, err := DetermineFormat(defaultFormat, wrpRequest.Original.Header, "Accept")
@@ -95,6 +125,7 @@ func NewEntityResponseWriter(defaultFormat wrp.Format) ResponseWriterFunc {
 		return &entityResponseWriter{
 			ResponseWriter: httpResponse,
 			f:              format,
+			readable:       format == wrp.JSON && IsReadableJSON(wrpRequest.Original.Header.Get("Accept")),
 		}, nil
 	}
 }
@@ -102,16 +133,23 @@ func NewEntityResponseWriter(defaultFormat wrp.Format) ResponseWriterFunc {
 // entityResponseWriter provides ResponseWriter behavior that marshals WRP messages into the HTTP entity (body)
 type entityResponseWriter struct {
 	http.ResponseWriter
-	f wrp.Format
+	f        wrp.Format
+	readable bool
 }
 
 func (erw *entityResponseWriter) WriteWRP(v interface{}) (int, error) {
-	var (
-		output  []byte
-		encoder = wrp.NewEncoderBytes(&output, erw.f)
-	)
+	if erw.readable {
+		var output []byte
+		if err := wrp.NewReadableEncoderBytes(&output).Encode(v); err != nil {
+			return 0, err
+		}
+
+		erw.ResponseWriter.Header().Set("Content-Type", ReadableJSONContentType)
+		return erw.ResponseWriter.Write(output)
+	}
 
-	if err := encoder.Encode(v); err != nil {
+	output, err := wrp.DefaultEncoderPool(erw.f).EncodeBytes(v)
+	if err != nil {
 		return 0, err
 	}
 
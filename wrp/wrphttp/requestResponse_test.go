@@ -119,8 +119,64 @@ func testEntityResponseWriterSuccess(t *testing.T, defaultFormat, expectedFormat
 	assert.Equal(*expected, *actual)
 }
 
+func testEntityResponseWriterReadable(t *testing.T) {
+	var (
+		assert  = assert.New(t)
+		require = require.New(t)
+
+		erw          = NewEntityResponseWriter(wrp.Msgpack)
+		httpResponse = httptest.NewRecorder()
+		wrpRequest   = &Request{
+			Original: httptest.NewRequest("POST", "/", nil),
+		}
+
+		expected = &wrp.Message{
+			Type:        wrp.SimpleRequestResponseMessageType,
+			ContentType: "application/json",
+			Payload:     []byte(`{"value": "hi there"}`),
+		}
+	)
+
+	wrpRequest.Original.Header.Set("Accept", ReadableJSONContentType)
+	wrpResponse, err := erw(httpResponse, wrpRequest)
+	require.NoError(err)
+	require.NotNil(wrpResponse)
+
+	_, err = wrpResponse.WriteWRP(expected)
+	require.NoError(err)
+	assert.Equal(ReadableJSONContentType, httpResponse.Header().Get("Content-Type"))
+	assert.Contains(httpResponse.Body.String(), `"payload":{"value": "hi there"}`)
+
+	actual := new(wrp.Message)
+	assert.NoError(wrp.NewReadableDecoder(httpResponse.Body).Decode(actual))
+	assert.Equal(*expected, *actual)
+}
+
+func TestIsReadableJSON(t *testing.T) {
+	testData := []struct {
+		value    string
+		expected bool
+	}{
+		{"", false},
+		{"application/json", false},
+		{"application/msgpack; payload=readable", false},
+		{"application/json; payload=base64", false},
+		{ReadableJSONContentType, true},
+		{"application/json;payload=readable;charset=utf-8", true},
+		{"application/json; payload=readable, application/msgpack", true},
+		{"asd;lfkjasdfkjasdfkjasdf", false},
+	}
+
+	for _, record := range testData {
+		t.Run(record.value, func(t *testing.T) {
+			assert.Equal(t, record.expected, IsReadableJSON(record.value))
+		})
+	}
+}
+
 func TestEntityResponseWriter(t *testing.T) {
 	t.Run("InvalidAccept", testEntityResponseWriterInvalidAccept)
+	t.Run("Readable", testEntityResponseWriterReadable)
 
 	t.Run("Success", func(t *testing.T) {
 		for _, defaultFormat := range wrp.AllFormats() {
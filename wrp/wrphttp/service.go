@@ -0,0 +1,48 @@
+package wrphttp
+
+import (
+	"net/http"
+
+	"github.com/Comcast/webpa-common/logging"
+	"github.com/Comcast/webpa-common/wrp/wrpendpoint"
+	"github.com/Comcast/webpa-common/xhttp"
+	"github.com/go-kit/kit/log/level"
+	gokithttp "github.com/go-kit/kit/transport/http"
+)
+
+// ServiceHandler adapts a wrpendpoint.Service, such as a wrpendpoint.Mux, into a Handler.  The decoded entity is
+// passed to the service using a logger obtained from the request context.  A non-nil response is written with
+// WriteWRP, while a nil response results in a 204.  Errors that expose a StatusCode() int method use that status,
+// and all other errors result in a 500.
+func ServiceHandler(s wrpendpoint.Service) Handler {
+	if s == nil {
+		panic("A WRP Service is required")
+	}
+
+	return HandlerFunc(func(w ResponseWriter, r *Request) {
+		var (
+			ctx     = r.Context()
+			request = wrpendpoint.WrapAsRequest(logging.GetLogger(ctx), &r.Entity.Message)
+		)
+
+		response, err := s.ServeWRP(ctx, request)
+		if err != nil {
+			code := http.StatusInternalServerError
+			if sc, ok := err.(gokithttp.StatusCoder); ok {
+				code = sc.StatusCode()
+			}
+
+			xhttp.WriteError(w, code, err)
+			return
+		}
+
+		if response == nil || response.Message() == nil {
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+
+		if _, err := w.WriteWRP(response.Message()); err != nil {
+			request.Logger().Log(level.Key(), level.ErrorValue(), logging.MessageKey(), "unable to write WRP response", logging.ErrorKey(), err)
+		}
+	})
+}
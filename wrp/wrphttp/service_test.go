@@ -0,0 +1,129 @@
+package wrphttp
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Comcast/webpa-common/wrp"
+	"github.com/Comcast/webpa-common/wrp/wrpendpoint"
+	"github.com/Comcast/webpa-common/xhttp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testServiceHandlerNil(t *testing.T) {
+	assert := assert.New(t)
+	assert.Panics(func() {
+		ServiceHandler(nil)
+	})
+}
+
+func testServiceHandlerMux(t *testing.T) {
+	var (
+		assert  = assert.New(t)
+		require = require.New(t)
+
+		mux     = wrpendpoint.NewMux()
+		handler = NewHTTPHandler(ServiceHandler(mux))
+	)
+
+	mux.HandleFunc("config/**", func(_ context.Context, r wrpendpoint.Request) (wrpendpoint.Response, error) {
+		return wrpendpoint.WrapAsResponse(&wrp.Message{
+			Type:            wrp.SimpleRequestResponseMessageType,
+			Source:          r.Destination(),
+			TransactionUUID: r.TransactionID(),
+			Payload:         []byte("config"),
+		}), nil
+	})
+
+	for destination, expectedStatus := range map[string]int64{"mac:112233445566/config/wifi": 0, "mac:112233445566/nosuch": http.StatusNotFound} {
+		t.Run(destination, func(t *testing.T) {
+			var (
+				body     []byte
+				response = httptest.NewRecorder()
+			)
+
+			require.NoError(wrp.NewEncoderBytes(&body, wrp.Msgpack).Encode(&wrp.Message{
+				Type:            wrp.SimpleRequestResponseMessageType,
+				Source:          "dns:talaria.example.com",
+				Destination:     destination,
+				TransactionUUID: "1234",
+			}))
+
+			handler.ServeHTTP(response, httptest.NewRequest("POST", "/", bytes.NewReader(body)))
+			assert.Equal(http.StatusOK, response.Code)
+
+			var actual wrp.Message
+			require.NoError(wrp.NewDecoder(response.Body, wrp.Msgpack).Decode(&actual))
+			assert.Equal(destination, actual.Source)
+			assert.Equal("1234", actual.TransactionUUID)
+
+			if expectedStatus > 0 {
+				require.NotNil(actual.Status)
+				assert.Equal(expectedStatus, *actual.Status)
+			} else {
+				assert.Nil(actual.Status)
+				assert.Equal([]byte("config"), actual.Payload)
+			}
+		})
+	}
+}
+
+func testServiceHandlerNoResponse(t *testing.T) {
+	var (
+		assert  = assert.New(t)
+		handler = ServiceHandler(wrpendpoint.ServiceFunc(func(context.Context, wrpendpoint.Request) (wrpendpoint.Response, error) {
+			return nil, nil
+		}))
+
+		response = httptest.NewRecorder()
+	)
+
+	handler.ServeWRP(
+		&entityResponseWriter{ResponseWriter: response, f: wrp.Msgpack},
+		&Request{Original: httptest.NewRequest("POST", "/", nil), Entity: new(Entity)},
+	)
+
+	assert.Equal(http.StatusNoContent, response.Code)
+}
+
+func testServiceHandlerError(t *testing.T) {
+	testData := []struct {
+		err          error
+		expectedCode int
+	}{
+		{errors.New("expected"), http.StatusInternalServerError},
+		{&xhttp.Error{Code: http.StatusServiceUnavailable, Text: "expected"}, http.StatusServiceUnavailable},
+	}
+
+	for _, record := range testData {
+		t.Run(record.err.Error(), func(t *testing.T) {
+			var (
+				assert  = assert.New(t)
+				handler = ServiceHandler(wrpendpoint.ServiceFunc(func(context.Context, wrpendpoint.Request) (wrpendpoint.Response, error) {
+					return nil, record.err
+				}))
+
+				response = httptest.NewRecorder()
+			)
+
+			handler.ServeWRP(
+				&entityResponseWriter{ResponseWriter: response, f: wrp.Msgpack},
+				&Request{Original: httptest.NewRequest("POST", "/", nil), Entity: new(Entity)},
+			)
+
+			assert.Equal(record.expectedCode, response.Code)
+		})
+	}
+}
+
+func TestServiceHandler(t *testing.T) {
+	t.Run("Nil", testServiceHandlerNil)
+	t.Run("Mux", testServiceHandlerMux)
+	t.Run("NoResponse", testServiceHandlerNoResponse)
+	t.Run("Error", testServiceHandlerError)
+}
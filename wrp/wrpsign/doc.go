@@ -0,0 +1,13 @@
+/*
+Package wrpsign provides message-level integrity protection for WRP messages.  Signatures are computed over a canonical
+encoding of a message and are carried in the message's metadata, so signed messages pass unchanged through any
+WRP format and through the HTTP header representation of a message.
+
+A message's UnknownFields are not part of the canonical encoding, so they are not covered by its signature.  An
+intermediary may add, change, or remove unknown fields without invalidating the signature, and verifiers must not
+rely on them.
+
+A Verifier is a wrp.Validator.  It can be used to verify messages decoded over HTTP via wrphttp.ValidateEntity,
+and to verify messages read from devices via device.Options.Validator.
+*/
+package wrpsign
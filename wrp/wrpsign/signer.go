@@ -0,0 +1,251 @@
+package wrpsign
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"sort"
+	"strconv"
+
+	"github.com/Comcast/webpa-common/secure/key"
+	"github.com/Comcast/webpa-common/wrp"
+)
+
+const (
+	// SignatureKey is the metadata key that holds the base64-encoded signature of a message
+	SignatureKey = "wrp-signature"
+
+	// AlgorithmKey is the metadata key that holds the algorithm used to sign a message, e.g. HS256
+	AlgorithmKey = "wrp-signature-alg"
+
+	// KeyIDKey is the metadata key that holds the identifier of the key used to sign a message
+	KeyIDKey = "wrp-signature-kid"
+
+	// HS256 is the algorithm name for HMAC using SHA-256
+	HS256 = "HS256"
+
+	// RS256 is the algorithm name for RSASSA-PKCS1-v1_5 using SHA-256
+	RS256 = "RS256"
+
+	// ES256 is the algorithm name for ECDSA using SHA-256.  Signatures are the fixed-width
+	// concatenation of r and s, as with JWS.
+	ES256 = "ES256"
+)
+
+var (
+	// ErrNoPrivateKey indicates that a key Pair supplied for signing has no private key
+	ErrNoPrivateKey = errors.New("A private key is required for signing")
+
+	// ErrUnsupportedKey indicates that a key was not an RSA or ECDSA key
+	ErrUnsupportedKey = errors.New("Only RSA and ECDSA keys are supported")
+)
+
+// signatureKeys are the metadata keys excluded from the canonical form of a message
+var signatureKeys = map[string]bool{
+	SignatureKey: true,
+	AlgorithmKey: true,
+	KeyIDKey:     true,
+}
+
+// canonicalWriter accumulates length-prefixed values, so that no two distinct messages
+// produce the same canonical bytes
+type canonicalWriter struct {
+	bytes.Buffer
+}
+
+func (cw *canonicalWriter) writeBytes(v []byte) {
+	var length [binary.MaxVarintLen64]byte
+	cw.Write(length[:binary.PutUvarint(length[:], uint64(len(v)))])
+	cw.Write(v)
+}
+
+func (cw *canonicalWriter) writeStrings(values ...string) {
+	cw.writeBytes([]byte(strconv.Itoa(len(values))))
+	for _, v := range values {
+		cw.writeBytes([]byte(v))
+	}
+}
+
+func formatInt(v *int64) string {
+	if v == nil {
+		return ""
+	}
+
+	return strconv.FormatInt(*v, 10)
+}
+
+func formatBool(v *bool) string {
+	if v == nil {
+		return ""
+	}
+
+	return strconv.FormatBool(*v)
+}
+
+// Canonical produces the bytes that are signed for a message.  Every field of the message is included
+// in a fixed order, with metadata sorted by key.  The signature metadata entries are excluded, so the
+// canonical form of a message is the same before and after signing.  UnknownFields are not included, as
+// their decoded form can vary between formats, so they are not covered by the signature.
+func Canonical(m *wrp.Message) []byte {
+	var (
+		cw       canonicalWriter
+		metadata = make([]string, 0, 2*len(m.Metadata))
+		keys     = make([]string, 0, len(m.Metadata))
+	)
+
+	for k := range m.Metadata {
+		if !signatureKeys[k] {
+			keys = append(keys, k)
+		}
+	}
+
+	sort.Strings(keys)
+	for _, k := range keys {
+		metadata = append(metadata, k, m.Metadata[k])
+	}
+
+	cw.writeStrings(
+		strconv.FormatInt(int64(m.Type), 10),
+		m.Source,
+		m.Destination,
+		m.TransactionUUID,
+		m.ContentType,
+		m.Accept,
+		formatInt(m.Status),
+		formatInt(m.RequestDeliveryResponse),
+		formatBool(m.IncludeSpans),
+		m.Path,
+		m.ServiceName,
+		m.URL,
+		m.SessionID,
+		strconv.FormatInt(int64(m.QualityOfService), 10),
+		m.RequestID,
+		m.ContentEncoding,
+	)
+
+	cw.writeStrings(m.Headers...)
+	cw.writeStrings(metadata...)
+	cw.writeStrings(strconv.Itoa(len(m.Spans)))
+	for _, span := range m.Spans {
+		cw.writeStrings(span...)
+	}
+
+	cw.writeStrings(m.PartnerIDs...)
+	cw.writeBytes(m.Payload)
+	return cw.Bytes()
+}
+
+// digest produces the SHA-256 hash of the canonical form of a message
+func digest(m *wrp.Message) []byte {
+	d := sha256.Sum256(Canonical(m))
+	return d[:]
+}
+
+// Signer signs WRP messages
+type Signer interface {
+	// Sign computes the signature of a message and stores it, along with the algorithm and key id,
+	// in the message's metadata.  Any existing signature is replaced.
+	Sign(*wrp.Message) error
+}
+
+// signerFunc computes the raw signature for a message digest or, for HMAC, the canonical bytes
+type signerFunc func(*wrp.Message) ([]byte, error)
+
+type signer struct {
+	keyID     string
+	algorithm string
+	sign      signerFunc
+}
+
+func (s *signer) Sign(m *wrp.Message) error {
+	signature, err := s.sign(m)
+	if err != nil {
+		return err
+	}
+
+	if m.Metadata == nil {
+		m.Metadata = make(map[string]string, 3)
+	}
+
+	m.Metadata[AlgorithmKey] = s.algorithm
+	m.Metadata[SignatureKey] = base64.StdEncoding.EncodeToString(signature)
+	if len(s.keyID) > 0 {
+		m.Metadata[KeyIDKey] = s.keyID
+	} else {
+		delete(m.Metadata, KeyIDKey)
+	}
+
+	return nil
+}
+
+// hmacSignature computes the HMAC-SHA256 of the canonical form of a message
+func hmacSignature(secret []byte, m *wrp.Message) []byte {
+	h := hmac.New(sha256.New, secret)
+	h.Write(Canonical(m))
+	return h.Sum(nil)
+}
+
+// NewHMACSigner produces a Signer that uses HMAC-SHA256 with a shared secret.  The key id is optional,
+// and allows a Verifier to select among several secrets.
+func NewHMACSigner(keyID string, secret []byte) Signer {
+	return &signer{
+		keyID:     keyID,
+		algorithm: HS256,
+		sign: func(m *wrp.Message) ([]byte, error) {
+			return hmacSignature(secret, m), nil
+		},
+	}
+}
+
+// NewSigner produces a Signer that uses the private key of a key Pair, such as one obtained from a
+// key.Resolver configured with key.PurposeSign.  RSA keys produce RS256 signatures, while ECDSA keys
+// produce ES256 signatures.
+func NewSigner(keyID string, pair key.Pair) (Signer, error) {
+	if !pair.HasPrivate() {
+		return nil, ErrNoPrivateKey
+	}
+
+	switch privateKey := pair.Private().(type) {
+	case *rsa.PrivateKey:
+		return &signer{
+			keyID:     keyID,
+			algorithm: RS256,
+			sign: func(m *wrp.Message) ([]byte, error) {
+				return rsa.SignPKCS1v15(rand.Reader, privateKey, crypto.SHA256, digest(m))
+			},
+		}, nil
+
+	case *ecdsa.PrivateKey:
+		return &signer{
+			keyID:     keyID,
+			algorithm: ES256,
+			sign: func(m *wrp.Message) ([]byte, error) {
+				r, s, err := ecdsa.Sign(rand.Reader, privateKey, digest(m))
+				if err != nil {
+					return nil, err
+				}
+
+				var (
+					size      = (privateKey.Curve.Params().BitSize + 7) / 8
+					signature = make([]byte, 2*size)
+					rBytes    = r.Bytes()
+					sBytes    = s.Bytes()
+				)
+
+				copy(signature[size-len(rBytes):size], rBytes)
+				copy(signature[2*size-len(sBytes):], sBytes)
+				return signature, nil
+			},
+		}, nil
+
+	default:
+		return nil, ErrUnsupportedKey
+	}
+}
@@ -0,0 +1,176 @@
+package wrpsign
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"testing"
+
+	"github.com/Comcast/webpa-common/secure/key"
+	"github.com/Comcast/webpa-common/wrp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	testRSAKey   *rsa.PrivateKey
+	testECDSAKey *ecdsa.PrivateKey
+)
+
+func init() {
+	var err error
+	if testRSAKey, err = rsa.GenerateKey(rand.Reader, 2048); err != nil {
+		panic(err)
+	}
+
+	if testECDSAKey, err = ecdsa.GenerateKey(elliptic.P256(), rand.Reader); err != nil {
+		panic(err)
+	}
+}
+
+func newTestMessage() *wrp.Message {
+	var (
+		status int64 = 200
+		rdr    int64 = 1
+	)
+
+	return &wrp.Message{
+		Type:                    wrp.SimpleRequestResponseMessageType,
+		Source:                  "dns:partner.example.com",
+		Destination:             "mac:112233445566/config",
+		TransactionUUID:         "1234",
+		ContentType:             "application/json",
+		Status:                  &status,
+		RequestDeliveryResponse: &rdr,
+		Headers:                 []string{"X-Foo: bar"},
+		Metadata:                map[string]string{"/boot-time": "1234", "/fw-name": "fw"},
+		Spans:                   [][]string{{"span", "1", "2"}},
+		PartnerIDs:              []string{"comcast"},
+		QualityOfService:        wrp.QOSHighValue,
+		Payload:                 []byte(`{"foo": "bar"}`),
+	}
+}
+
+// newPair produces a mock key Pair for the given private key
+func newPair(privateKey interface{}, publicKey interface{}) *key.MockPair {
+	pair := new(key.MockPair)
+	pair.On("HasPrivate").Return(privateKey != nil).Maybe()
+	pair.On("Private").Return(privateKey).Maybe()
+	pair.On("Public").Return(publicKey).Maybe()
+	return pair
+}
+
+func TestCanonical(t *testing.T) {
+	var (
+		assert   = assert.New(t)
+		original = newTestMessage()
+		expected = Canonical(original)
+	)
+
+	assert.NotEmpty(expected)
+	assert.Equal(expected, Canonical(newTestMessage()))
+
+	signed := newTestMessage()
+	signed.Metadata[SignatureKey] = "signature"
+	signed.Metadata[AlgorithmKey] = HS256
+	signed.Metadata[KeyIDKey] = "kid"
+	assert.Equal(expected, Canonical(signed), "signature metadata should be excluded")
+
+	for name, modify := range map[string]func(*wrp.Message){
+		"Type":        func(m *wrp.Message) { m.Type = wrp.SimpleEventMessageType },
+		"Destination": func(m *wrp.Message) { m.Destination = "mac:112233445566/other" },
+		"Status":      func(m *wrp.Message) { m.Status = nil },
+		"Metadata":    func(m *wrp.Message) { m.Metadata["/boot-time"] = "5678" },
+		"Spans":       func(m *wrp.Message) { m.Spans = nil },
+		"PartnerIDs":  func(m *wrp.Message) { m.PartnerIDs = []string{"other"} },
+		"QOS":         func(m *wrp.Message) { m.QualityOfService = wrp.QOSLowValue },
+		"Payload":     func(m *wrp.Message) { m.Payload = []byte("tampered") },
+		"Boundaries":  func(m *wrp.Message) { m.Headers = []string{"X-Foo:", " bar"} },
+	} {
+		modified := newTestMessage()
+		modify(modified)
+		assert.NotEqual(expected, Canonical(modified), name)
+	}
+}
+
+func testNewSignerRSA(t *testing.T) {
+	var (
+		assert  = assert.New(t)
+		require = require.New(t)
+
+		message = newTestMessage()
+	)
+
+	signer, err := NewSigner("rsa", newPair(testRSAKey, &testRSAKey.PublicKey))
+	require.NoError(err)
+	require.NotNil(signer)
+
+	require.NoError(signer.Sign(message))
+	assert.Equal(RS256, message.Metadata[AlgorithmKey])
+	assert.Equal("rsa", message.Metadata[KeyIDKey])
+	assert.NotEmpty(message.Metadata[SignatureKey])
+	assert.Equal("1234", message.Metadata["/boot-time"])
+}
+
+func testNewSignerECDSA(t *testing.T) {
+	var (
+		assert  = assert.New(t)
+		require = require.New(t)
+
+		message = &wrp.Message{Type: wrp.SimpleEventMessageType}
+	)
+
+	signer, err := NewSigner("", newPair(testECDSAKey, &testECDSAKey.PublicKey))
+	require.NoError(err)
+	require.NotNil(signer)
+
+	require.NoError(signer.Sign(message))
+	assert.Equal(ES256, message.Metadata[AlgorithmKey])
+	assert.NotContains(message.Metadata, KeyIDKey)
+	assert.NotEmpty(message.Metadata[SignatureKey])
+}
+
+func testNewSignerNoPrivateKey(t *testing.T) {
+	assert := assert.New(t)
+
+	signer, err := NewSigner("rsa", newPair(nil, &testRSAKey.PublicKey))
+	assert.Nil(signer)
+	assert.Equal(ErrNoPrivateKey, err)
+}
+
+func testNewSignerUnsupportedKey(t *testing.T) {
+	assert := assert.New(t)
+
+	signer, err := NewSigner("rsa", newPair("not a key", nil))
+	assert.Nil(signer)
+	assert.Equal(ErrUnsupportedKey, err)
+}
+
+func TestNewSigner(t *testing.T) {
+	t.Run("RSA", testNewSignerRSA)
+	t.Run("ECDSA", testNewSignerECDSA)
+	t.Run("NoPrivateKey", testNewSignerNoPrivateKey)
+	t.Run("UnsupportedKey", testNewSignerUnsupportedKey)
+}
+
+func TestNewHMACSigner(t *testing.T) {
+	var (
+		assert  = assert.New(t)
+		require = require.New(t)
+
+		message = newTestMessage()
+		signer  = NewHMACSigner("shared", []byte("secret"))
+	)
+
+	require.NotNil(signer)
+	require.NoError(signer.Sign(message))
+	assert.Equal(HS256, message.Metadata[AlgorithmKey])
+	assert.Equal("shared", message.Metadata[KeyIDKey])
+
+	first := message.Metadata[SignatureKey]
+	assert.NotEmpty(first)
+
+	require.NoError(signer.Sign(message))
+	assert.Equal(first, message.Metadata[SignatureKey], "resigning should produce the same signature")
+}
@@ -0,0 +1,172 @@
+package wrpsign
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/hmac"
+	"crypto/rsa"
+	"encoding/base64"
+	"errors"
+	"math/big"
+
+	"github.com/Comcast/webpa-common/secure/key"
+	"github.com/Comcast/webpa-common/wrp"
+)
+
+var (
+	// ErrMissingSignature indicates that a message was not signed
+	ErrMissingSignature = errors.New("message is not signed")
+
+	// ErrInvalidSignature indicates that a message's signature did not match its contents
+	ErrInvalidSignature = errors.New("invalid signature")
+
+	// ErrUnsupportedAlgorithm indicates that a message was signed with an algorithm the Verifier does not accept
+	ErrUnsupportedAlgorithm = errors.New("unsupported signature algorithm")
+
+	// ErrUnknownKey indicates that the key used to sign a message could not be found
+	ErrUnknownKey = errors.New("unknown signing key")
+)
+
+// VerifierOption is a configurable option for a Verifier
+type VerifierOption func(*Verifier)
+
+// WithSecret adds an HMAC secret for the given key id.  Messages signed with HS256 are verified using the
+// secret that matches their key id.  The empty key id is used for messages that do not carry a key id.
+func WithSecret(keyID string, secret []byte) VerifierOption {
+	return func(v *Verifier) {
+		v.secrets[keyID] = secret
+	}
+}
+
+// WithResolver sets the key.Resolver used to obtain public keys for messages signed with RS256 or ES256.
+// The message's key id is passed to the resolver.  Typically, the resolver is configured with key.PurposeVerify.
+func WithResolver(r key.Resolver) VerifierOption {
+	return func(v *Verifier) {
+		v.resolver = r
+	}
+}
+
+// WithOptionalSignature allows messages that are not signed to pass verification.  Messages that are
+// signed must still carry a valid signature.  By default, all messages must be signed.
+func WithOptionalSignature() VerifierOption {
+	return func(v *Verifier) {
+		v.optional = true
+	}
+}
+
+// Verifier checks the signatures of WRP messages.  A Verifier is a wrp.Validator, and so can be used anywhere
+// messages are validated.  Failures are reported as *wrp.FieldError instances for the metadata field.
+type Verifier struct {
+	secrets  map[string][]byte
+	resolver key.Resolver
+	optional bool
+}
+
+// NewVerifier creates a Verifier with the given options.  Only algorithms for which keys have been configured,
+// via WithSecret or WithResolver, are accepted.
+func NewVerifier(options ...VerifierOption) *Verifier {
+	v := &Verifier{
+		secrets: make(map[string][]byte),
+	}
+
+	for _, o := range options {
+		o(v)
+	}
+
+	return v
+}
+
+func metadataError(err error) error {
+	return &wrp.FieldError{Field: "metadata", Err: err}
+}
+
+// Validate verifies the signature of a message
+func (v *Verifier) Validate(m *wrp.Message) error {
+	encoded, ok := m.Metadata[SignatureKey]
+	if !ok {
+		if v.optional {
+			return nil
+		}
+
+		return metadataError(ErrMissingSignature)
+	}
+
+	signature, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return metadataError(ErrInvalidSignature)
+	}
+
+	keyID := m.Metadata[KeyIDKey]
+	switch m.Metadata[AlgorithmKey] {
+	case HS256:
+		secret, ok := v.secrets[keyID]
+		if !ok {
+			if len(v.secrets) == 0 {
+				return metadataError(ErrUnsupportedAlgorithm)
+			}
+
+			return metadataError(ErrUnknownKey)
+		}
+
+		if !hmac.Equal(signature, hmacSignature(secret, m)) {
+			return metadataError(ErrInvalidSignature)
+		}
+
+		return nil
+
+	case RS256, ES256:
+		if v.resolver == nil {
+			return metadataError(ErrUnsupportedAlgorithm)
+		}
+
+		pair, err := v.resolver.ResolveKey(keyID)
+		if err != nil || pair == nil {
+			return metadataError(ErrUnknownKey)
+		}
+
+		return v.verifyPublic(m.Metadata[AlgorithmKey], pair.Public(), signature, digest(m))
+
+	default:
+		return metadataError(ErrUnsupportedAlgorithm)
+	}
+}
+
+// verifyPublic checks a signature using a public key, which must match the algorithm
+func (v *Verifier) verifyPublic(algorithm string, publicKey interface{}, signature, hashed []byte) error {
+	switch publicKey := publicKey.(type) {
+	case *rsa.PublicKey:
+		if algorithm != RS256 {
+			return metadataError(ErrUnsupportedAlgorithm)
+		}
+
+		if rsa.VerifyPKCS1v15(publicKey, crypto.SHA256, hashed, signature) != nil {
+			return metadataError(ErrInvalidSignature)
+		}
+
+		return nil
+
+	case *ecdsa.PublicKey:
+		if algorithm != ES256 {
+			return metadataError(ErrUnsupportedAlgorithm)
+		}
+
+		size := (publicKey.Curve.Params().BitSize + 7) / 8
+		if len(signature) != 2*size {
+			return metadataError(ErrInvalidSignature)
+		}
+
+		var (
+			r = new(big.Int).SetBytes(signature[:size])
+			s = new(big.Int).SetBytes(signature[size:])
+		)
+
+		if !ecdsa.Verify(publicKey, hashed, r, s) {
+			return metadataError(ErrInvalidSignature)
+		}
+
+		return nil
+
+	default:
+		return metadataError(ErrUnsupportedKey)
+	}
+}
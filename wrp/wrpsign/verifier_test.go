@@ -0,0 +1,162 @@
+package wrpsign
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/Comcast/webpa-common/secure/key"
+	"github.com/Comcast/webpa-common/wrp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// assertFieldError asserts that err is a metadata *wrp.FieldError with the given cause
+func assertFieldError(assert *assert.Assertions, expected error, err error) {
+	if fe, ok := err.(*wrp.FieldError); assert.True(ok, "expected a *wrp.FieldError, got %v", err) {
+		assert.Equal("metadata", fe.Field)
+		assert.Equal(expected, fe.Err)
+	}
+}
+
+func testVerifierHMAC(t *testing.T) {
+	var (
+		assert  = assert.New(t)
+		require = require.New(t)
+
+		verifier = NewVerifier(WithSecret("shared", []byte("secret")), WithSecret("", []byte("default")))
+	)
+
+	message := newTestMessage()
+	require.NoError(NewHMACSigner("shared", []byte("secret")).Sign(message))
+	assert.NoError(verifier.Validate(message))
+
+	message = newTestMessage()
+	require.NoError(NewHMACSigner("", []byte("default")).Sign(message))
+	assert.NoError(verifier.Validate(message))
+
+	message = newTestMessage()
+	require.NoError(NewHMACSigner("shared", []byte("wrong")).Sign(message))
+	assertFieldError(assert, ErrInvalidSignature, verifier.Validate(message))
+
+	message = newTestMessage()
+	require.NoError(NewHMACSigner("nosuch", []byte("secret")).Sign(message))
+	assertFieldError(assert, ErrUnknownKey, verifier.Validate(message))
+
+	message = newTestMessage()
+	require.NoError(NewHMACSigner("shared", []byte("secret")).Sign(message))
+	message.Payload = []byte("tampered")
+	assertFieldError(assert, ErrInvalidSignature, verifier.Validate(message))
+}
+
+func testVerifierPublicKey(t *testing.T) {
+	var (
+		assert  = assert.New(t)
+		require = require.New(t)
+
+		resolver = new(key.MockResolver)
+		verifier = NewVerifier(WithResolver(resolver))
+	)
+
+	resolver.On("ResolveKey", "rsa").Return(newPair(nil, &testRSAKey.PublicKey), nil)
+	resolver.On("ResolveKey", "ecdsa").Return(newPair(nil, &testECDSAKey.PublicKey), nil)
+	resolver.On("ResolveKey", "nosuch").Return(nil, errors.New("expected"))
+
+	rsaSigner, err := NewSigner("rsa", newPair(testRSAKey, &testRSAKey.PublicKey))
+	require.NoError(err)
+
+	ecdsaSigner, err := NewSigner("ecdsa", newPair(testECDSAKey, &testECDSAKey.PublicKey))
+	require.NoError(err)
+
+	for _, signer := range []Signer{rsaSigner, ecdsaSigner} {
+		message := newTestMessage()
+		require.NoError(signer.Sign(message))
+		assert.NoError(verifier.Validate(message))
+
+		message.Destination = "mac:665544332211/config"
+		assertFieldError(assert, ErrInvalidSignature, verifier.Validate(message))
+	}
+
+	message := newTestMessage()
+	require.NoError(rsaSigner.Sign(message))
+	message.Metadata[KeyIDKey] = "ecdsa"
+	assertFieldError(assert, ErrUnsupportedAlgorithm, verifier.Validate(message))
+
+	message.Metadata[KeyIDKey] = "nosuch"
+	assertFieldError(assert, ErrUnknownKey, verifier.Validate(message))
+
+	message = newTestMessage()
+	require.NoError(ecdsaSigner.Sign(message))
+	message.Metadata[KeyIDKey] = "rsa"
+	assertFieldError(assert, ErrUnsupportedAlgorithm, verifier.Validate(message))
+
+	resolver.AssertExpectations(t)
+}
+
+func testVerifierUnsigned(t *testing.T) {
+	var (
+		assert = assert.New(t)
+
+		required = NewVerifier(WithSecret("", []byte("secret")))
+		optional = NewVerifier(WithSecret("", []byte("secret")), WithOptionalSignature())
+	)
+
+	assertFieldError(assert, ErrMissingSignature, required.Validate(newTestMessage()))
+	assert.NoError(optional.Validate(newTestMessage()))
+
+	message := newTestMessage()
+	message.Metadata[SignatureKey] = "invalid"
+	message.Metadata[AlgorithmKey] = HS256
+	assertFieldError(assert, ErrInvalidSignature, optional.Validate(message))
+}
+
+func testVerifierUnsupportedAlgorithm(t *testing.T) {
+	var (
+		assert  = assert.New(t)
+		require = require.New(t)
+
+		verifier = NewVerifier()
+	)
+
+	message := newTestMessage()
+	require.NoError(NewHMACSigner("", []byte("secret")).Sign(message))
+	assertFieldError(assert, ErrUnsupportedAlgorithm, verifier.Validate(message))
+
+	rsaSigner, err := NewSigner("rsa", newPair(testRSAKey, &testRSAKey.PublicKey))
+	require.NoError(err)
+	message = newTestMessage()
+	require.NoError(rsaSigner.Sign(message))
+	assertFieldError(assert, ErrUnsupportedAlgorithm, verifier.Validate(message))
+
+	message.Metadata[AlgorithmKey] = "none"
+	assertFieldError(assert, ErrUnsupportedAlgorithm, verifier.Validate(message))
+}
+
+func testVerifierValidators(t *testing.T) {
+	var (
+		assert  = assert.New(t)
+		require = require.New(t)
+
+		validator = wrp.Validators{wrp.DefaultValidator(), NewVerifier(WithSecret("", []byte("secret")))}
+		message   = newTestMessage()
+	)
+
+	require.NoError(NewHMACSigner("", []byte("secret")).Sign(message))
+	assert.NoError(validator.Validate(message))
+
+	message.Payload = []byte("tampered")
+	err := validator.Validate(message)
+	require.Error(err)
+
+	ve, ok := err.(*wrp.ValidationError)
+	require.True(ok)
+	require.Len(ve.Errors, 1)
+	assertFieldError(assert, ErrInvalidSignature, ve.Errors[0])
+}
+
+func TestVerifier(t *testing.T) {
+	t.Run("HMAC", testVerifierHMAC)
+	t.Run("PublicKey", testVerifierPublicKey)
+	t.Run("Unsigned", testVerifierUnsigned)
+	t.Run("UnsupportedAlgorithm", testVerifierUnsupportedAlgorithm)
+	t.Run("Validators", testVerifierValidators)
+}
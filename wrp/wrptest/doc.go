@@ -0,0 +1,9 @@
+/*
+Package wrptest provides helpers for testing code that produces or consumes WRP messages.
+
+A Generator produces random, valid messages of each type.  Matchers compare messages either exactly, ignoring
+the difference between nil and empty collections, or partially, considering only the fields of an expected
+message that are set.  Golden file helpers compare encoded messages against files kept under testdata, and
+a fuzz harness exercises the WRP decoders with a generated seed corpus.
+*/
+package wrptest
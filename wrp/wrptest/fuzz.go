@@ -0,0 +1,160 @@
+package wrptest
+
+import (
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+
+	"github.com/Comcast/webpa-common/wrp"
+	"github.com/stretchr/testify/assert"
+)
+
+// Fuzz is a fuzz harness over wrp.NewDecoderBytes for the given format.  Input that cannot be decoded is
+// rejected by returning 0.  Input that can be decoded must survive re-encoding:  once decoded and re-encoded,
+// a message must decode and encode again to the same message.  If not, this function panics.  Otherwise,
+// 1 is returned so that the input is given priority, following the go-fuzz convention.
+func Fuzz(data []byte, f wrp.Format) int {
+	var first wrp.Message
+	if err := wrp.NewDecoderBytes(data, f).Decode(&first); err != nil {
+		return 0
+	}
+
+	second := reencode(&first, f)
+	third := reencode(second, f)
+	if !messagesEqual(second, third) {
+		panic(fmt.Errorf("Unstable %s encoding\nfirst : %#v\nsecond: %#v", f, second, third))
+	}
+
+	return 1
+}
+
+// FuzzMsgpack is a go-fuzz entry point for the Msgpack format, e.g. go-fuzz-build -func FuzzMsgpack
+func FuzzMsgpack(data []byte) int {
+	return Fuzz(data, wrp.Msgpack)
+}
+
+// FuzzJSON is a go-fuzz entry point for the JSON format, e.g. go-fuzz-build -func FuzzJSON
+func FuzzJSON(data []byte) int {
+	return Fuzz(data, wrp.JSON)
+}
+
+// reencode encodes and decodes a message, panicking on any error
+func reencode(m *wrp.Message, f wrp.Format) *wrp.Message {
+	var encoded []byte
+	if err := wrp.NewEncoderBytes(&encoded, f).Encode(m); err != nil {
+		panic(fmt.Errorf("Unable to encode decoded %s message: %s\nmessage: %#v", f, err, m))
+	}
+
+	decoded := new(wrp.Message)
+	if err := wrp.NewDecoderBytes(encoded, f).Decode(decoded); err != nil {
+		panic(fmt.Errorf("Unable to decode re-encoded %s message: %s\nencoded: %x", f, err, encoded))
+	}
+
+	return decoded
+}
+
+// messagesEqual compares messages after normalization.  Unknown fields are compared by their printed form,
+// since they can hold values such as NaN that are never deeply equal.
+func messagesEqual(a, b *wrp.Message) bool {
+	a, b = Normalize(a), Normalize(b)
+	if fmt.Sprint(a.UnknownFields) != fmt.Sprint(b.UnknownFields) {
+		return false
+	}
+
+	a.UnknownFields, b.UnknownFields = nil, nil
+	return reflect.DeepEqual(a, b)
+}
+
+// Corpus produces a seed corpus of encoded, random messages of every type
+func Corpus(seed int64, count int, f wrp.Format) [][]byte {
+	var (
+		generator = NewGenerator(seed)
+		corpus    = make([][]byte, 0, count)
+	)
+
+	for i := 0; i < count; i++ {
+		corpus = append(corpus, wrp.MustEncode(generator.AnyMessage(), f))
+	}
+
+	return corpus
+}
+
+// WriteCorpus writes each corpus entry to its own file in dir, which is the layout of a go-fuzz corpus directory
+func WriteCorpus(dir string, corpus [][]byte) error {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+
+	for i, entry := range corpus {
+		if err := ioutil.WriteFile(filepath.Join(dir, "seed-"+strconv.Itoa(i)), entry, 0644); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Mutate produces a random variation of an entry by truncating it, flipping bits, or overwriting bytes
+// with values that are significant to the WRP formats.  The original entry is not modified.
+func Mutate(random *rand.Rand, entry []byte) []byte {
+	mutated := append([]byte(nil), entry...)
+	if len(mutated) == 0 {
+		return []byte{byte(random.Intn(256))}
+	}
+
+	switch random.Intn(3) {
+	case 0:
+		return mutated[:random.Intn(len(mutated))]
+	case 1:
+		for i := random.Intn(4); i >= 0; i-- {
+			mutated[random.Intn(len(mutated))] ^= 1 << uint(random.Intn(8))
+		}
+	default:
+		interesting := []byte{0x00, 0x7f, 0x80, 0xc0, 0xc1, 0xdc, 0xdd, 0xde, 0xdf, 0xff, '"', '{', '}', '[', ']', ':', ','}
+		mutated[random.Intn(len(mutated))] = interesting[random.Intn(len(interesting))]
+	}
+
+	return mutated
+}
+
+// AssertFuzz feeds each corpus entry, along with the given number of random mutations of each entry, to Fuzz.
+// Any panic is reported as an assertion failure that includes the offending input.  The corpus entries
+// themselves must be decodable.
+func AssertFuzz(t assert.TestingT, f wrp.Format, corpus [][]byte, seed int64, mutations int) bool {
+	var (
+		random = rand.New(rand.NewSource(seed))
+		result = true
+	)
+
+	run := func(input []byte) (accepted int, ok bool) {
+		defer func() {
+			if r := recover(); r != nil {
+				assert.Fail(t, fmt.Sprintf("Fuzz panicked: %v\ninput: %s", r, hex.EncodeToString(input)))
+				ok = false
+			}
+		}()
+
+		return Fuzz(input, f), true
+	}
+
+	for _, entry := range corpus {
+		accepted, ok := run(entry)
+		if ok && accepted == 0 {
+			assert.Fail(t, fmt.Sprintf("Corpus entry was not decodable: %s", hex.EncodeToString(entry)))
+			ok = false
+		}
+
+		result = result && ok
+		for i := 0; i < mutations; i++ {
+			_, ok = run(Mutate(random, entry))
+			result = result && ok
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,85 @@
+package wrptest
+
+import (
+	"io/ioutil"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Comcast/webpa-common/wrp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testFuzzCorpus(t *testing.T, f wrp.Format) {
+	corpus := Corpus(1234, 50, f)
+	assert.Len(t, corpus, 50)
+	AssertFuzz(t, f, corpus, 5678, 100)
+}
+
+func testFuzzRejects(t *testing.T) {
+	assert := assert.New(t)
+	assert.Equal(0, FuzzMsgpack(nil))
+	assert.Equal(0, FuzzJSON([]byte("this is not JSON")))
+	assert.Equal(1, FuzzJSON([]byte(`{"msg_type": 4, "source": "mac:112233445566"}`)))
+	assert.Equal(1, FuzzMsgpack(wrp.MustEncode(&wrp.ServiceAlive{}, wrp.Msgpack)))
+}
+
+func testFuzzAssertReportsUndecodable(t *testing.T) {
+	var (
+		assert   = assert.New(t)
+		recorder = new(recordingT)
+	)
+
+	assert.False(AssertFuzz(recorder, wrp.JSON, [][]byte{[]byte("not json")}, 1, 0))
+	assert.Len(recorder.failures, 1)
+}
+
+func testMutate(t *testing.T) {
+	var (
+		assert = assert.New(t)
+		random = rand.New(rand.NewSource(1))
+		entry  = []byte("original entry")
+	)
+
+	assert.Len(Mutate(random, nil), 1)
+	for repeat := 0; repeat < 100; repeat++ {
+		mutated := Mutate(random, entry)
+		assert.True(len(mutated) <= len(entry))
+	}
+
+	assert.Equal([]byte("original entry"), entry)
+}
+
+func testWriteCorpus(t *testing.T) {
+	var (
+		assert  = assert.New(t)
+		require = require.New(t)
+	)
+
+	dir, err := ioutil.TempDir("", "wrptest")
+	require.NoError(err)
+	defer os.RemoveAll(dir)
+
+	corpus := Corpus(1, 3, wrp.Msgpack)
+	require.NoError(WriteCorpus(filepath.Join(dir, "corpus"), corpus))
+	for i, entry := range corpus {
+		written, err := ioutil.ReadFile(filepath.Join(dir, "corpus", "seed-"+string('0'+rune(i))))
+		require.NoError(err)
+		assert.Equal(entry, written)
+	}
+}
+
+func TestFuzz(t *testing.T) {
+	for _, f := range wrp.AllFormats() {
+		t.Run(f.String(), func(t *testing.T) {
+			testFuzzCorpus(t, f)
+		})
+	}
+
+	t.Run("Rejects", testFuzzRejects)
+	t.Run("AssertReportsUndecodable", testFuzzAssertReportsUndecodable)
+	t.Run("Mutate", testMutate)
+	t.Run("WriteCorpus", testWriteCorpus)
+}
@@ -0,0 +1,289 @@
+package wrptest
+
+import (
+	"fmt"
+	"math/rand"
+
+	"github.com/Comcast/webpa-common/wrp"
+)
+
+var (
+	contentTypes = []string{"application/json", "application/msgpack", "text/plain", "application/octet-stream"}
+	services     = []string{"config", "iot", "parodus", "aker", "webpa"}
+	partnerIDs   = []string{"comcast", "cox", "shaw", "rogers"}
+	authStatuses = []int64{200, 401, 403}
+)
+
+// Generator produces random WRP messages for tests.  Each message produced for a given type passes
+// wrp.DefaultValidator, and optional fields are randomly set or left unset.  A Generator is deterministic
+// for a given seed, so that failures can be reproduced.
+//
+// A Generator is not safe for concurrent use.
+type Generator struct {
+	random *rand.Rand
+}
+
+// NewGenerator creates a Generator using the given random seed
+func NewGenerator(seed int64) *Generator {
+	return &Generator{
+		random: rand.New(rand.NewSource(seed)),
+	}
+}
+
+func (g *Generator) chance() bool {
+	return g.random.Intn(2) == 0
+}
+
+func (g *Generator) pick(values []string) string {
+	return values[g.random.Intn(len(values))]
+}
+
+func (g *Generator) hex(length int) string {
+	const digits = "0123456789abcdef"
+	b := make([]byte, length)
+	for i := range b {
+		b[i] = digits[g.random.Intn(len(digits))]
+	}
+
+	return string(b)
+}
+
+// MAC produces a random device identifier, e.g. mac:112233445566
+func (g *Generator) MAC() string {
+	return "mac:" + g.hex(12)
+}
+
+// DeviceLocator produces a random locator for a service on a device, e.g. mac:112233445566/config
+func (g *Generator) DeviceLocator() string {
+	return g.MAC() + "/" + g.pick(services)
+}
+
+// ServerLocator produces a random locator for a server-side service, e.g. dns:talaria-3.example.com/api
+func (g *Generator) ServerLocator() string {
+	return fmt.Sprintf("dns:%s-%d.example.com/%s", g.pick(services), g.random.Intn(10), g.pick(services))
+}
+
+// UUID produces a random, version 4 style UUID suitable for transaction keys
+func (g *Generator) UUID() string {
+	return fmt.Sprintf("%s-%s-4%s-%s-%s", g.hex(8), g.hex(4), g.hex(3), g.hex(4), g.hex(12))
+}
+
+// Payload produces a random payload along with a matching content type.  The payload may be empty.
+func (g *Generator) Payload() ([]byte, string) {
+	contentType := g.pick(contentTypes)
+	switch contentType {
+	case "application/json":
+		return []byte(fmt.Sprintf(`{"id": "%s", "value": %d}`, g.hex(8), g.random.Intn(1000))), contentType
+	case "text/plain":
+		return []byte("payload " + g.hex(g.random.Intn(64))), contentType
+	}
+
+	payload := make([]byte, g.random.Intn(256))
+	g.random.Read(payload)
+	if len(payload) == 0 {
+		return nil, ""
+	}
+
+	return payload, contentType
+}
+
+func (g *Generator) metadata() map[string]string {
+	if g.chance() {
+		return nil
+	}
+
+	metadata := make(map[string]string)
+	for i := g.random.Intn(4); i >= 0; i-- {
+		metadata["/"+g.hex(6)] = g.hex(8)
+	}
+
+	return metadata
+}
+
+func (g *Generator) partnerIDs() []string {
+	if g.chance() {
+		return nil
+	}
+
+	return []string{g.pick(partnerIDs)}
+}
+
+func (g *Generator) headers() []string {
+	if g.chance() {
+		return nil
+	}
+
+	return []string{"X-" + g.hex(4) + ": " + g.hex(8)}
+}
+
+func (g *Generator) optionalString(value func() string) string {
+	if g.chance() {
+		return ""
+	}
+
+	return value()
+}
+
+// Message produces a random message of the given type.  This method panics if the type is not a
+// known WRP message type.
+func (g *Generator) Message(mt wrp.MessageType) *wrp.Message {
+	m := &wrp.Message{Type: mt}
+
+	switch mt {
+	case wrp.AuthMessageType:
+		m.SetStatus(authStatuses[g.random.Intn(len(authStatuses))])
+
+	case wrp.SimpleRequestResponseMessageType,
+		wrp.CreateMessageType, wrp.RetrieveMessageType, wrp.UpdateMessageType, wrp.DeleteMessageType:
+		m.Source = g.ServerLocator()
+		m.Destination = g.DeviceLocator()
+		m.TransactionUUID = g.UUID()
+		m.Payload, m.ContentType = g.Payload()
+		m.Headers = g.headers()
+		m.Metadata = g.metadata()
+		m.PartnerIDs = g.partnerIDs()
+		m.SessionID = g.optionalString(g.UUID)
+		m.RequestID = g.optionalString(g.UUID)
+		if mt == wrp.SimpleRequestResponseMessageType {
+			m.Accept = g.optionalString(func() string { return g.pick(contentTypes) })
+		} else {
+			m.Path = "/" + g.hex(8)
+		}
+
+		if g.chance() {
+			m.SetStatus(int64(200 + g.random.Intn(400)))
+		}
+
+		if g.chance() {
+			m.SetRequestDeliveryResponse(int64(g.random.Intn(10)))
+		}
+
+	case wrp.SimpleEventMessageType:
+		device := g.MAC()
+		m.Source = device + "/" + g.pick(services)
+		m.Destination = "event:device-status/" + device + "/" + g.hex(6)
+		m.Payload, m.ContentType = g.Payload()
+		m.Headers = g.headers()
+		m.Metadata = g.metadata()
+		m.PartnerIDs = g.partnerIDs()
+		m.SessionID = g.optionalString(g.UUID)
+		m.QualityOfService = wrp.QOSValue(g.random.Intn(100))
+
+	case wrp.ServiceRegistrationMessageType:
+		m.ServiceName = g.pick(services)
+		m.URL = fmt.Sprintf("tcp://127.0.0.1:%d", 1024+g.random.Intn(60000))
+
+	case wrp.ServiceAliveMessageType:
+
+	default:
+		panic(fmt.Errorf("Unsupported message type: %s", mt))
+	}
+
+	return m
+}
+
+// MessageTypes returns the message types supported by Generator.Message
+func MessageTypes() []wrp.MessageType {
+	return []wrp.MessageType{
+		wrp.AuthMessageType,
+		wrp.SimpleRequestResponseMessageType,
+		wrp.SimpleEventMessageType,
+		wrp.CreateMessageType,
+		wrp.RetrieveMessageType,
+		wrp.UpdateMessageType,
+		wrp.DeleteMessageType,
+		wrp.ServiceRegistrationMessageType,
+		wrp.ServiceAliveMessageType,
+	}
+}
+
+// AnyMessage produces a random message of a random type
+func (g *Generator) AnyMessage() *wrp.Message {
+	types := MessageTypes()
+	return g.Message(types[g.random.Intn(len(types))])
+}
+
+// Authorization produces a random Authorization message
+func (g *Generator) Authorization() *wrp.Authorization {
+	m := g.Message(wrp.AuthMessageType)
+	return &wrp.Authorization{
+		Type:   m.Type,
+		Status: *m.Status,
+	}
+}
+
+// SimpleRequestResponse produces a random SimpleRequestResponse message
+func (g *Generator) SimpleRequestResponse() *wrp.SimpleRequestResponse {
+	m := g.Message(wrp.SimpleRequestResponseMessageType)
+	return &wrp.SimpleRequestResponse{
+		Type:                    m.Type,
+		Source:                  m.Source,
+		Destination:             m.Destination,
+		ContentType:             m.ContentType,
+		Accept:                  m.Accept,
+		TransactionUUID:         m.TransactionUUID,
+		Status:                  m.Status,
+		RequestDeliveryResponse: m.RequestDeliveryResponse,
+		Headers:                 m.Headers,
+		Metadata:                m.Metadata,
+		Payload:                 m.Payload,
+		PartnerIDs:              m.PartnerIDs,
+		SessionID:               m.SessionID,
+		RequestID:               m.RequestID,
+	}
+}
+
+// SimpleEvent produces a random SimpleEvent message
+func (g *Generator) SimpleEvent() *wrp.SimpleEvent {
+	m := g.Message(wrp.SimpleEventMessageType)
+	return &wrp.SimpleEvent{
+		Type:             m.Type,
+		Source:           m.Source,
+		Destination:      m.Destination,
+		ContentType:      m.ContentType,
+		Headers:          m.Headers,
+		Metadata:         m.Metadata,
+		Payload:          m.Payload,
+		PartnerIDs:       m.PartnerIDs,
+		SessionID:        m.SessionID,
+		QualityOfService: m.QualityOfService,
+	}
+}
+
+// CRUD produces a random CRUD message of the given type.  This method panics if the type is not
+// one of the CRUD message types.
+func (g *Generator) CRUD(mt wrp.MessageType) *wrp.CRUD {
+	switch mt {
+	case wrp.CreateMessageType, wrp.RetrieveMessageType, wrp.UpdateMessageType, wrp.DeleteMessageType:
+	default:
+		panic(fmt.Errorf("Not a CRUD message type: %s", mt))
+	}
+
+	m := g.Message(mt)
+	return &wrp.CRUD{
+		Type:                    m.Type,
+		Source:                  m.Source,
+		Destination:             m.Destination,
+		TransactionUUID:         m.TransactionUUID,
+		ContentType:             m.ContentType,
+		Headers:                 m.Headers,
+		Metadata:                m.Metadata,
+		Status:                  m.Status,
+		RequestDeliveryResponse: m.RequestDeliveryResponse,
+		Path:                    m.Path,
+		Payload:                 m.Payload,
+		PartnerIDs:              m.PartnerIDs,
+		SessionID:               m.SessionID,
+		RequestID:               m.RequestID,
+	}
+}
+
+// ServiceRegistration produces a random ServiceRegistration message
+func (g *Generator) ServiceRegistration() *wrp.ServiceRegistration {
+	m := g.Message(wrp.ServiceRegistrationMessageType)
+	return &wrp.ServiceRegistration{
+		Type:        m.Type,
+		ServiceName: m.ServiceName,
+		URL:         m.URL,
+	}
+}
@@ -0,0 +1,102 @@
+package wrptest
+
+import (
+	"testing"
+
+	"github.com/Comcast/webpa-common/wrp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testGeneratorMessage(t *testing.T, mt wrp.MessageType) {
+	var (
+		assert    = assert.New(t)
+		require   = require.New(t)
+		generator = NewGenerator(1234)
+		validator = wrp.DefaultValidator()
+	)
+
+	for repeat := 0; repeat < 50; repeat++ {
+		m := generator.Message(mt)
+		require.NotNil(m)
+		assert.Equal(mt, m.Type)
+		assert.NoError(validator.Validate(m))
+
+		for _, f := range wrp.AllFormats() {
+			var decoded wrp.Message
+			require.NoError(wrp.NewDecoderBytes(wrp.MustEncode(m, f), f).Decode(&decoded))
+			AssertMessage(t, m, &decoded, f.String())
+		}
+	}
+}
+
+func testGeneratorDeterministic(t *testing.T) {
+	var (
+		assert = assert.New(t)
+		first  = NewGenerator(5678)
+		second = NewGenerator(5678)
+	)
+
+	for repeat := 0; repeat < 20; repeat++ {
+		assert.Equal(first.AnyMessage(), second.AnyMessage())
+	}
+
+	assert.NotEqual(NewGenerator(1).Message(wrp.SimpleEventMessageType), NewGenerator(2).Message(wrp.SimpleEventMessageType))
+}
+
+func testGeneratorTyped(t *testing.T) {
+	var (
+		assert    = assert.New(t)
+		require   = require.New(t)
+		generator = NewGenerator(91011)
+		validator = wrp.DefaultValidator()
+
+		values = []interface{}{
+			generator.Authorization(),
+			generator.SimpleRequestResponse(),
+			generator.SimpleEvent(),
+			generator.CRUD(wrp.CreateMessageType),
+			generator.CRUD(wrp.RetrieveMessageType),
+			generator.CRUD(wrp.UpdateMessageType),
+			generator.CRUD(wrp.DeleteMessageType),
+			generator.ServiceRegistration(),
+		}
+	)
+
+	for _, value := range values {
+		var m wrp.Message
+		require.NoError(wrp.NewDecoderBytes(wrp.MustEncode(value, wrp.Msgpack), wrp.Msgpack).Decode(&m))
+		assert.NoError(validator.Validate(&m), "%#v", value)
+	}
+
+	assert.Panics(func() { generator.CRUD(wrp.SimpleEventMessageType) })
+	assert.Panics(func() { generator.Message(wrp.MessageType(999)) })
+}
+
+func testGeneratorLocators(t *testing.T) {
+	var (
+		assert    = assert.New(t)
+		generator = NewGenerator(1213)
+	)
+
+	for _, locator := range []string{generator.MAC(), generator.DeviceLocator(), generator.ServerLocator()} {
+		_, err := wrp.ParseLocator(locator)
+		assert.NoError(err, locator)
+	}
+
+	assert.Regexp(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[0-9a-f]{4}-[0-9a-f]{12}$`, generator.UUID())
+}
+
+func TestGenerator(t *testing.T) {
+	t.Run("Message", func(t *testing.T) {
+		for _, mt := range MessageTypes() {
+			t.Run(mt.FriendlyName(), func(t *testing.T) {
+				testGeneratorMessage(t, mt)
+			})
+		}
+	})
+
+	t.Run("Deterministic", testGeneratorDeterministic)
+	t.Run("Typed", testGeneratorTyped)
+	t.Run("Locators", testGeneratorLocators)
+}
@@ -0,0 +1,83 @@
+package wrptest
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/Comcast/webpa-common/wrp"
+	"github.com/stretchr/testify/assert"
+)
+
+// UpdateGoldenEnv is the environment variable that controls whether AssertGolden rewrites golden files rather
+// than comparing against them, e.g. WRPTEST_UPDATE=true go test ./...
+const UpdateGoldenEnv = "WRPTEST_UPDATE"
+
+// UpdateGolden tests if the UpdateGoldenEnv environment variable is set to a true value
+func UpdateGolden() bool {
+	update, _ := strconv.ParseBool(os.Getenv(UpdateGoldenEnv))
+	return update
+}
+
+// GoldenPath returns the conventional location of a golden file for the given name and format, e.g.
+// testdata/event.msgpack
+func GoldenPath(name string, f wrp.Format) string {
+	return filepath.Join("testdata", name+"."+strings.ToLower(f.String()))
+}
+
+// WriteGolden encodes a value in the given format and writes it to path, creating any missing directories
+func WriteGolden(path string, f wrp.Format, value interface{}) error {
+	encoded, err := wrp.DefaultEncoderPool(f).EncodeBytes(value)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(path, encoded, 0644)
+}
+
+// ReadGolden decodes the message in a golden file
+func ReadGolden(path string, f wrp.Format) (*wrp.Message, error) {
+	encoded, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	m := new(wrp.Message)
+	if err := wrp.DefaultDecoderPool(f).DecodeBytes(m, encoded); err != nil {
+		return nil, err
+	}
+
+	return m, nil
+}
+
+// AssertGolden asserts that the encoded form of value matches the golden file at path.  Rather than comparing
+// bytes, which can vary with map ordering, both forms are decoded and compared as messages.  If UpdateGolden
+// returns true, the golden file is rewritten instead.
+func AssertGolden(t assert.TestingT, path string, f wrp.Format, value interface{}, msgAndArgs ...interface{}) bool {
+	if UpdateGolden() {
+		return assert.NoError(t, WriteGolden(path, f, value), msgAndArgs...)
+	}
+
+	expected, err := ReadGolden(path, f)
+	if !assert.NoError(t, err, msgAndArgs...) {
+		return false
+	}
+
+	encoded, err := wrp.DefaultEncoderPool(f).EncodeBytes(value)
+	if !assert.NoError(t, err, msgAndArgs...) {
+		return false
+	}
+
+	actual := new(wrp.Message)
+	if !assert.NoError(t, wrp.DefaultDecoderPool(f).DecodeBytes(actual, encoded), msgAndArgs...) {
+		return false
+	}
+
+	return AssertMessage(t, expected, actual, msgAndArgs...)
+}
@@ -0,0 +1,121 @@
+package wrptest
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Comcast/webpa-common/wrp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// goldenMessage is the message stored in this package's testdata golden files
+var goldenMessage = wrp.SimpleRequestResponse{
+	Source:          "dns:talaria.example.com/api",
+	Destination:     "mac:112233445566/config",
+	TransactionUUID: "c2bb1f16-09c8-4a9b-a4d8-8d3b4b1d4a6f",
+	ContentType:     "application/json",
+	Metadata:        map[string]string{"/boot-time": "1542834188", "/trust": "1000"},
+	PartnerIDs:      []string{"comcast"},
+	Payload:         []byte(`{"names": ["Device.DeviceInfo.SerialNumber"]}`),
+}
+
+func testGoldenPath(t *testing.T) {
+	assert := assert.New(t)
+	assert.Equal(filepath.Join("testdata", "event.msgpack"), GoldenPath("event", wrp.Msgpack))
+	assert.Equal(filepath.Join("testdata", "event.json"), GoldenPath("event", wrp.JSON))
+}
+
+func testGoldenFiles(t *testing.T, f wrp.Format) {
+	message := goldenMessage
+	AssertGolden(t, GoldenPath("simpleRequestResponse", f), f, &message)
+}
+
+func testGoldenWriteRead(t *testing.T) {
+	var (
+		assert  = assert.New(t)
+		require = require.New(t)
+	)
+
+	dir, err := ioutil.TempDir("", "wrptest")
+	require.NoError(err)
+	defer os.RemoveAll(dir)
+
+	for _, f := range wrp.AllFormats() {
+		var (
+			path     = filepath.Join(dir, "nested", "golden."+f.String())
+			message  = goldenMessage
+			recorder = new(recordingT)
+		)
+
+		require.NoError(WriteGolden(path, f, &message))
+		actual, err := ReadGolden(path, f)
+		require.NoError(err)
+		assert.Equal(wrp.SimpleRequestResponseMessageType, actual.Type)
+		assert.Equal(goldenMessage.Payload, actual.Payload)
+
+		assert.True(AssertGolden(recorder, path, f, &message))
+		assert.Empty(recorder.failures)
+
+		message.TransactionUUID = "different"
+		assert.False(AssertGolden(recorder, path, f, &message))
+		assert.Len(recorder.failures, 1)
+
+		assert.False(AssertGolden(recorder, filepath.Join(dir, "missing"), f, &message))
+		assert.Len(recorder.failures, 2)
+	}
+}
+
+func testGoldenUpdate(t *testing.T) {
+	var (
+		assert  = assert.New(t)
+		require = require.New(t)
+		message = goldenMessage
+	)
+
+	dir, err := ioutil.TempDir("", "wrptest")
+	require.NoError(err)
+	defer os.RemoveAll(dir)
+
+	original, wasSet := os.LookupEnv(UpdateGoldenEnv)
+	defer func() {
+		if wasSet {
+			os.Setenv(UpdateGoldenEnv, original)
+		} else {
+			os.Unsetenv(UpdateGoldenEnv)
+		}
+	}()
+
+	require.NoError(os.Setenv(UpdateGoldenEnv, "false"))
+	assert.False(UpdateGolden())
+
+	require.NoError(os.Setenv(UpdateGoldenEnv, "not a bool"))
+	assert.False(UpdateGolden())
+
+	require.NoError(os.Setenv(UpdateGoldenEnv, "true"))
+	assert.True(UpdateGolden())
+
+	for _, f := range wrp.AllFormats() {
+		path := filepath.Join(dir, "golden."+f.String())
+		assert.True(AssertGolden(t, path, f, &message))
+
+		actual, err := ReadGolden(path, f)
+		require.NoError(err)
+		assert.Equal(goldenMessage.TransactionUUID, actual.TransactionUUID)
+	}
+}
+
+func TestGolden(t *testing.T) {
+	t.Run("GoldenPath", testGoldenPath)
+	t.Run("WriteRead", testGoldenWriteRead)
+	t.Run("Update", testGoldenUpdate)
+	t.Run("Files", func(t *testing.T) {
+		for _, f := range wrp.AllFormats() {
+			t.Run(f.String(), func(t *testing.T) {
+				testGoldenFiles(t, f)
+			})
+		}
+	})
+}
@@ -0,0 +1,149 @@
+package wrptest
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/Comcast/webpa-common/wrp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// Normalize returns a copy of a message with empty slices and maps replaced by nil.  Messages that differ only
+// in this respect encode identically, so normalized messages can be compared with reflect.DeepEqual or assert.Equal.
+// A nil message normalizes to nil.
+func Normalize(m *wrp.Message) *wrp.Message {
+	if m == nil {
+		return nil
+	}
+
+	var (
+		normalized = *m
+		v          = reflect.ValueOf(&normalized).Elem()
+	)
+
+	for i := 0; i < v.NumField(); i++ {
+		field := v.Field(i)
+		switch field.Kind() {
+		case reflect.Slice, reflect.Map:
+			if !field.IsNil() && field.Len() == 0 {
+				field.Set(reflect.Zero(field.Type()))
+			}
+		}
+	}
+
+	return &normalized
+}
+
+// AssertMessage asserts that two messages are deeply equal, treating nil and empty slices and maps as equal
+func AssertMessage(t assert.TestingT, expected, actual *wrp.Message, msgAndArgs ...interface{}) bool {
+	return assert.Equal(t, Normalize(expected), Normalize(actual), msgAndArgs...)
+}
+
+// fieldName returns the WRP name of a field of wrp.Message
+func fieldName(f reflect.StructField) string {
+	name := strings.Split(f.Tag.Get("wrp"), ",")[0]
+	if name == "-" {
+		return f.Name
+	}
+
+	return name
+}
+
+// isSet tests if a field of a wrp.Message has a value that would be encoded
+func isSet(field reflect.Value) bool {
+	switch field.Kind() {
+	case reflect.Ptr:
+		return !field.IsNil()
+	case reflect.Slice, reflect.Map, reflect.String:
+		return field.Len() > 0
+	case reflect.Int, reflect.Int64:
+		return field.Int() != 0
+	default:
+		return false
+	}
+}
+
+// containsEntries tests if every entry of the expected map is present, with an equal value, in the actual map
+func containsEntries(expected, actual reflect.Value) bool {
+	for _, key := range expected.MapKeys() {
+		actualValue := actual.MapIndex(key)
+		if !actualValue.IsValid() || !reflect.DeepEqual(expected.MapIndex(key).Interface(), actualValue.Interface()) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Differences returns the names of the fields set on expected whose values differ in actual.  Fields that
+// are not set on expected are ignored, which allows for partial matches.  Maps, such as metadata, match if
+// every expected entry is present in actual.  The returned names are WRP field names, e.g. "dest".
+func Differences(expected, actual *wrp.Message) []string {
+	if expected == nil {
+		return nil
+	} else if actual == nil {
+		actual = new(wrp.Message)
+	}
+
+	var (
+		differences []string
+		e           = reflect.ValueOf(expected).Elem()
+		a           = reflect.ValueOf(actual).Elem()
+		t           = e.Type()
+	)
+
+	for i := 0; i < t.NumField(); i++ {
+		expectedField, actualField := e.Field(i), a.Field(i)
+		if !isSet(expectedField) {
+			continue
+		}
+
+		var equal bool
+		if expectedField.Kind() == reflect.Map {
+			equal = containsEntries(expectedField, actualField)
+		} else {
+			equal = reflect.DeepEqual(expectedField.Interface(), actualField.Interface())
+		}
+
+		if !equal {
+			differences = append(differences, fieldName(t.Field(i)))
+		}
+	}
+
+	return differences
+}
+
+// Matches returns a predicate that tests whether a message partially matches expected, as with Differences
+func Matches(expected *wrp.Message) func(*wrp.Message) bool {
+	return func(actual *wrp.Message) bool {
+		return len(Differences(expected, actual)) == 0
+	}
+}
+
+// MatchedBy produces a stretchr mock argument matcher for messages that partially match expected.
+// This is useful when the exact message passed to a mock, such as its transaction key, is not known in advance.
+func MatchedBy(expected *wrp.Message) interface{} {
+	return mock.MatchedBy(Matches(expected))
+}
+
+// AssertMatches asserts that actual partially matches expected, as with Differences.  The failure message
+// identifies each field that did not match.
+func AssertMatches(t assert.TestingT, expected, actual *wrp.Message, msgAndArgs ...interface{}) bool {
+	differences := Differences(expected, actual)
+	if len(differences) == 0 {
+		return true
+	}
+
+	return assert.Fail(
+		t,
+		fmt.Sprintf(
+			"Message does not match on fields %s\nexpected: %#v\nactual  : %#v",
+			strings.Join(differences, ", "),
+			expected,
+			actual,
+		),
+		msgAndArgs...,
+	)
+}
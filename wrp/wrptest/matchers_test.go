@@ -0,0 +1,143 @@
+package wrptest
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/Comcast/webpa-common/wrp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// recordingT is an assert.TestingT that records failures rather than failing the enclosing test
+type recordingT struct {
+	failures []string
+}
+
+func (r *recordingT) Errorf(format string, arguments ...interface{}) {
+	r.failures = append(r.failures, fmt.Sprintf(format, arguments...))
+}
+
+func testNormalize(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Nil(Normalize(nil))
+
+	original := &wrp.Message{
+		Type:          wrp.SimpleEventMessageType,
+		Headers:       []string{},
+		Metadata:      map[string]string{},
+		Payload:       []byte{},
+		PartnerIDs:    []string{"comcast"},
+		UnknownFields: map[string]interface{}{},
+	}
+
+	normalized := Normalize(original)
+	assert.Equal(&wrp.Message{Type: wrp.SimpleEventMessageType, PartnerIDs: []string{"comcast"}}, normalized)
+
+	// the original is not modified
+	assert.NotNil(original.Headers)
+	assert.NotNil(original.Payload)
+}
+
+func testAssertMessage(t *testing.T) {
+	var (
+		assert   = assert.New(t)
+		recorder = new(recordingT)
+	)
+
+	assert.True(AssertMessage(recorder, &wrp.Message{Payload: []byte{}}, &wrp.Message{}))
+	assert.Empty(recorder.failures)
+
+	assert.False(AssertMessage(recorder, &wrp.Message{Source: "a"}, &wrp.Message{Source: "b"}))
+	assert.Len(recorder.failures, 1)
+}
+
+func testDifferences(t *testing.T) {
+	var (
+		assert = assert.New(t)
+		actual = &wrp.Message{
+			Type:            wrp.SimpleRequestResponseMessageType,
+			Source:          "dns:talaria.example.com",
+			Destination:     "mac:112233445566/config",
+			TransactionUUID: "1234",
+			Metadata:        map[string]string{"a": "1", "b": "2"},
+			Payload:         []byte("payload"),
+		}
+	)
+
+	assert.Empty(Differences(nil, actual))
+	assert.Empty(Differences(&wrp.Message{}, actual))
+	assert.Empty(Differences(actual, actual))
+	assert.Empty(Differences(&wrp.Message{Destination: "mac:112233445566/config", Metadata: map[string]string{"b": "2"}}, actual))
+
+	assert.Equal(
+		[]string{"msg_type", "dest", "metadata", "payload"},
+		Differences(
+			&wrp.Message{
+				Type:        wrp.SimpleEventMessageType,
+				Destination: "event:foo",
+				Metadata:    map[string]string{"c": "3"},
+				Payload:     []byte("different"),
+			},
+			actual,
+		),
+	)
+
+	assert.Equal([]string{"source"}, Differences(&wrp.Message{Source: "dns:foo.com"}, nil))
+	assert.Equal(
+		[]string{"UnknownFields"},
+		Differences(&wrp.Message{UnknownFields: map[string]interface{}{"future": 1}}, actual),
+	)
+}
+
+func testAssertMatches(t *testing.T) {
+	var (
+		assert   = assert.New(t)
+		recorder = new(recordingT)
+		actual   = &wrp.Message{Source: "dns:talaria.example.com", Destination: "mac:112233445566"}
+	)
+
+	assert.True(AssertMatches(recorder, &wrp.Message{Source: "dns:talaria.example.com"}, actual))
+	assert.Empty(recorder.failures)
+
+	assert.False(AssertMatches(recorder, &wrp.Message{Source: "dns:other.example.com"}, actual))
+	if assert.Len(recorder.failures, 1) {
+		assert.Contains(recorder.failures[0], "fields source")
+	}
+
+	assert.True(Matches(&wrp.Message{Destination: "mac:112233445566"})(actual))
+	assert.False(Matches(&wrp.Message{Destination: "mac:665544332211"})(actual))
+}
+
+// messageSink is a mock used to verify that MatchedBy works as a stretchr argument matcher
+type messageSink struct {
+	mock.Mock
+}
+
+func (ms *messageSink) Send(m *wrp.Message) {
+	ms.Called(m)
+}
+
+func testMatchedBy(t *testing.T) {
+	var (
+		assert = assert.New(t)
+		sink   = new(messageSink)
+	)
+
+	sink.On("Send", MatchedBy(&wrp.Message{Destination: "mac:112233445566/config"})).Once()
+	sink.Send(&wrp.Message{Source: "dns:talaria.example.com", Destination: "mac:112233445566/config", TransactionUUID: "random"})
+	sink.AssertExpectations(t)
+
+	assert.Panics(func() {
+		sink.Send(&wrp.Message{Destination: "mac:665544332211/config"})
+	})
+}
+
+func TestMatchers(t *testing.T) {
+	t.Run("Normalize", testNormalize)
+	t.Run("AssertMessage", testAssertMessage)
+	t.Run("Differences", testDifferences)
+	t.Run("AssertMatches", testAssertMatches)
+	t.Run("MatchedBy", testMatchedBy)
+}
@@ -0,0 +1,16 @@
+package converter
+
+import (
+	"reflect"
+	"time"
+)
+
+// Time parses an RFC3339 timestamp into a time.Time value
+func Time(v string) reflect.Value {
+	t, err := time.Parse(time.RFC3339, v)
+	if err != nil {
+		return reflect.Value{}
+	}
+
+	return reflect.ValueOf(t)
+}
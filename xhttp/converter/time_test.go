@@ -0,0 +1,46 @@
+package converter
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func testTimeValid(t *testing.T) {
+	testData := []struct {
+		value    string
+		expected reflect.Value
+	}{
+		{"2018-04-05T12:00:00Z", reflect.ValueOf(time.Date(2018, 4, 5, 12, 0, 0, 0, time.UTC))},
+		{"2019-11-30T23:15:07Z", reflect.ValueOf(time.Date(2019, 11, 30, 23, 15, 7, 0, time.UTC))},
+	}
+
+	for _, record := range testData {
+		t.Run(record.value, func(t *testing.T) {
+			assert.Equal(t, record.expected.Interface(), Time(record.value).Interface())
+		})
+	}
+}
+
+func testTimeInvalid(t *testing.T) {
+	testData := []struct {
+		value string
+	}{
+		{""},
+		{"asdf"},
+		{"2018-04-05"},
+	}
+
+	for _, record := range testData {
+		t.Run(record.value, func(t *testing.T) {
+			assert.False(t, Time(record.value).IsValid())
+		})
+	}
+}
+
+func TestTime(t *testing.T) {
+	t.Run("Valid", testTimeValid)
+	t.Run("Invalid", testTimeInvalid)
+}